@@ -0,0 +1,161 @@
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// bundle formats, by extension of the bundle file
+const (
+	zipBundleExtension    = ".zip"
+	tarZstBundleExtension = ".tar.zst"
+)
+
+// checkBundlePath checks the --bundle flag, whose file must be a .zip or a
+// .tar.zst out of the output folder it bundles.
+func checkBundlePath(bundlePath, outputFolderPath string) error {
+	if bundlePath == "" {
+		return nil
+	}
+	if !strings.HasSuffix(bundlePath, zipBundleExtension) && !strings.HasSuffix(bundlePath, tarZstBundleExtension) {
+		return fmt.Errorf("unknown bundle format of %s (%s or %s)", bundlePath, zipBundleExtension, tarZstBundleExtension)
+	}
+
+	absBundlePath, err := filepath.Abs(bundlePath)
+	if err != nil {
+		return err
+	}
+	absOutputFolderPath, err := filepath.Abs(outputFolderPath)
+	if err != nil {
+		return err
+	}
+	if relPath, err := filepath.Rel(absOutputFolderPath, absBundlePath); err == nil && relPath != ".." && !strings.HasPrefix(relPath, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("bundle %s must not be in the output folder", bundlePath)
+	}
+	return nil
+}
+
+// writeBundle writes the files of the output folder to a single archive, the
+// manifest first so that it can be read without going through the whole
+// archive. Nothing is written when bundlePath is empty.
+func writeBundle(outputFolderPath, bundlePath string) error {
+	if bundlePath == "" {
+		return nil
+	}
+
+	filePaths := []string{manifestFileName}
+	err := filepath.WalkDir(outputFolderPath, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		relPath, err := filepath.Rel(outputFolderPath, filePath)
+		if err != nil || relPath == manifestFileName {
+			return err
+		}
+		filePaths = append(filePaths, relPath)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error listing output files: %w", err)
+	}
+
+	bundleFile, err := os.Create(bundlePath)
+	if err != nil {
+		return fmt.Errorf("error creating bundle: %w", err)
+	}
+	defer bundleFile.Close()
+
+	if strings.HasSuffix(bundlePath, zipBundleExtension) {
+		err = writeZipBundle(bundleFile, outputFolderPath, filePaths)
+	} else {
+		err = writeTarZstBundle(bundleFile, outputFolderPath, filePaths)
+	}
+	if err == nil {
+		err = bundleFile.Close()
+	}
+	if err != nil {
+		os.Remove(bundlePath)
+		return fmt.Errorf("error writing bundle: %w", err)
+	}
+	return nil
+}
+
+func writeZipBundle(w io.Writer, outputFolderPath string, filePaths []string) error {
+	zipWriter := zip.NewWriter(w)
+	for _, filePath := range filePaths {
+		info, err := os.Stat(filepath.Join(outputFolderPath, filePath))
+		if err != nil {
+			return err
+		}
+		header, err := zip.FileInfoHeader(info)
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(filePath)
+		header.Method = zip.Deflate
+
+		entryWriter, err := zipWriter.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+		err = copyFile(entryWriter, filepath.Join(outputFolderPath, filePath))
+		if err != nil {
+			return err
+		}
+	}
+	return zipWriter.Close()
+}
+
+func writeTarZstBundle(w io.Writer, outputFolderPath string, filePaths []string) error {
+	zstdWriter, err := zstd.NewWriter(w)
+	if err != nil {
+		return err
+	}
+	defer zstdWriter.Close()
+
+	tarWriter := tar.NewWriter(zstdWriter)
+	for _, filePath := range filePaths {
+		info, err := os.Stat(filepath.Join(outputFolderPath, filePath))
+		if err != nil {
+			return err
+		}
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(filePath)
+
+		err = tarWriter.WriteHeader(header)
+		if err != nil {
+			return err
+		}
+		err = copyFile(tarWriter, filepath.Join(outputFolderPath, filePath))
+		if err != nil {
+			return err
+		}
+	}
+	err = tarWriter.Close()
+	if err != nil {
+		return err
+	}
+	return zstdWriter.Close()
+}
+
+func copyFile(w io.Writer, filePath string) error {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = io.Copy(w, file)
+	return err
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+)
+
+// autoDetectFlag is the --auto-detect flag of the commands taking a Dofus
+// data folder, which may then be left out of the arguments.
+type autoDetectFlag struct {
+	enabled bool
+	// argIndex is the position of the data folder in the arguments.
+	argIndex int
+}
+
+func (f *autoDetectFlag) String() string {
+	if f == nil {
+		return "false"
+	}
+	return fmt.Sprint(f.enabled)
+}
+
+func (f *autoDetectFlag) Set(value string) error {
+	switch value {
+	case "true":
+		f.enabled = true
+	case "false":
+		f.enabled = false
+	default:
+		return fmt.Errorf("invalid boolean value: %s", value)
+	}
+	return nil
+}
+
+func (f *autoDetectFlag) IsBoolFlag() bool {
+	return true
+}
+
+// detectDofusDataFolder returns the data folder of the installed game, looked
+// for where the Ankama Launcher and Steam install it.
+func detectDofusDataFolder() (string, error) {
+	installationPaths := dofusInstallationPaths()
+	for _, installationPath := range installationPaths {
+		dataFolderPath := filepath.Join(installationPath, "data")
+		if checkDofusDataFolder(dataFolderPath) == nil {
+			return dataFolderPath, nil
+		}
+	}
+	return "", fmt.Errorf("no Dofus installation found in %s", strings.Join(installationPaths, ", "))
+}
+
+// dofusInstallationPaths returns the folders the game may be installed to, the
+// most likely first.
+func dofusInstallationPaths() []string {
+	paths := make([]string, 0)
+
+	// the launcher records where it installed each release of the games
+	if configFolderPath, err := os.UserConfigDir(); err == nil {
+		releaseFilePath := filepath.Join(configFolderPath, "zaap", "repositories", "production", "dofus", "main", "release.json")
+		if location := readLauncherLocation(releaseFilePath); location != "" {
+			paths = append(paths, location)
+		}
+	}
+
+	homeFolderPath, _ := os.UserHomeDir()
+	var steamFolderPaths []string
+	switch runtime.GOOS {
+	case "windows":
+		localAppData := os.Getenv("LOCALAPPDATA")
+		paths = append(paths,
+			filepath.Join(localAppData, "Ankama", "Dofus"),
+			filepath.Join(os.Getenv("APPDATA"), "zaap", "dofus"),
+		)
+		steamFolderPaths = []string{
+			filepath.Join(os.Getenv("ProgramFiles(x86)"), "Steam"),
+			filepath.Join(os.Getenv("ProgramFiles"), "Steam"),
+		}
+	case "darwin":
+		paths = append(paths,
+			"/Applications/Dofus.app/Contents/Resources",
+			filepath.Join(homeFolderPath, "Library", "Application Support", "zaap", "dofus"),
+		)
+		steamFolderPaths = []string{filepath.Join(homeFolderPath, "Library", "Application Support", "Steam")}
+	default:
+		paths = append(paths,
+			filepath.Join(homeFolderPath, "Ankama", "Dofus"),
+			filepath.Join(homeFolderPath, ".config", "zaap", "dofus"),
+		)
+		steamFolderPaths = []string{
+			filepath.Join(homeFolderPath, ".steam", "steam"),
+			filepath.Join(homeFolderPath, ".local", "share", "Steam"),
+			filepath.Join(homeFolderPath, ".var", "app", "com.valvesoftware.Steam", ".local", "share", "Steam"),
+		}
+	}
+
+	for _, steamFolderPath := range steamFolderPaths {
+		for _, libraryFolderPath := range readSteamLibraries(steamFolderPath) {
+			paths = append(paths, filepath.Join(libraryFolderPath, "steamapps", "common", "Dofus"))
+		}
+	}
+	return paths
+}
+
+// readLauncherLocation returns the installation folder of a release file of
+// the launcher, if any.
+func readLauncherLocation(releaseFilePath string) string {
+	content, err := os.ReadFile(releaseFilePath)
+	if err != nil {
+		return ""
+	}
+	var release struct {
+		Location string `json:"location"`
+	}
+	if json.Unmarshal(content, &release) != nil {
+		return ""
+	}
+	return release.Location
+}
+
+var steamLibraryPathRegexp = regexp.MustCompile(`"path"\s+"([^"]+)"`)
+
+// readSteamLibraries returns the Steam folder and the other libraries listed
+// in its libraryfolders.vdf, where games may also be installed.
+func readSteamLibraries(steamFolderPath string) []string {
+	libraries := []string{steamFolderPath}
+	content, err := os.ReadFile(filepath.Join(steamFolderPath, "steamapps", "libraryfolders.vdf"))
+	if err != nil {
+		return libraries
+	}
+	for _, match := range steamLibraryPathRegexp.FindAllStringSubmatch(string(content), -1) {
+		// backslashes are escaped in the Windows paths
+		libraryFolderPath := strings.ReplaceAll(match[1], `\\`, `\`)
+		if libraryFolderPath != steamFolderPath {
+			libraries = append(libraries, libraryFolderPath)
+		}
+	}
+	return libraries
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/brequet/dofus-data-file-parser/pkg/diff"
+)
+
+func runDiffCommand(_ context.Context, args []string) error {
+	flagSet, debug := newFlagSet("diff", "oldDofusDataFolderPath newDofusDataFolderPath")
+	outputPath := flagSet.String("output", "", "write the changelog to this file instead of the standard output")
+	schema := flagSet.Bool("schema", false, "compare the class definitions instead of the objects and texts")
+	format := flagSet.String("format", "json", "format of the changelog (json, or text with --schema)")
+	err := parseFlags(flagSet, debug, args, 2)
+	if err != nil {
+		return err
+	}
+
+	if *format != "json" && (*format != "text" || !*schema) {
+		flagSet.Usage()
+		return errUsage
+	}
+
+	for _, dofusDataFolderPath := range flagSet.Args() {
+		err = checkDofusDataFolder(dofusDataFolderPath)
+		if err != nil {
+			return fmt.Errorf("error with provided dofus data folder: %w", err)
+		}
+	}
+
+	var changelog any
+	if *schema {
+		schemaChanges, err := diff.CompareSchemas(flagSet.Arg(0), flagSet.Arg(1))
+		if err != nil {
+			return err
+		}
+		if *format == "text" {
+			return writeText(schemaChanges.Text(), *outputPath)
+		}
+		changelog = schemaChanges
+	} else {
+		changelog, err = diff.CompareDataFolders(flagSet.Arg(0), flagSet.Arg(1))
+		if err != nil {
+			return err
+		}
+	}
+
+	if *outputPath != "" {
+		return writeJSONFile(changelog, *outputPath)
+	}
+	return printJSON(changelog)
+}
+
+// writeText writes text to a file, or to the standard output when no path is
+// given.
+func writeText(text, outputPath string) error {
+	if outputPath == "" {
+		_, err := os.Stdout.WriteString(text)
+		return err
+	}
+	return os.WriteFile(outputPath, []byte(text), 0644)
+}
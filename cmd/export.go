@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/brequet/dofus-data-file-parser/pkg/dataset"
+)
+
+// exportModes are the datasets the export command builds, by name.
+var exportModes = map[string]func(d *dataset.Dataset) ([]any, error){
+	"encyclopedia": func(d *dataset.Dataset) ([]any, error) {
+		return documents(dataset.BuildEncyclopedia(d))
+	},
+	"item-drops": func(d *dataset.Dataset) ([]any, error) {
+		return documents(dataset.BuildItemDrops(d))
+	},
+	"monster-drops": func(d *dataset.Dataset) ([]any, error) {
+		return documents(dataset.BuildMonsterDrops(d))
+	},
+	"recipes": func(d *dataset.Dataset) ([]any, error) {
+		return documents(dataset.BuildRecipes(d))
+	},
+	"world": func(d *dataset.Dataset) ([]any, error) {
+		return documents(dataset.BuildWorld(d))
+	},
+}
+
+func runExportCommand(_ context.Context, args []string) error {
+	modes := make([]string, 0, len(exportModes))
+	for mode := range exportModes {
+		modes = append(modes, mode)
+	}
+	slices.Sort(modes)
+
+	flagSet, debug := newFlagSet("export", fmt.Sprintf("<%s> dofusDataFolderPath outputFilePath", strings.Join(modes, "|")))
+	locale := flagSet.String("locale", "en", "locale of the texts of the dataset")
+	outputFormat := flagSet.String("output-format", "json", "format of the dataset (json or ndjson, one document per line)")
+	err := parseFlags(flagSet, debug, args, 3)
+	if err != nil {
+		return err
+	}
+
+	build, ok := exportModes[flagSet.Arg(0)]
+	if !ok || (*outputFormat != "json" && *outputFormat != "ndjson") {
+		flagSet.Usage()
+		return errUsage
+	}
+
+	dofusDataFolderPath := flagSet.Arg(1)
+	err = checkDofusDataFolder(dofusDataFolderPath)
+	if err != nil {
+		return fmt.Errorf("error with provided dofus data folder: %w", err)
+	}
+
+	d, err := dataset.Open(dofusDataFolderPath, *locale, parseOptions())
+	if err != nil {
+		return err
+	}
+
+	docs, err := build(d)
+	if err != nil {
+		return fmt.Errorf("error building %s dataset: %w", flagSet.Arg(0), err)
+	}
+
+	if *outputFormat == "ndjson" {
+		return writeNDJSONFile(docs, flagSet.Arg(2))
+	}
+	return writeJSONFile(docs, flagSet.Arg(2))
+}
+
+// documents converts the documents of a dataset for writing them.
+func documents[T any](values []T, err error) ([]any, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	docs := make([]any, 0, len(values))
+	for _, value := range values {
+		docs = append(docs, value)
+	}
+	return docs, nil
+}
+
+func writeNDJSONFile(values []any, outputPath string) error {
+	outputFile, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("error creating file: %w", err)
+	}
+	defer outputFile.Close()
+
+	writer := bufio.NewWriter(outputFile)
+	encoder := json.NewEncoder(writer)
+	for _, value := range values {
+		err = encoder.Encode(value)
+		if err != nil {
+			return fmt.Errorf("error encoding json: %w", err)
+		}
+	}
+
+	return writer.Flush()
+}
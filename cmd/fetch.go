@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/brequet/dofus-data-file-parser/pkg/cytrus"
+)
+
+func runFetchCommand(ctx context.Context, args []string) error {
+	flagSet, debug := newFlagSet("fetch", "downloadFolderPath outputFolderPath")
+	game := flagSet.String("game", "dofus", "game whose files are downloaded")
+	release := flagSet.String("release", "main", "release of the game (main or beta)")
+	platform := flagSet.String("platform", defaultCytrusPlatform(), "platform of the game (windows, darwin or linux)")
+	version := flagSet.String("version", "", "version of the game, the current one of the release when empty")
+	baseURL := flagSet.String("cytrus-url", cytrus.DefaultBaseURL, "URL of the Cytrus CDN")
+	err := parseFlags(flagSet, debug, args, 2)
+	if err != nil {
+		return err
+	}
+	downloadFolderPath, outputFolderPath := flagSet.Arg(0), flagSet.Arg(1)
+
+	client := cytrus.Client{BaseURL: *baseURL}
+	if *version == "" {
+		versions, err := client.Versions(ctx)
+		if err != nil {
+			return fmt.Errorf("error fetching versions: %w", err)
+		}
+		*version = versions[*game][*platform][*release]
+		if *version == "" {
+			return fmt.Errorf("no %s release of %s for %s", *release, *game, *platform)
+		}
+	}
+
+	slog.Info("fetching manifest", "game", *game, "release", *release, "platform", *platform, "version", *version)
+	manifest, err := client.Manifest(ctx, *game, *release, *platform, *version)
+	if err != nil {
+		return err
+	}
+
+	files := cytrus.FilesWithPrefix(manifest, "data/common/", "data/i18n/")
+	if len(files) == 0 {
+		return fmt.Errorf("no data files in version %s", *version)
+	}
+	slog.Info("downloading files", "count", len(files), "folder", downloadFolderPath)
+	err = client.Download(ctx, *game, manifest, files, downloadFolderPath)
+	if err != nil {
+		return fmt.Errorf("error downloading files: %w", err)
+	}
+	// recorded in the manifest of the output
+	err = os.WriteFile(filepath.Join(downloadFolderPath, gameVersionFileName), []byte(*version+"\n"), 0644)
+	if err != nil {
+		return fmt.Errorf("error writing version: %w", err)
+	}
+
+	parseArgs := []string{filepath.Join(downloadFolderPath, "data"), outputFolderPath}
+	if *debug {
+		parseArgs = append([]string{"--debug"}, parseArgs...)
+	}
+	if strictParsing {
+		parseArgs = append([]string{"--strict"}, parseArgs...)
+	}
+	return runParseCommand(ctx, parseArgs)
+}
+
+// defaultCytrusPlatform returns the Cytrus platform of the running system.
+func defaultCytrusPlatform() string {
+	switch runtime.GOOS {
+	case "darwin", "linux":
+		return runtime.GOOS
+	default:
+		return "windows"
+	}
+}
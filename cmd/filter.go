@@ -0,0 +1,78 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// moduleFilter selects modules by file name, e.g. "Items.d2o", or by module
+// name, e.g. "Items", using comma separated glob patterns.
+type moduleFilter struct {
+	include []string // all modules when empty
+	exclude []string
+}
+
+func addModuleFilterFlags(flagSet *flag.FlagSet) (include, exclude *string) {
+	include = flagSet.String("include", "", "only parse the modules matching these comma separated globs (e.g. 'Items*,Spells*')")
+	exclude = flagSet.String("exclude", "", "skip the modules matching these comma separated globs")
+	return include, exclude
+}
+
+func newModuleFilter(include, exclude string) (moduleFilter, error) {
+	filter := moduleFilter{
+		include: splitPatterns(include),
+		exclude: splitPatterns(exclude),
+	}
+
+	for _, pattern := range append(filter.include, filter.exclude...) {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return moduleFilter{}, fmt.Errorf("invalid module pattern %q: %w", pattern, err)
+		}
+	}
+
+	return filter, nil
+}
+
+func (f moduleFilter) matches(fileName string) bool {
+	if len(f.include) > 0 && !matchesAny(f.include, fileName) {
+		return false
+	}
+	return !matchesAny(f.exclude, fileName)
+}
+
+// filter returns the file names matching the filter.
+func (f moduleFilter) filter(fileNames []string) []string {
+	filtered := make([]string, 0, len(fileNames))
+	for _, fileName := range fileNames {
+		if f.matches(fileName) {
+			filtered = append(filtered, fileName)
+		}
+	}
+	return filtered
+}
+
+func matchesAny(patterns []string, fileName string) bool {
+	moduleName := strings.TrimSuffix(fileName, filepath.Ext(fileName))
+	for _, pattern := range patterns {
+		if matched, _ := filepath.Match(pattern, fileName); matched {
+			return true
+		}
+		if matched, _ := filepath.Match(pattern, moduleName); matched {
+			return true
+		}
+	}
+	return false
+}
+
+func splitPatterns(patterns string) []string {
+	result := make([]string, 0)
+	for _, pattern := range strings.Split(patterns, ",") {
+		pattern = strings.TrimSpace(pattern)
+		if pattern != "" {
+			result = append(result, pattern)
+		}
+	}
+	return result
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/brequet/dofus-data-file-parser/pkg/exporter"
+	"github.com/brequet/dofus-data-file-parser/pkg/generator"
+	"github.com/brequet/dofus-data-file-parser/pkg/parser"
+)
+
+// goEmbedLang is the --lang of the Go packages embedding the objects of the
+// modules, written by the go-embed exporter rather than the types exporter.
+const goEmbedLang = "go-embed"
+
+func runGenCommand(ctx context.Context, args []string) error {
+	flagSet, debug := newFlagSet("gen", "dofusDataFolderPath outputFolderPath")
+	lang := flagSet.String("lang", "go", "language of the generated class types (go, ts, zod, python, pydantic, kotlin, rust or csharp), docs for their Markdown documentation, dot or mermaid for the graph of their references, or go-embed for Go packages embedding the objects of each module")
+	enums := flagSet.String("enums", "", "comma separated modules to also generate Go id constants for (e.g. 'Breeds,ItemTypes')")
+	enumLocale := flagSet.String("enum-locale", "en", "locale of the texts naming the enum constants")
+	goPackage := flagSet.String("go-package", "types", "package of the generated Go files")
+	goBuildTags := flagSet.String("go-build-tags", "", "build constraint of the generated Go files (e.g. 'dofus && !retro')")
+	goHeader := flagSet.String("go-header", "", "comment written at the top of the generated Go files, such as a licence")
+	goNullableNumbers := flagSet.Bool("go-nullable-numbers", false, "generate Go Number fields as *float64, for null (NaN) numbers to stay null")
+	goTagCase := flagSet.String("go-tag-case", "", "casing of the Go struct tag names (camel or snake), the field names when empty")
+	goOmitEmpty := flagSet.Bool("go-omitempty", false, "add omitempty to the Go struct tags")
+	goTags := flagSet.String("go-tags", "", "comma separated struct tags to write along with the json one (e.g. 'db,bson')")
+	templateDir := flagSet.String("template-dir", "", "folder of templates replacing the default ones of the class files, named after the language (e.g. go.tmpl)")
+	gameVersion := flagSet.String("game-version", "", "game version the Go files are generated from, added to their header")
+	failFast := flagSet.Bool("fail-fast", false, "stop at the first file which fails to be processed")
+	include, exclude := addModuleFilterFlags(flagSet)
+	err := parseFlags(flagSet, debug, args, 2)
+	if err != nil {
+		return err
+	}
+
+	filter, err := newModuleFilter(*include, *exclude)
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(exporter.TypesLanguages, *lang) && *lang != goEmbedLang || (*goTagCase != "" && *goTagCase != "camel" && *goTagCase != "snake") {
+		flagSet.Usage()
+		return errUsage
+	}
+
+	commonFolderPath := filepath.Join(flagSet.Arg(0), "common")
+	outputFolderPath := flagSet.Arg(1)
+
+	err = checkFolderExists(commonFolderPath)
+	if err != nil {
+		return fmt.Errorf("error with provided dofus data folder: %w", err)
+	}
+
+	goOptions := newGoOptions(*goPackage, *goBuildTags, *goHeader, *gameVersion)
+	goOptions.NullableNumbers = *goNullableNumbers
+	goOptions.TagCase = *goTagCase
+	goOptions.OmitEmpty = *goOmitEmpty
+	goOptions.ExtraTags = splitPatterns(*goTags)
+
+	// the types exporter only reads class tables, objects are not decoded but
+	// for the go-embed packages
+	var genExporter exporter.Exporter
+	if *lang == goEmbedLang {
+		if *goTagCase != "" {
+			slog.Warn("--go-tag-case is ignored with go-embed, the struct tags being the names of the embedded fields")
+		}
+		goOptions.Templates = generator.Templates{Dir: *templateDir}
+		embedExporter := exporter.NewGoEmbedExporter(filepath.Join(outputFolderPath, goEmbedLang))
+		embedExporter.SetGoOptions(goOptions)
+		genExporter = embedExporter
+	} else {
+		typesExporter, err := exporter.NewTypesExporter(*lang, outputFolderPath)
+		if err != nil {
+			return err
+		}
+		typesExporter.SetGoOptions(goOptions)
+		typesExporter.SetTemplates(generator.Templates{Dir: *templateDir})
+		genExporter = typesExporter
+	}
+
+	report := newFailureReport(*failFast)
+	err = processCommonFolder(ctx, commonFolderPath, commonFolderOptions{
+		workers:   1,
+		exporters: []exporter.Exporter{genExporter},
+		filter:    filter,
+		report:    report,
+	})
+	if err != nil {
+		return err
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	err = report.summarize()
+	if err != nil {
+		return err
+	}
+
+	if *lang != "go" || *enums == "" {
+		return nil
+	}
+	return exportEnumsToGolang(flagSet.Arg(0), splitPatterns(*enums), *enumLocale, outputFolderPath, goOptions)
+}
+
+// newGoOptions returns the options of the generated Go files, the game
+// version being appended to the header.
+func newGoOptions(packageName, buildTags, header, gameVersion string) generator.GoOptions {
+	if gameVersion != "" {
+		if header != "" {
+			header += "\n\n"
+		}
+		header += "Generated from the data of Dofus " + gameVersion + "."
+	}
+
+	return generator.GoOptions{
+		PackageName: packageName,
+		BuildTags:   buildTags,
+		Header:      header,
+	}
+}
+
+// exportEnumsToGolang writes the id constants of each module to a
+// <module>_enum.go file.
+func exportEnumsToGolang(dofusDataFolderPath string, moduleNames []string, locale, outputFolderPath string, goOptions generator.GoOptions) error {
+	translations, err := parser.ProcessD2iFile(filepath.Join(dofusDataFolderPath, "i18n", getD2iFileNameFromLocale(locale)), parseOptions())
+	if err != nil {
+		slog.Warn("error loading translations, enum constants are named from untranslated fields only", "error", err, "locale", locale)
+	}
+
+	for _, moduleName := range moduleNames {
+		data, err := parser.ProcessD2oFile(filepath.Join(dofusDataFolderPath, "common", moduleName+".d2o"), parseOptions())
+		if err != nil {
+			return fmt.Errorf("error parsing module %s: %w", moduleName, err)
+		}
+
+		goFileContent, err := generator.GenerateGoEnumFromObjects(moduleName, data, translations, goOptions)
+		if err != nil {
+			return fmt.Errorf("error generating golang enum of %s: %w", moduleName, err)
+		}
+
+		goFilePath := filepath.Join(outputFolderPath, "go", strings.ToLower(moduleName)+"_enum.go")
+		err = os.WriteFile(goFilePath, goFileContent, 0644)
+		if err != nil {
+			return fmt.Errorf("error writing file: %w", err)
+		}
+	}
+
+	return nil
+}
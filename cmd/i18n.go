@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/brequet/dofus-data-file-parser/pkg/exporter"
+	"github.com/brequet/dofus-data-file-parser/pkg/parser"
+)
+
+func runI18nCommand(ctx context.Context, args []string) error {
+	flagSet, debug := newFlagSet("i18n", "dofusDataFolderPath outputFolderPath")
+	sqlitePath := flagSet.String("sqlite", "", "also export translations to this SQLite database")
+	workers := flagSet.Int("workers", 1, "number of files parsed concurrently")
+	failFast := flagSet.Bool("fail-fast", false, "stop at the first file which fails to be processed")
+	progress := flagSet.Bool("progress", false, "render a progress bar on the standard error")
+	i18nFlags := addI18nFlags(flagSet)
+	err := parseFlags(flagSet, debug, args, 2)
+	if err != nil {
+		return err
+	}
+
+	i18nFolderPath := filepath.Join(flagSet.Arg(0), "i18n")
+	outputFolderPath := flagSet.Arg(1)
+
+	err = checkFolderExists(i18nFolderPath)
+	if err != nil {
+		return fmt.Errorf("error with provided dofus data folder: %w", err)
+	}
+
+	err = os.MkdirAll(filepath.Join(outputFolderPath, "translation"), 0755)
+	if err != nil {
+		return fmt.Errorf("error creating translation folder: %w", err)
+	}
+
+	var sqliteExporter *exporter.SQLiteExporter
+	if *sqlitePath != "" {
+		sqliteExporter, err = exporter.NewSQLiteExporter(*sqlitePath)
+		if err != nil {
+			return fmt.Errorf("error opening sqlite database: %w", err)
+		}
+		defer sqliteExporter.Close()
+	}
+
+	options, err := newI18nOptions(i18nFlags, i18nFolderPath, *workers, sqliteExporter)
+	if err != nil {
+		return err
+	}
+	options.report = newFailureReport(*failFast)
+	if *progress {
+		options.progress = startProgressBar(os.Stderr)
+	}
+
+	err = processI18nFolder(ctx, i18nFolderPath, outputFolderPath, options)
+	options.progress.stop()
+	if err != nil {
+		return err
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	return options.report.summarize()
+}
+
+type i18nFlags struct {
+	locales      *string
+	merge        *bool
+	po           *bool
+	xliff        *string
+	sourceLocale *string
+}
+
+func addI18nFlags(flagSet *flag.FlagSet) i18nFlags {
+	return i18nFlags{
+		locales:      flagSet.String("locales", "", "comma separated locales to export (e.g. 'fr,en'), all when empty"),
+		merge:        flagSet.Bool("merge-locales", false, "also export the texts of all locales to a single merged.json file, keyed by id then locale"),
+		po:           flagSet.Bool("po", false, "also export translations as gettext PO files"),
+		xliff:        flagSet.String("xliff", "", "also export translations as XLIFF files of this version (1.2 or 2.0)"),
+		sourceLocale: flagSet.String("source-locale", "fr", "locale of the PO and XLIFF source texts, the one the game is written in"),
+	}
+}
+
+type i18nOptions struct {
+	workers        int
+	sqliteExporter *exporter.SQLiteExporter
+	locales        []string // all locales when empty
+	merge          bool
+	po             bool
+	xliffVersion   string // no XLIFF export when empty
+	sourceLocale   string
+	source         parser.D2iData    // texts of the source locale
+	state          *incrementalState // nil when not recording input hashes
+	report         *failureReport
+	progress       *progressBar // nil when not shown
+}
+
+func newI18nOptions(flags i18nFlags, i18nFolderPath string, workers int, sqliteExporter *exporter.SQLiteExporter) (i18nOptions, error) {
+	options := i18nOptions{
+		workers:        workers,
+		sqliteExporter: sqliteExporter,
+		locales:        splitPatterns(*flags.locales),
+		merge:          *flags.merge,
+		po:             *flags.po,
+		xliffVersion:   *flags.xliff,
+		sourceLocale:   *flags.sourceLocale,
+	}
+
+	if options.xliffVersion != "" && options.xliffVersion != "1.2" && options.xliffVersion != "2.0" {
+		return i18nOptions{}, fmt.Errorf("unsupported XLIFF version: %s", options.xliffVersion)
+	}
+
+	if options.po || options.xliffVersion != "" {
+		var err error
+		options.source, err = parser.ProcessD2iFileData(filepath.Join(i18nFolderPath, getD2iFileNameFromLocale(options.sourceLocale)), parseOptions())
+		if err != nil {
+			return i18nOptions{}, fmt.Errorf("error loading source locale %s: %w", options.sourceLocale, err)
+		}
+	}
+
+	return options, nil
+}
+
+// filterLocales returns the names of the D2I files of the selected locales.
+func (o i18nOptions) filterLocales(fileNames []string) []string {
+	if len(o.locales) == 0 {
+		return fileNames
+	}
+
+	filtered := make([]string, 0, len(o.locales))
+	for _, fileName := range fileNames {
+		if slices.Contains(o.locales, getLocalFromD2iFileName(fileName)) {
+			filtered = append(filtered, fileName)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/brequet/dofus-data-file-parser/pkg/dataset"
+)
+
+func runIconsCommand(_ context.Context, args []string) error {
+	flagSet, debug := newFlagSet("icons", "dofusDataFolderPath gfxFolderPath outputFolderPath")
+	locale := flagSet.String("locale", "en", "locale of the names of the objects")
+	extract := flagSet.Bool("extract", false, "also write the images, named after their icon id (e.g. items/16001.png)")
+	err := parseFlags(flagSet, debug, args, 3)
+	if err != nil {
+		return err
+	}
+
+	dofusDataFolderPath := flagSet.Arg(0)
+	gfxFolderPath := flagSet.Arg(1)
+	outputFolderPath := flagSet.Arg(2)
+
+	err = checkDofusDataFolder(dofusDataFolderPath)
+	if err != nil {
+		return fmt.Errorf("error with provided dofus data folder: %w", err)
+	}
+
+	d, err := dataset.Open(dofusDataFolderPath, *locale, parseOptions())
+	if err != nil {
+		return err
+	}
+	gfx, err := dataset.IndexGfx(gfxFolderPath, parseOptions())
+	if err != nil {
+		return fmt.Errorf("error reading gfx archives: %w", err)
+	}
+
+	icons, err := dataset.BuildIcons(d, gfx)
+	if err != nil {
+		return fmt.Errorf("error building icons: %w", err)
+	}
+
+	err = os.MkdirAll(outputFolderPath, 0755)
+	if err != nil {
+		return fmt.Errorf("error creating output folder: %w", err)
+	}
+	err = writeJSONFile(icons, filepath.Join(outputFolderPath, "icons.json"))
+	if err != nil {
+		return fmt.Errorf("error writing icons: %w", err)
+	}
+	if *extract {
+		err = dataset.ExtractIcons(icons, gfx, outputFolderPath)
+		if err != nil {
+			return fmt.Errorf("error extracting icons: %w", err)
+		}
+	}
+	slog.Info("icons mapped", "count", len(icons))
+
+	return nil
+}
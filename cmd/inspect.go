@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+
+	"github.com/brequet/dofus-data-file-parser/pkg/parser"
+)
+
+func runInspectCommand(_ context.Context, args []string) error {
+	flagSet, debug := newFlagSet("inspect", "filePath")
+	id := flagSet.Int("id", -1, "also print the object (D2O), text (D2I) or graphical element (ELE) with this id")
+	err := parseFlags(flagSet, debug, args, 1)
+	if err != nil {
+		return err
+	}
+
+	filePath := flagSet.Arg(0)
+	switch filepath.Ext(filePath) {
+	case ".d2o":
+		return inspectD2oFile(filePath, *id)
+	case ".d2i":
+		return inspectD2iFile(filePath, *id)
+	case ".d2p":
+		return inspectD2pFile(filePath)
+	case ".dlm":
+		return inspectDlmFile(filePath)
+	case ".ele":
+		return inspectEleFile(filePath, *id)
+	case ".swl":
+		return inspectSwlFile(filePath)
+	default:
+		return fmt.Errorf("unsupported file extension: %s", filepath.Ext(filePath))
+	}
+}
+
+func inspectD2oFile(filePath string, id int) error {
+	reader, err := parser.NewD2oReader(filePath, parseOptions())
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	classes := reader.Classes()
+	classIds := make([]int, 0, len(classes))
+	for classId := range classes {
+		classIds = append(classIds, classId)
+	}
+	sort.Ints(classIds)
+
+	fmt.Printf("objects: %d\n", len(reader.ObjectIds()))
+	fmt.Printf("search keys: %d\n", len(reader.SearchIndex()))
+	fmt.Printf("classes: %d\n", len(classes))
+	for _, classId := range classIds {
+		class := classes[classId]
+		fmt.Printf("  %d %s.%s (%d fields)\n", classId, class.PackageName, class.PackageClass, len(class.Fields))
+	}
+
+	if id < 0 {
+		return nil
+	}
+
+	object, err := reader.GetObjectByID(id)
+	if err != nil {
+		return err
+	}
+	return printJSON(object)
+}
+
+func inspectD2iFile(filePath string, id int) error {
+	data, err := parser.ProcessD2iFileData(filePath, parseOptions())
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("texts: %d\n", len(data.Texts))
+	fmt.Printf("named texts: %d\n", len(data.NamedTexts))
+
+	if id < 0 {
+		return nil
+	}
+
+	text, ok := data.Texts[id]
+	if !ok {
+		return fmt.Errorf("text not found: %d", id)
+	}
+	return printJSON(text)
+}
+
+func inspectD2pFile(filePath string) error {
+	archive, err := parser.ProcessD2pFile(filePath, parseOptions())
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("properties: %d\n", len(archive.Properties))
+	for _, property := range archive.Properties {
+		fmt.Printf("  %s=%s\n", property.Key, property.Value)
+	}
+	fmt.Printf("entries: %d\n", len(archive.Entries))
+	for _, entry := range archive.Entries {
+		fmt.Printf("  %s (%d bytes)\n", entry.Name, entry.Length)
+	}
+
+	return nil
+}
+
+func inspectDlmFile(filePath string) error {
+	dlmMap, err := parser.ProcessDlmFile(filePath, parseOptions())
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("id: %d\n", dlmMap.Id)
+	fmt.Printf("version: %d\n", dlmMap.Version)
+	fmt.Printf("subarea id: %d\n", dlmMap.SubareaId)
+	fmt.Printf("neighbours: top %d, bottom %d, left %d, right %d\n", dlmMap.TopNeighbourId, dlmMap.BottomNeighbourId, dlmMap.LeftNeighbourId, dlmMap.RightNeighbourId)
+	fmt.Printf("layers: %d\n", len(dlmMap.Layers))
+	fmt.Printf("cells: %d\n", len(dlmMap.Cells))
+
+	return nil
+}
+
+func inspectEleFile(filePath string, id int) error {
+	elements, err := parser.ProcessEleFile(filePath, parseOptions())
+	if err != nil {
+		return err
+	}
+
+	typeCounts := map[int]int{}
+	for _, element := range elements.Elements {
+		typeCounts[element.ElementType]++
+	}
+	fmt.Printf("version: %d\n", elements.Version)
+	fmt.Printf("elements: %d\n", len(elements.Elements))
+	for _, elementType := range slices.Sorted(maps.Keys(typeCounts)) {
+		fmt.Printf("  type %d: %d\n", elementType, typeCounts[elementType])
+	}
+	fmt.Printf("jpg gfx: %d\n", len(elements.JpgGfx))
+
+	if id >= 0 {
+		element, ok := elements.Elements[uint(id)]
+		if !ok {
+			return fmt.Errorf("no element with id %d", id)
+		}
+		return printJSON(element)
+	}
+	return nil
+}
+
+func inspectSwlFile(filePath string) error {
+	swl, err := parser.ProcessSwlFile(filePath, parseOptions())
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("version: %d\n", swl.Version)
+	fmt.Printf("frame rate: %d\n", swl.FrameRate)
+	fmt.Printf("frames: %d\n", swl.FrameCount)
+	fmt.Printf("swf: %d bytes\n", len(swl.Swf))
+	fmt.Printf("classes: %d\n", len(swl.Classes))
+	for _, class := range swl.Classes {
+		fmt.Printf("  %s\n", class)
+	}
+
+	return nil
+}
+
+func printJSON(value any) error {
+	jsonStr, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshalling json: %w", err)
+	}
+
+	_, err = os.Stdout.Write(append(jsonStr, '\n'))
+	return err
+}
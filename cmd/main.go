@@ -1,63 +1,379 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"io/fs"
 	"log/slog"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
 
-	"github.com/brequet/dofus-data-file-parser/pkg/generator"
+	"github.com/brequet/dofus-data-file-parser/pkg/criterion"
+	"github.com/brequet/dofus-data-file-parser/pkg/effect"
+	"github.com/brequet/dofus-data-file-parser/pkg/exporter"
 	"github.com/brequet/dofus-data-file-parser/pkg/parser"
 )
 
+type command struct {
+	name        string
+	description string
+	run         func(ctx context.Context, args []string) error
+}
+
+var errUsage = errors.New("invalid usage")
+
+func getCommands() []command {
+	return []command{
+		{name: "parse", description: "export modules, translations and class types of a Dofus data folder", run: runParseCommand},
+		{name: "i18n", description: "export the translations of a Dofus data folder", run: runI18nCommand},
+		{name: "gen", description: "generate the class types of a Dofus data folder", run: runGenCommand},
+		{name: "inspect", description: "print a summary of a D2O, D2I, D2P, DLM, ELE or SWL file", run: runInspectCommand},
+		{name: "diff", description: "list the changes between two Dofus data folders", run: runDiffCommand},
+		{name: "export", description: "build a dataset joining several modules, e.g. the item encyclopedia", run: runExportCommand},
+		{name: "icons", description: "map the icons of items, spells and monsters to their images in the gfx archives", run: runIconsCommand},
+		{name: "render-map", description: "draw maps as PNG images from their archives, elements and gfx", run: runRenderMapCommand},
+		{name: "pathfinding", description: "export the movement graphs of maps and the transitions between them", run: runPathfindingCommand},
+		{name: "swl", description: "list the classes of SWL asset libraries and extract their SWF movies", run: runSwlCommand},
+		{name: "fetch", description: "download the data files of a game version from the Cytrus CDN, then parse them", run: runFetchCommand},
+	}
+}
+
 func main() {
-	debug := flag.Bool("debug", false, "enable debug mode")
-	flag.Parse()
+	commands := getCommands()
+	if len(os.Args) < 2 || os.Args[1] == "help" || os.Args[1] == "-h" || os.Args[1] == "--help" {
+		printUsage(commands)
+		os.Exit(1)
+	}
 
-	if flag.NArg() != 2 {
-		fmt.Println("Usage:", os.Args[0], "[--debug] dofusDataFolderPath outputFolderPath")
+	// without a subcommand, arguments are those of parse, as before subcommands existed
+	cmd, args := commands[0], os.Args[1:]
+	for _, c := range commands {
+		if c.name == os.Args[1] {
+			cmd, args = c, os.Args[2:]
+			break
+		}
+	}
+
+	// interrupting cancels the context, for the command to stop cleanly
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	err := cmd.run(ctx, args)
+	stop()
+	if errors.Is(err, errUsage) {
+		os.Exit(2)
+	}
+	if err != nil {
+		slog.Error("error running command", "command", cmd.name, "error", err)
 		os.Exit(1)
 	}
+}
+
+func printUsage(commands []command) {
+	fmt.Fprintf(os.Stderr, "Usage: %s <command> [flags] [arguments]\n\nCommands:\n", os.Args[0])
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %-8s %s\n", c.name, c.description)
+	}
+	fmt.Fprintf(os.Stderr, "\nRun '%s <command> -h' for the flags of a command.\n", os.Args[0])
+}
+
+// strictParsing is set by the --strict flag shared by all commands.
+var strictParsing bool
+
+// outputCompression is set by the --compress flag of the parse command, the
+// JSON files written by writeJSONFile being compressed with it.
+var outputCompression exporter.Compression
+
+// newFlagSet creates the flag set of a command, with its usage line and the
+// --debug and --strict flags shared by all commands, as well as --auto-detect
+// for those taking a Dofus data folder.
+func newFlagSet(name, arguments string) (*flag.FlagSet, *bool) {
+	flagSet := flag.NewFlagSet(name, flag.ContinueOnError)
+	flagSet.Usage = func() {
+		fmt.Fprintf(flagSet.Output(), "Usage: %s %s [flags] %s\n", os.Args[0], name, arguments)
+		flagSet.PrintDefaults()
+	}
+	debug := flagSet.Bool("debug", false, "enable debug mode")
+	flagSet.BoolVar(&strictParsing, "strict", false, "fail on any anomaly of the parsed files instead of skipping the objects which cannot be decoded")
+	if argIndex := slices.Index(strings.Fields(arguments), "dofusDataFolderPath"); argIndex >= 0 {
+		flagSet.Var(&autoDetectFlag{argIndex: argIndex}, "auto-detect", "use the data folder of the installed game, to be left out of the arguments")
+	}
+	return flagSet, debug
+}
+
+// parseFlags parses the arguments of a command, which must have exactly
+// argumentCount positional arguments, and sets up logging.
+func parseFlags(flagSet *flag.FlagSet, debug *bool, args []string, argumentCount int) error {
+	err := flagSet.Parse(args)
+	if err != nil {
+		return errUsage
+	}
+
+	setupLogger(*debug)
+
+	if autoDetect, ok := flagValue(flagSet, "auto-detect").(*autoDetectFlag); ok && autoDetect.enabled && flagSet.NArg() == argumentCount-1 {
+		dofusDataFolderPath, err := detectDofusDataFolder()
+		if err != nil {
+			return err
+		}
+		slog.Info("detected dofus data folder", "path", dofusDataFolderPath)
+		// the remaining arguments are all positional, parsing them again
+		// only inserts the data folder
+		err = flagSet.Parse(slices.Insert(flagSet.Args(), autoDetect.argIndex, dofusDataFolderPath))
+		if err != nil {
+			return errUsage
+		}
+	}
+
+	if flagSet.NArg() != argumentCount {
+		flagSet.Usage()
+		return errUsage
+	}
+	return nil
+}
 
-	dofusDataFolderPath := flag.Arg(0)
-	outputFolderPath := flag.Arg(1)
+// flagValue returns the value of a flag, nil when the flag set has no flag of
+// this name.
+func flagValue(flagSet *flag.FlagSet, name string) flag.Value {
+	f := flagSet.Lookup(name)
+	if f == nil {
+		return nil
+	}
+	return f.Value
+}
 
+func setupLogger(debug bool) {
 	logLevel := slog.LevelInfo
-	if *debug {
+	if debug {
 		logLevel = slog.LevelDebug
 	}
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
 		Level: logLevel,
 	}))
 	slog.SetDefault(logger)
+}
+
+// parseOptions forwards the logs of the parser to the logger of the command.
+func parseOptions() parser.ParseOptions {
+	return parser.ParseOptions{Logger: slog.Default(), Strict: strictParsing}
+}
+
+func runParseCommand(ctx context.Context, args []string) error {
+	flagSet, debug := newFlagSet("parse", "dofusDataFolderPath outputFolderPath")
+	d2pFolderPath := flagSet.String("d2p", "", "folder containing .d2p archives to unpack")
+	lang := flagSet.String("lang", "go", "language of the generated class types (go or ts)")
+	sqlitePath := flagSet.String("sqlite", "", "also export modules and translations to this SQLite database")
+	exportCSV := flagSet.Bool("csv", false, "also export modules as CSV files, one per class, in the csv output folder")
+	extraExporters := flagSet.String("exporters", "", "comma separated registered exporters to also run, each writing to the output subfolder of its name")
+	workers := flagSet.Int("workers", 1, "number of files parsed concurrently")
+	decodeWorkers := flagSet.Int("decode-workers", 1, "number of goroutines decoding the objects of a file, for the biggest modules")
+	resolveI18n := flagSet.String("resolve-i18n", "", "locale whose texts are embedded next to I18n fields (e.g. fr)")
+	decodeEffects := flagSet.Bool("decode-effects", false, "add the decoded zone and values of effect instances to the exported objects, as an \"Effect_\" entry")
+	parseCriteria := flagSet.Bool("parse-criteria", false, "add the syntax tree and description of criteria fields to the exported objects, as \"<field>Tree\" and \"<field>Description\" entries")
+	outputFormat := flagSet.String("output-format", "json", "format of the exported modules (json or ndjson, one object per line)")
+	compress := flagSet.String("compress", "", "compress the written JSON files with gzip or zstd, adding .gz or .zst to their names")
+	bundle := flagSet.String("bundle", "", "also write the whole output folder, manifest included, to a single .zip or .tar.zst archive")
+	clean := flagSet.Bool("clean", false, "remove the previous content of the output folder, which must be empty or contain the manifest of a previous run")
+	failFast := flagSet.Bool("fail-fast", false, "stop at the first file which fails to be processed")
+	progress := flagSet.Bool("progress", false, "render a progress bar on the standard error")
+	incremental := flagSet.Bool("incremental", false, "only export the files which changed since the last run with the same options")
+	gameVersion := flagSet.String("game-version", "", "game version recorded in the manifest, instead of the one of the VERSION file next to the data folder")
+	retroLocale := flagSet.String("retro-locale", "fr", "locale of the lang files exported as modules, for a Dofus Retro data folder")
+	i18nFlags := addI18nFlags(flagSet)
+	include, exclude := addModuleFilterFlags(flagSet)
+	err := parseFlags(flagSet, debug, args, 2)
+	if err != nil {
+		return err
+	}
+	if *outputFormat != "json" && *outputFormat != "ndjson" {
+		flagSet.Usage()
+		return errUsage
+	}
+	outputCompression, err = exporter.ParseCompression(*compress)
+	if err != nil {
+		return err
+	}
+
+	filter, err := newModuleFilter(*include, *exclude)
+	if err != nil {
+		return err
+	}
+	if *lang != "go" && *lang != "ts" {
+		flagSet.Usage()
+		return errUsage
+	}
+
+	dofusDataFolderPath := flagSet.Arg(0)
+	outputFolderPath := flagSet.Arg(1)
+
+	err = checkBundlePath(*bundle, outputFolderPath)
+	if err != nil {
+		return err
+	}
 
 	slog.Info("Dofus Data File Parser started")
 	slog.Debug("debug mode enabled")
 
-	err := checkDofusDataFolder(dofusDataFolderPath)
+	game, err := parser.DetectGameVersion(dofusDataFolderPath)
 	if err != nil {
-		slog.Error("error with provided dofus data folder", "error", err)
-		os.Exit(1)
+		return fmt.Errorf("error with provided dofus data folder: %w", err)
+	}
+	switch game {
+	case parser.DofusRetro:
+		err = runRetroParse(ctx, flagSet, dofusDataFolderPath, outputFolderPath, *retroLocale, *gameVersion, *clean, *failFast)
+		if err != nil {
+			return err
+		}
+		return writeBundle(outputFolderPath, *bundle)
+	case parser.Dofus3:
+		err = runUnityParse(ctx, flagSet, dofusDataFolderPath, outputFolderPath, *gameVersion, *clean, *failFast)
+		if err != nil {
+			return err
+		}
+		return writeBundle(outputFolderPath, *bundle)
 	}
 
-	err = prepareOutputFolder(outputFolderPath)
+	err = checkDofusDataFolder(dofusDataFolderPath)
 	if err != nil {
-		slog.Error("error preparing output folder", "error", err)
-		os.Exit(1)
+		return fmt.Errorf("error with provided dofus data folder: %w", err)
+	}
+
+	err = prepareOutputFolder(outputFolderPath, *lang, *clean)
+	if err != nil {
+		return fmt.Errorf("error preparing output folder: %w", err)
+	}
+
+	fingerprint := optionsFingerprint(flagSet)
+	if *resolveI18n != "" {
+		// the embedded texts are stale once the texts of the locale change,
+		// whether the modules changed or not
+		fingerprint, err = addInputFingerprint(fingerprint, filepath.Join(dofusDataFolderPath, "i18n", getD2iFileNameFromLocale(*resolveI18n)))
+		if err != nil {
+			return err
+		}
+	}
+	state, err := newIncrementalState(outputFolderPath, fingerprint, *incremental)
+	if err != nil {
+		return err
+	}
+	state.describeInputs(dofusDataFolderPath, *gameVersion)
+
+	var sqliteExporter *exporter.SQLiteExporter
+	if *sqlitePath != "" {
+		sqliteExporter, err = exporter.NewSQLiteExporter(*sqlitePath)
+		if err != nil {
+			return fmt.Errorf("error opening sqlite database: %w", err)
+		}
+		defer sqliteExporter.Close()
+	}
+
+	var translations parser.Translations
+	if *resolveI18n != "" {
+		translations, err = parser.ProcessD2iFile(filepath.Join(dofusDataFolderPath, "i18n", getD2iFileNameFromLocale(*resolveI18n)), parseOptions())
+		if err != nil {
+			return fmt.Errorf("error loading translations of locale %s to resolve: %w", *resolveI18n, err)
+		}
+	}
+
+	jsonExporter := exporter.NewJSONExporter(filepath.Join(outputFolderPath, "common"), *outputFormat == "ndjson", translations)
+	if *decodeEffects {
+		jsonExporter.AddEnricher(effect.Enrich)
+	}
+	if *parseCriteria {
+		jsonExporter.AddEnricher(criterion.Renderer{}.Enrich)
+	}
+	jsonExporter.SetCompression(outputCompression)
+	schemaExporter := exporter.NewJSONSchemaExporter(filepath.Join(outputFolderPath, "schema"))
+	schemaExporter.SetCompression(outputCompression)
+	exporters := []exporter.Exporter{
+		jsonExporter,
+		schemaExporter,
+	}
+	typesExporter, err := exporter.NewTypesExporter(*lang, outputFolderPath)
+	if err != nil {
+		return err
+	}
+	exporters = append(exporters, typesExporter)
+	if sqliteExporter != nil {
+		exporters = append(exporters, sqliteExporter)
+	}
+	if *exportCSV {
+		exporters = append(exporters, exporter.NewCSVExporter(filepath.Join(outputFolderPath, "csv")))
+	}
+	for _, name := range splitPatterns(*extraExporters) {
+		extraExporter, err := exporter.New(name, filepath.Join(outputFolderPath, name))
+		if err != nil {
+			return err
+		}
+		exporters = append(exporters, extraExporter)
 	}
 
-	err = processCommonFolder(filepath.Join(dofusDataFolderPath, "common"), outputFolderPath)
+	var bar *progressBar
+	if *progress {
+		bar = startProgressBar(os.Stderr)
+	}
+
+	report := newFailureReport(*failFast)
+	options := commonFolderOptions{
+		workers:       *workers,
+		decodeWorkers: *decodeWorkers,
+		exporters:     exporters,
+		filter:        filter,
+		state:         state,
+		report:        report,
+		progress:      bar,
+	}
+	err = processCommonFolder(ctx, filepath.Join(dofusDataFolderPath, "common"), options)
 	if err != nil {
-		slog.Error("error processing common folder", "error", err)
+		return fmt.Errorf("error processing common folder: %w", err)
 	}
 
-	err = processI18nFolder(filepath.Join(dofusDataFolderPath, "i18n"), outputFolderPath)
+	i18nFolderPath := filepath.Join(dofusDataFolderPath, "i18n")
+	i18nOptions, err := newI18nOptions(i18nFlags, i18nFolderPath, *workers, sqliteExporter)
 	if err != nil {
-		slog.Error("error processing i18n folder", "error", err)
+		return err
+	}
+	i18nOptions.state = state
+	i18nOptions.report = report
+	i18nOptions.progress = bar
+
+	if !report.stopped() {
+		err = processI18nFolder(ctx, i18nFolderPath, outputFolderPath, i18nOptions)
+		if err != nil {
+			return fmt.Errorf("error processing i18n folder: %w", err)
+		}
 	}
+
+	if *d2pFolderPath != "" && !report.stopped() {
+		err = processD2pFolder(ctx, *d2pFolderPath, outputFolderPath, report)
+		if err != nil {
+			return fmt.Errorf("error processing d2p folder: %w", err)
+		}
+	}
+
+	bar.stop()
+
+	err = state.write(outputFolderPath)
+	if err != nil {
+		return fmt.Errorf("error writing manifest: %w", err)
+	}
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	err = report.summarize()
+	if err != nil {
+		return err
+	}
+	// only complete outputs are bundled
+	return writeBundle(outputFolderPath, *bundle)
 }
 
 func checkDofusDataFolder(dofusDataFolderPath string) error {
@@ -92,159 +408,412 @@ func checkFolderExists(folderPath string) error {
 	return nil
 }
 
-func prepareOutputFolder(outputFolderPath string) error {
-	err := os.RemoveAll(outputFolderPath)
-	if err != nil {
-		return fmt.Errorf("error removing output folder: %w", err)
+// prepareOutputFolder creates the output folder and its subfolders. Files are
+// written in place unless clean is set, in which case the previous content is
+// removed, provided the folder was written by the parser.
+func prepareOutputFolder(outputFolderPath, lang string, clean bool) error {
+	if clean {
+		err := checkOutputFolderRemovable(outputFolderPath)
+		if err != nil {
+			return err
+		}
+
+		err = os.RemoveAll(outputFolderPath)
+		if err != nil {
+			return fmt.Errorf("error removing output folder: %w", err)
+		}
 	}
 
-	err = os.MkdirAll(outputFolderPath, 0755)
-	if err != nil {
-		return fmt.Errorf("error creating output folder: %w", err)
+	for _, folderName := range []string{"common", lang, "schema", "translation"} {
+		err := os.MkdirAll(filepath.Join(outputFolderPath, folderName), 0755)
+		if err != nil {
+			return fmt.Errorf("error creating %s folder: %w", folderName, err)
+		}
 	}
 
-	err = os.Mkdir(filepath.Join(outputFolderPath, "common"), 0755)
+	return nil
+}
+
+// checkOutputFolderRemovable refuses to remove a non-empty folder without the
+// manifest written at the end of each run, as it may contain other data.
+func checkOutputFolderRemovable(outputFolderPath string) error {
+	entries, err := os.ReadDir(outputFolderPath)
+	if errors.Is(err, fs.ErrNotExist) || len(entries) == 0 {
+		return nil
+	}
 	if err != nil {
-		return fmt.Errorf("error creating common folder: %w", err)
+		return fmt.Errorf("error reading output folder: %w", err)
 	}
 
-	err = os.Mkdir(filepath.Join(outputFolderPath, "go"), 0755)
+	_, err = os.Stat(filepath.Join(outputFolderPath, manifestFileName))
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("refusing to clean %s: folder is not empty and contains no %s", outputFolderPath, manifestFileName)
+	}
 	if err != nil {
-		return fmt.Errorf("error creating common folder: %w", err)
+		return fmt.Errorf("error checking manifest: %w", err)
 	}
 
-	err = os.Mkdir(filepath.Join(outputFolderPath, "translation"), 0755)
+	return nil
+}
+
+type commonFolderOptions struct {
+	workers       int
+	decodeWorkers int
+	exporters     []exporter.Exporter
+	filter        moduleFilter
+	state         *incrementalState // nil when not recording input hashes
+	report        *failureReport
+	progress      *progressBar // nil when not shown
+}
+
+func processCommonFolder(ctx context.Context, commonFolderPath string, options commonFolderOptions) error {
+	fileNames, err := listFilesWithExtension(commonFolderPath, ".d2o")
 	if err != nil {
-		return fmt.Errorf("error creating translation folder: %w", err)
+		return err
+	}
+	fileNames = options.filter.filter(fileNames)
+	options.progress.FilesDiscovered(len(fileNames))
+
+	var fileParsedCount atomic.Int64
+	runWorkers(ctx, options.workers, fileNames, options.report, func(fileName string) error {
+		defer options.progress.FileParsed(fileName)
+
+		inputPath := filepath.Join("common", fileName)
+		hash, unchanged, err := options.state.check(filepath.Join(commonFolderPath, fileName), inputPath)
+		if err != nil {
+			return err
+		}
+
+		err = processD2oFile(ctx, commonFolderPath, fileName, options, unchanged)
+		if err != nil {
+			return err
+		}
+		fileParsedCount.Add(1)
+		options.state.record(inputPath, hash)
+		return nil
+	})
+	slog.Info("d2o files parsed", "count", fileParsedCount.Load())
+
+	if options.report.stopped() || ctx.Err() != nil {
+		return nil
+	}
+
+	for _, e := range options.exporters {
+		if finisher, ok := e.(exporter.Finisher); ok {
+			err = finisher.Finish()
+			if err != nil {
+				options.report.add(e.Name(), fmt.Errorf("error finishing export: %w", err))
+			}
+		}
 	}
 
 	return nil
 }
 
-func processCommonFolder(commonFolderPath, outputFolderPath string) error {
-	files, err := os.ReadDir(commonFolderPath)
+// processD2oFile exports a module, returning the errors of all exporters. The
+// outputs of an unchanged module are kept, only exporters aggregating modules
+// (finishers) are given the module.
+func processD2oFile(ctx context.Context, commonFolderPath, fileName string, options commonFolderOptions, unchanged bool) error {
+	parseOptions := options.progress.parseOptions()
+	parseOptions.Workers = options.decodeWorkers
+	reader, err := parser.NewD2oReader(filepath.Join(commonFolderPath, fileName), parseOptions)
 	if err != nil {
-		return fmt.Errorf("error reading directory: %w", err)
+		return err
 	}
+	defer reader.Close()
 
-	classes := map[string]map[string]parser.Class{}
+	slog.Debug("file parsed", "file", fileName, "classes", len(reader.Classes()), "objects", len(reader.ObjectIds()))
 
-	fileParsedCount := 0
+	module := exporter.NewModuleContext(ctx, strings.TrimSuffix(fileName, ".d2o"), reader)
+	var errs []error
+	for _, e := range options.exporters {
+		if _, ok := e.(exporter.Finisher); unchanged && !ok {
+			continue
+		}
+
+		err = e.Export(module)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error exporting module with %s exporter: %w", e.Name(), err))
+		}
+	}
+	if warnings := reader.Warnings(); len(warnings) > 0 {
+		slog.Warn("objects skipped", "file", fileName, "count", len(warnings))
+	}
+
+	return errors.Join(errs...)
+}
+
+// listFilesWithExtension returns the names of the files of a folder having
+// the given extension.
+func listFilesWithExtension(folderPath, extension string) ([]string, error) {
+	files, err := os.ReadDir(folderPath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading directory: %w", err)
+	}
+
+	fileNames := make([]string, 0)
 	for _, file := range files {
 		if file.IsDir() {
 			slog.Debug("skipping directory", "directory", file.Name())
 			continue
 		}
 
-		if filepath.Ext(file.Name()) != ".d2o" {
+		if filepath.Ext(file.Name()) != extension {
 			slog.Debug("skipping file (wrong extension)", "file", file.Name())
 			continue
 		}
 
-		d2oFilePath := filepath.Join(commonFolderPath, file.Name())
-		data, err := parser.ProcessD2oFile(d2oFilePath)
-		if err != nil {
-			slog.Error("error parsing file", "error", err)
-			continue
+		fileNames = append(fileNames, file.Name())
+	}
+
+	return fileNames, nil
+}
+
+// runWorkers calls process for each file name from up to workers goroutines,
+// adding failures to the report. Remaining files are skipped once the report
+// is stopped or ctx is done.
+func runWorkers(ctx context.Context, workers int, fileNames []string, report *failureReport, process func(fileName string) error) {
+	fileNamesChan := make(chan string)
+
+	var wg sync.WaitGroup
+	for i := 0; i < max(workers, 1); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for fileName := range fileNamesChan {
+				err := process(fileName)
+				if err != nil && ctx.Err() == nil {
+					report.add(fileName, err)
+				}
+			}
+		}()
+	}
+
+	for _, fileName := range fileNames {
+		if report.stopped() || ctx.Err() != nil {
+			break
 		}
+		fileNamesChan <- fileName
+	}
+	close(fileNamesChan)
+	wg.Wait()
+}
 
-		slog.Debug("file parsed", "file", file.Name(), "classes", len(data.Classes), "objects", len(data.Objects))
+func processI18nFolder(ctx context.Context, i18nFolderPath, outputFolderPath string, options i18nOptions) error {
+	fileNames, err := listFilesWithExtension(i18nFolderPath, ".d2i")
+	if err != nil {
+		return err
+	}
+	fileNames = options.filterLocales(fileNames)
+	options.progress.FilesDiscovered(len(fileNames))
+
+	translationsByLocale := map[string]parser.Translations{}
+	var translationsMutex sync.Mutex
 
-		jsonStr, err := json.MarshalIndent(data, "", "  ")
+	var fileParsedCount atomic.Int64
+	runWorkers(ctx, options.workers, fileNames, options.report, func(fileName string) error {
+		defer options.progress.FileParsed(fileName)
+
+		inputPath := filepath.Join("i18n", fileName)
+		hash, unchanged, err := options.state.check(filepath.Join(i18nFolderPath, fileName), inputPath)
 		if err != nil {
-			slog.Error("error marshalling json", "error", err)
+			return err
+		}
+		if unchanged && !options.merge {
+			slog.Debug("skipping unchanged file", "file", fileName)
+			options.state.record(inputPath, hash)
+			return nil
 		}
 
-		outputPath := filepath.Join(outputFolderPath, "common", file.Name()+".json")
-		err = os.WriteFile(outputPath, jsonStr, 0644)
+		translations, err := processD2iFile(i18nFolderPath, fileName, outputFolderPath, options)
 		if err != nil {
-			slog.Error("error writing file", "error", err, "path", outputPath)
+			return err
 		}
-		fileParsedCount++
+		fileParsedCount.Add(1)
+		options.state.record(inputPath, hash)
 
-		for _, class := range data.Classes {
-			if classes[class.PackageName] == nil {
-				classes[class.PackageName] = map[string]parser.Class{}
-			}
-			classes[class.PackageName][class.PackageClass] = class
+		if options.merge {
+			translationsMutex.Lock()
+			defer translationsMutex.Unlock()
+			translationsByLocale[getLocalFromD2iFileName(fileName)] = translations
 		}
-	}
-	slog.Info("d2o files parsed", "count", fileParsedCount)
+		return nil
+	})
+	slog.Info("d2i files parsed", "count", fileParsedCount.Load())
 
-	err = exportClassTypesToGolang(classes, outputFolderPath)
-	if err != nil {
-		slog.Error("error exporting class types to golang", "error", err)
+	if options.merge && !options.report.stopped() && ctx.Err() == nil {
+		mergedOutputPath := filepath.Join(outputFolderPath, "translation", "merged.json")
+		err = writeJSONFile(parser.MergeTranslations(translationsByLocale), mergedOutputPath)
+		if err != nil {
+			options.report.add(mergedOutputPath, fmt.Errorf("error writing file: %w", err))
+		}
 	}
 
 	return nil
 }
 
-func exportClassTypesToGolang(classes map[string]map[string]parser.Class, outputFolderPath string) error {
-	for packageName, classMap := range classes {
+// processD2iFile exports the texts of a locale and returns them, along with
+// the errors of the exports.
+func processD2iFile(i18nFolderPath, fileName, outputFolderPath string, options i18nOptions) (parser.Translations, error) {
+	d2iFilePath := filepath.Join(i18nFolderPath, fileName)
+	data, err := parser.ProcessD2iFileData(d2iFilePath, options.progress.parseOptions())
+	if err != nil {
+		return nil, err
+	}
 
-		classList := make([]parser.Class, 0)
-		for _, class := range classMap {
-			classList = append(classList, class)
-		}
+	translations := parser.Translations{}
+	for id, text := range data.Texts {
+		translations[id] = text.Text
+	}
+
+	var errs []error
+	locale := getLocalFromD2iFileName(fileName)
+	outputPath := filepath.Join(outputFolderPath, "translation", locale+".json")
+	err = writeJSONFile(translations, outputPath)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("error writing %s: %w", outputPath, err))
+	}
+
+	undiacriticalOutputPath := filepath.Join(outputFolderPath, "translation", locale+".undiacritical.json")
+	err = writeJSONFile(data.UndiacriticalTranslations(), undiacriticalOutputPath)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("error writing %s: %w", undiacriticalOutputPath, err))
+	}
 
-		goFileContent, err := generator.GenerateGoFromClasses(classList)
+	namedOutputPath := filepath.Join(outputFolderPath, "translation", locale+".named.json")
+	err = writeJSONFile(data.NamedTexts, namedOutputPath)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("error writing %s: %w", namedOutputPath, err))
+	}
+
+	if options.po {
+		poOutputPath := filepath.Join(outputFolderPath, "translation", locale+".po")
+		err = exporter.WritePOFile(poOutputPath, locale, data, options.source)
 		if err != nil {
-			return fmt.Errorf("error generating golang from classes: %w", err)
+			errs = append(errs, fmt.Errorf("error writing %s: %w", poOutputPath, err))
 		}
+	}
 
-		fileName := packageName[strings.LastIndex(packageName, ".")+1:] + ".go"
+	if options.xliffVersion != "" {
+		xliffOutputPath := filepath.Join(outputFolderPath, "translation", locale+".xlf")
+		err = exporter.WriteXLIFFFile(xliffOutputPath, options.xliffVersion, locale, options.sourceLocale, data, options.source)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error writing %s: %w", xliffOutputPath, err))
+		}
+	}
 
-		goFilePath := filepath.Join(outputFolderPath, "go", fileName)
-		err = os.WriteFile(goFilePath, goFileContent, 0644)
+	if options.sqliteExporter != nil {
+		err = options.sqliteExporter.ExportTranslations(locale, translations)
 		if err != nil {
-			return fmt.Errorf("error writing file: %w", err)
+			errs = append(errs, fmt.Errorf("error exporting translations to sqlite: %w", err))
 		}
 	}
 
-	return nil
+	return translations, errors.Join(errs...)
 }
 
-func processI18nFolder(i18nFolderPath, outputFolderPath string) error {
-	files, err := os.ReadDir(i18nFolderPath)
+// writeJSONFile writes a value as indented JSON, compressed with
+// outputCompression, whose extension is appended to outputPath.
+func writeJSONFile(value any, outputPath string) error {
+	jsonStr, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshalling json: %w", err)
+	}
+
+	return outputCompression.WriteFile(outputPath, jsonStr)
+}
+
+// processD2pFolder extracts the archives of a folder and exports their maps,
+// as well as the graphical elements of elements.ele when the folder has it,
+// adding the archives and maps which failed to the report.
+func processD2pFolder(ctx context.Context, d2pFolderPath, outputFolderPath string, report *failureReport) error {
+	files, err := os.ReadDir(d2pFolderPath)
 	if err != nil {
 		return fmt.Errorf("error reading directory: %w", err)
 	}
 
 	fileParsedCount := 0
 	for _, file := range files {
+		if report.stopped() || ctx.Err() != nil {
+			break
+		}
+
 		if file.IsDir() {
 			slog.Debug("skipping directory", "directory", file.Name())
 			continue
 		}
 
-		if filepath.Ext(file.Name()) != ".d2i" {
+		if filepath.Ext(file.Name()) != ".d2p" {
 			slog.Debug("skipping file (wrong extension)", "file", file.Name())
 			continue
 		}
 
-		d2iFilePath := filepath.Join(i18nFolderPath, file.Name())
-		translations, err := parser.ProcessD2iFile(d2iFilePath)
+		d2pFilePath := filepath.Join(d2pFolderPath, file.Name())
+		archive, err := parser.ProcessD2pFile(d2pFilePath, parseOptions())
 		if err != nil {
-			return fmt.Errorf("error processing i18n file: %w", err)
+			report.add(file.Name(), err)
+			continue
 		}
 
-		jsonStr, err := json.MarshalIndent(translations, "", "  ")
+		slog.Debug("file parsed", "file", file.Name(), "entries", len(archive.Entries))
+
+		archiveOutputPath := filepath.Join(outputFolderPath, "d2p", strings.TrimSuffix(file.Name(), ".d2p"))
+		err = archive.ExtractAll(archiveOutputPath)
 		if err != nil {
-			slog.Error("error marshalling json", "error", err)
+			report.add(file.Name(), fmt.Errorf("error extracting archive: %w", err))
+			continue
 		}
 
-		outputPath := filepath.Join(outputFolderPath, "translation", getLocalFromD2iFileName(file.Name())+".json")
-		err = os.WriteFile(outputPath, jsonStr, 0644)
-		if err != nil {
-			slog.Error("error writing file", "error", err, "path", outputPath)
+		for _, entry := range archive.Entries {
+			if filepath.Ext(entry.Name) != ".dlm" {
+				continue
+			}
+
+			dlmMap, err := parser.ParseDlm(archive.Read(entry), parser.DefaultDlmKey, parseOptions())
+			if err != nil {
+				report.add(file.Name()+"/"+entry.Name, fmt.Errorf("error parsing map: %w", err))
+				continue
+			}
+
+			outputPath := filepath.Join(archiveOutputPath, filepath.FromSlash(entry.Name)+".json")
+			err = writeJSONFile(dlmMap, outputPath)
+			if err != nil {
+				report.add(file.Name()+"/"+entry.Name, fmt.Errorf("error writing %s: %w", outputPath, err))
+			}
 		}
 		fileParsedCount++
 	}
-	slog.Info("d2i files parsed", "count", fileParsedCount)
+	slog.Info("d2p files parsed", "count", fileParsedCount)
+
+	// the graphical elements the maps refer to are next to their archives
+	eleFilePath := filepath.Join(d2pFolderPath, eleFileName)
+	if _, err := os.Stat(eleFilePath); err == nil && !report.stopped() {
+		elements, err := parser.ProcessEleFile(eleFilePath, parseOptions())
+		if err != nil {
+			report.add(eleFileName, err)
+			return nil
+		}
+
+		outputPath := filepath.Join(outputFolderPath, "d2p", "elements.json")
+		err = os.MkdirAll(filepath.Dir(outputPath), 0755)
+		if err == nil {
+			err = writeJSONFile(elements, outputPath)
+		}
+		if err != nil {
+			report.add(eleFileName, fmt.Errorf("error writing %s: %w", outputPath, err))
+		}
+		slog.Info("elements parsed", "count", len(elements.Elements))
+	}
 
 	return nil
 }
 
+const eleFileName = "elements.ele"
+
 func getLocalFromD2iFileName(d2iFileName string) string {
 	return d2iFileName[len("i18n_") : len(d2iFileName)-len(".d2i")]
 }
+
+func getD2iFileNameFromLocale(locale string) string {
+	return "i18n_" + locale + ".d2i"
+}
@@ -0,0 +1,256 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/brequet/dofus-data-file-parser/pkg/parser"
+)
+
+const (
+	manifestFileName    = "manifest.json"
+	gameVersionFileName = "VERSION"
+)
+
+// manifest describes the last run writing to an output folder, for the next
+// incremental run and for the pipelines using the output to tell which data
+// and parser it comes from.
+type manifest struct {
+	Options       string            `json:"options"`                 // flags affecting the output, see optionsFingerprint
+	Game          string            `json:"game,omitempty"`          // see parser.DetectGameVersion
+	GameVersion   string            `json:"gameVersion,omitempty"`   // see readGameVersion
+	ParserVersion string            `json:"parserVersion,omitempty"` // see parserVersion
+	StartedAt     time.Time         `json:"startedAt"`
+	FinishedAt    time.Time         `json:"finishedAt"`
+	Inputs        map[string]string `json:"inputs"`  // input file path, relative to the data folder -> SHA-256
+	Outputs       map[string]string `json:"outputs"` // output file path, relative to the output folder -> SHA-256
+}
+
+func readManifest(outputFolderPath string) (manifest, error) {
+	content, err := os.ReadFile(filepath.Join(outputFolderPath, manifestFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return manifest{Inputs: map[string]string{}}, nil
+	}
+	if err != nil {
+		return manifest{}, fmt.Errorf("error reading manifest: %w", err)
+	}
+
+	m := manifest{}
+	err = json.Unmarshal(content, &m)
+	if err != nil {
+		return manifest{}, fmt.Errorf("error unmarshalling manifest: %w", err)
+	}
+	if m.Inputs == nil {
+		m.Inputs = map[string]string{}
+	}
+	return m, nil
+}
+
+// writeManifest writes the manifest, never compressed so that it is found by
+// the next run whatever its options.
+func writeManifest(m manifest, outputFolderPath string) error {
+	content, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshalling json: %w", err)
+	}
+	return os.WriteFile(filepath.Join(outputFolderPath, manifestFileName), content, 0644)
+}
+
+// optionsFingerprint returns the flags set on the command line, except those
+// which do not change the content of the output files.
+func optionsFingerprint(flagSet *flag.FlagSet) string {
+	ignoredFlags := []string{"debug", "workers", "decode-workers", "clean", "fail-fast", "progress", "incremental", "include", "exclude", "locales", "game-version", "auto-detect", "bundle"}
+
+	options := make([]string, 0)
+	flagSet.Visit(func(f *flag.Flag) {
+		if !slices.Contains(ignoredFlags, f.Name) {
+			options = append(options, f.Name+"="+f.Value.String())
+		}
+	})
+	return strings.Join(options, " ")
+}
+
+// addInputFingerprint adds the hash of an input file to the options of a run,
+// for the outputs depending on it to be written again once it changed.
+func addInputFingerprint(options, filePath string) (string, error) {
+	hash, err := hashFile(filePath)
+	if err != nil {
+		return "", fmt.Errorf("error hashing %s: %w", filePath, err)
+	}
+	return strings.TrimSpace(options + " " + filepath.Base(filePath) + "=" + hash), nil
+}
+
+// incrementalState tracks the input files which did not change since the
+// last run, whose outputs do not need to be written again.
+type incrementalState struct {
+	previous manifest
+
+	mu      sync.Mutex
+	current manifest
+}
+
+// newIncrementalState starts from the manifest of the last run when
+// incremental is set, unless the last run had different options. Otherwise
+// every file is considered changed, hashes being only recorded for the next
+// run.
+func newIncrementalState(outputFolderPath, options string, incremental bool) (*incrementalState, error) {
+	previous := manifest{Inputs: map[string]string{}}
+	if incremental {
+		var err error
+		previous, err = readManifest(outputFolderPath)
+		if err != nil {
+			return nil, err
+		}
+		if previous.Options != options {
+			previous.Inputs = map[string]string{}
+		}
+	}
+
+	return &incrementalState{
+		previous: previous,
+		current:  manifest{Options: options, StartedAt: time.Now().UTC(), Inputs: map[string]string{}},
+	}, nil
+}
+
+// describeInputs records the game and parser the output is produced from,
+// gameVersion overriding the version read from the data folder when set.
+func (s *incrementalState) describeInputs(dofusDataFolderPath, gameVersion string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	game, err := parser.DetectGameVersion(dofusDataFolderPath)
+	if err != nil {
+		slog.Warn("error detecting game", "error", err)
+	}
+	s.current.Game = game.String()
+	s.current.GameVersion = gameVersion
+	if gameVersion == "" {
+		s.current.GameVersion = readGameVersion(dofusDataFolderPath)
+	}
+	s.current.ParserVersion = parserVersion()
+}
+
+// check hashes an input file, inputPath being its path relative to the data
+// folder, and tells whether it is unchanged since the last run. A nil state
+// considers every file changed.
+func (s *incrementalState) check(filePath, inputPath string) (hash string, unchanged bool, err error) {
+	if s == nil {
+		return "", false, nil
+	}
+
+	hash, err = hashFile(filePath)
+	if err != nil {
+		return "", false, err
+	}
+	return hash, s.previous.Inputs[filepath.ToSlash(inputPath)] == hash, nil
+}
+
+// record marks an input file as successfully processed.
+func (s *incrementalState) record(inputPath, hash string) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.current.Inputs[filepath.ToSlash(inputPath)] = hash
+}
+
+// write writes the manifest of the run, listing the files of the output
+// folder.
+func (s *incrementalState) write(outputFolderPath string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	outputs, err := hashOutputFiles(outputFolderPath)
+	if err != nil {
+		return err
+	}
+	s.current.Outputs = outputs
+	s.current.FinishedAt = time.Now().UTC()
+	return writeManifest(s.current, outputFolderPath)
+}
+
+// hashOutputFiles hashes the files of the output folder, except the manifest.
+func hashOutputFiles(outputFolderPath string) (map[string]string, error) {
+	outputs := map[string]string{}
+	err := filepath.WalkDir(outputFolderPath, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		outputPath, err := filepath.Rel(outputFolderPath, filePath)
+		if err != nil || outputPath == manifestFileName {
+			return err
+		}
+
+		hash, err := hashFile(filePath)
+		if err != nil {
+			return err
+		}
+		outputs[filepath.ToSlash(outputPath)] = hash
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing output files: %w", err)
+	}
+	return outputs, nil
+}
+
+// readGameVersion returns the version of the game written in the VERSION file
+// of the data folder or of its parent, the installation folder, as the fetch
+// command writes it. It is empty when there is none.
+func readGameVersion(dofusDataFolderPath string) string {
+	for _, folderPath := range []string{dofusDataFolderPath, filepath.Dir(dofusDataFolderPath)} {
+		content, err := os.ReadFile(filepath.Join(folderPath, gameVersionFileName))
+		if err == nil {
+			return strings.TrimSpace(string(content))
+		}
+	}
+	return ""
+}
+
+// parserVersion returns the version of the module the command is built from,
+// with its VCS revision when known.
+func parserVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+
+	version := info.Main.Version
+	for _, setting := range info.Settings {
+		if setting.Key == "vcs.revision" {
+			version += " (" + setting.Value + ")"
+		}
+	}
+	return version
+}
+
+func hashFile(filePath string) (string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", fmt.Errorf("error opening file: %w", err)
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	_, err = io.Copy(hash, file)
+	if err != nil {
+		return "", fmt.Errorf("error hashing file: %w", err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
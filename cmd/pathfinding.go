@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/brequet/dofus-data-file-parser/pkg/parser"
+	"github.com/brequet/dofus-data-file-parser/pkg/pathfinding"
+)
+
+func runPathfindingCommand(ctx context.Context, args []string) error {
+	flagSet, debug := newFlagSet("pathfinding", "mapsFolderPath outputFolderPath")
+	writeBinary := flagSet.Bool("binary", false, "also write the maps and transitions in the compact binary form of graph.bin")
+	failFast := flagSet.Bool("fail-fast", false, "stop at the first map which fails to be read")
+	err := parseFlags(flagSet, debug, args, 2)
+	if err != nil {
+		return err
+	}
+
+	mapsFolderPath := flagSet.Arg(0)
+	outputFolderPath := flagSet.Arg(1)
+
+	err = os.MkdirAll(filepath.Join(outputFolderPath, "maps"), 0755)
+	if err != nil {
+		return fmt.Errorf("error creating output folder: %w", err)
+	}
+
+	report := newFailureReport(*failFast)
+	graphs := make([]pathfinding.MapGraph, 0)
+	transitions := make([]pathfinding.Transition, 0)
+	err = forEachDlmMap(mapsFolderPath, report, func(name string, dlmMap parser.DlmMap) bool {
+		if ctx.Err() != nil || report.stopped() {
+			return false
+		}
+
+		graph := pathfinding.BuildMapGraph(dlmMap)
+		err := writeJSONFile(graph, filepath.Join(outputFolderPath, "maps", fmt.Sprintf("%d.json", dlmMap.Id)))
+		if err != nil {
+			report.add(name, fmt.Errorf("error writing map graph: %w", err))
+			return true
+		}
+		transitions = append(transitions, pathfinding.Transitions(dlmMap)...)
+		if *writeBinary {
+			graphs = append(graphs, graph)
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	err = writeJSONFile(transitions, filepath.Join(outputFolderPath, "world.json"))
+	if err != nil {
+		return fmt.Errorf("error writing world graph: %w", err)
+	}
+	if *writeBinary {
+		err = writeGraphBinary(filepath.Join(outputFolderPath, "graph.bin"), graphs, transitions)
+		if err != nil {
+			return err
+		}
+	}
+	slog.Info("graphs written", "transitions", len(transitions))
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return report.summarize()
+}
+
+func writeGraphBinary(outputPath string, graphs []pathfinding.MapGraph, transitions []pathfinding.Transition) error {
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("error creating file: %w", err)
+	}
+	defer file.Close()
+
+	err = pathfinding.WriteBinary(file, graphs, transitions)
+	if err != nil {
+		return fmt.Errorf("error writing binary graph: %w", err)
+	}
+	return file.Close()
+}
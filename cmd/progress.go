@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/brequet/dofus-data-file-parser/pkg/parser"
+)
+
+const progressBarWidth = 30
+
+// progressBar renders the progress of a run on a single terminal line. It
+// implements parser.Progress, and its methods do nothing on a nil bar so that
+// callers need not check whether the progress is shown.
+type progressBar struct {
+	w io.Writer
+
+	files   atomic.Int64
+	parsed  atomic.Int64
+	bytes   atomic.Int64
+	objects atomic.Int64
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+// startProgressBar renders the progress to w until stop is called.
+func startProgressBar(w io.Writer) *progressBar {
+	b := &progressBar{
+		w:    w,
+		done: make(chan struct{}),
+	}
+
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		ticker := time.NewTicker(200 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				b.render()
+			case <-b.done:
+				return
+			}
+		}
+	}()
+
+	return b
+}
+
+// stop renders the final progress and ends its line.
+func (b *progressBar) stop() {
+	if b == nil {
+		return
+	}
+
+	close(b.done)
+	b.wg.Wait()
+	b.render()
+	fmt.Fprintln(b.w)
+}
+
+// parseOptions returns the parser options of the command, reporting to the bar.
+func (b *progressBar) parseOptions() parser.ParseOptions {
+	options := parseOptions()
+	if b != nil {
+		options.Progress = b
+	}
+	return options
+}
+
+func (b *progressBar) FilesDiscovered(count int) {
+	if b != nil {
+		b.files.Add(int64(count))
+	}
+}
+
+func (b *progressBar) FileParsed(string) {
+	if b != nil {
+		b.parsed.Add(1)
+	}
+}
+
+func (b *progressBar) BytesRead(count int64) {
+	if b != nil {
+		b.bytes.Add(count)
+	}
+}
+
+func (b *progressBar) ObjectsDecoded(count int) {
+	if b != nil {
+		b.objects.Add(int64(count))
+	}
+}
+
+func (b *progressBar) render() {
+	files, parsed := b.files.Load(), b.parsed.Load()
+	filled := 0
+	if files > 0 {
+		filled = int(min(parsed, files) * progressBarWidth / files)
+	}
+
+	bar := strings.Repeat("=", filled) + strings.Repeat(" ", progressBarWidth-filled)
+	// \033[K clears what is left of a longer previous line
+	fmt.Fprintf(b.w, "\r[%s] %d/%d files, %.1f MiB read, %d objects\033[K", bar, parsed, files, float64(b.bytes.Load())/(1<<20), b.objects.Load())
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"image/png"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+
+	"github.com/brequet/dofus-data-file-parser/pkg/maprender"
+	"github.com/brequet/dofus-data-file-parser/pkg/parser"
+)
+
+func runRenderMapCommand(ctx context.Context, args []string) error {
+	flagSet, debug := newFlagSet("render-map", "mapsFolderPath gfxFolderPath outputFolderPath")
+	mapIds := flagSet.String("map-ids", "", "comma separated ids of the maps to render, all when empty")
+	layers := flagSet.String("layers", "", "comma separated ids of the layers to draw (0 ground, 1 ground decorations, 2 objects), all when empty")
+	grid := flagSet.Bool("grid", false, "outline the cells")
+	walkable := flagSet.Bool("walkable", false, "fill the cells which cannot be walked on in red")
+	los := flagSet.Bool("los", false, "fill the cells blocking the line of sight in blue")
+	failFast := flagSet.Bool("fail-fast", false, "stop at the first map which fails to be rendered")
+	err := parseFlags(flagSet, debug, args, 3)
+	if err != nil {
+		return err
+	}
+
+	mapsFolderPath := flagSet.Arg(0)
+	gfxFolderPath := flagSet.Arg(1)
+	outputFolderPath := flagSet.Arg(2)
+
+	selectedMapIds, err := parseIntList(*mapIds)
+	if err != nil {
+		return fmt.Errorf("invalid map ids: %w", err)
+	}
+	options := maprender.Options{Grid: *grid, Walkable: *walkable, LineOfSight: *los}
+	options.Layers, err = parseIntList(*layers)
+	if err != nil {
+		return fmt.Errorf("invalid layers: %w", err)
+	}
+
+	elements, err := parser.ProcessEleFile(filepath.Join(mapsFolderPath, eleFileName), parseOptions())
+	if err != nil {
+		return fmt.Errorf("error reading elements: %w", err)
+	}
+	gfx, err := maprender.LoadArchiveGfx(gfxFolderPath, parseOptions())
+	if err != nil {
+		return fmt.Errorf("error reading gfx archives: %w", err)
+	}
+	slog.Info("gfx loaded", "elements", len(elements.Elements), "images", gfx.Len())
+
+	err = os.MkdirAll(outputFolderPath, 0755)
+	if err != nil {
+		return fmt.Errorf("error creating output folder: %w", err)
+	}
+
+	renderer := maprender.NewRenderer(elements, gfx)
+	report := newFailureReport(*failFast)
+	renderedCount := 0
+	err = forEachDlmMap(mapsFolderPath, report, func(name string, dlmMap parser.DlmMap) bool {
+		if ctx.Err() != nil || report.stopped() {
+			return false
+		}
+		if len(selectedMapIds) > 0 && !slices.Contains(selectedMapIds, int(dlmMap.Id)) {
+			return true
+		}
+
+		img, err := renderer.Render(dlmMap, options)
+		if err != nil {
+			report.add(name, err)
+			return true
+		}
+		err = writePNGFile(img, filepath.Join(outputFolderPath, fmt.Sprintf("%d.png", dlmMap.Id)))
+		if err != nil {
+			report.add(name, err)
+			return true
+		}
+		renderedCount++
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	if missing := renderer.MissingGfx(); len(missing) > 0 {
+		slog.Warn("elements without image skipped", "gfx count", len(missing))
+		slog.Debug("missing gfx", "ids", missing)
+	}
+	slog.Info("maps rendered", "count", renderedCount)
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return report.summarize()
+}
+
+// forEachDlmMap calls process with the maps of the .d2p archives of a folder,
+// adding the archives and maps which cannot be read to the report, until
+// process returns false.
+func forEachDlmMap(mapsFolderPath string, report *failureReport, process func(name string, dlmMap parser.DlmMap) bool) error {
+	fileNames, err := listFilesWithExtension(mapsFolderPath, ".d2p")
+	if err != nil {
+		return err
+	}
+
+	for _, fileName := range fileNames {
+		archive, err := parser.ProcessD2pFile(filepath.Join(mapsFolderPath, fileName), parseOptions())
+		if err != nil {
+			report.add(fileName, err)
+			continue
+		}
+
+		for _, entry := range archive.Entries {
+			if filepath.Ext(entry.Name) != ".dlm" {
+				continue
+			}
+
+			dlmMap, err := parser.ParseDlm(archive.Read(entry), parser.DefaultDlmKey, parseOptions())
+			if err != nil {
+				report.add(fileName+"/"+entry.Name, fmt.Errorf("error parsing map: %w", err))
+				continue
+			}
+			if !process(fileName+"/"+entry.Name, dlmMap) {
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+func writePNGFile(img *image.RGBA, outputPath string) error {
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("error creating file: %w", err)
+	}
+	defer file.Close()
+
+	err = png.Encode(file, img)
+	if err != nil {
+		return fmt.Errorf("error encoding png: %w", err)
+	}
+	return file.Close()
+}
+
+// parseIntList parses comma separated integers.
+func parseIntList(list string) ([]int, error) {
+	values := make([]int, 0)
+	for _, item := range splitPatterns(list) {
+		value, err := strconv.Atoi(item)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+)
+
+// failureReport collects the files which failed to be processed during a
+// run, so that the command can exit with a non-zero status.
+type failureReport struct {
+	failFast bool
+	aborted  atomic.Bool
+
+	mu       sync.Mutex
+	failures []fileFailure
+}
+
+type fileFailure struct {
+	file string
+	err  error
+}
+
+func newFailureReport(failFast bool) *failureReport {
+	return &failureReport{
+		failFast: failFast,
+	}
+}
+
+// add logs and records the failure of a file, aborting the run when failing
+// fast.
+func (r *failureReport) add(file string, err error) {
+	slog.Error("error processing file", "file", file, "error", err)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.failures = append(r.failures, fileFailure{file: file, err: err})
+	if r.failFast {
+		r.aborted.Store(true)
+	}
+}
+
+// stopped tells whether remaining files must be skipped.
+func (r *failureReport) stopped() bool {
+	return r.aborted.Load()
+}
+
+// summarize logs the failed files and returns an error if there is any.
+func (r *failureReport) summarize() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.failures) == 0 {
+		return nil
+	}
+
+	for _, failure := range r.failures {
+		slog.Warn("failed file", "file", failure.file, "error", failure.err)
+	}
+	if r.aborted.Load() {
+		return fmt.Errorf("run aborted after %d failed file(s)", len(r.failures))
+	}
+	return fmt.Errorf("%d file(s) failed", len(r.failures))
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+
+	"github.com/brequet/dofus-data-file-parser/pkg/retro"
+)
+
+// retroTranslationsName is the name of the lang files holding the texts of the
+// interface, exported as translations rather than modules.
+const retroTranslationsName = "lang"
+
+// processRetroFolder exports the lang files of a Dofus Retro data folder:
+// the modules of the given locale to the common folder, as <name>.json, and
+// the texts of every locale to the translation folder, as <locale>.json.
+func processRetroFolder(ctx context.Context, dataFolderPath, outputFolderPath, locale string, report *failureReport) error {
+	files, err := retro.LatestLangFiles(dataFolderPath)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		if ctx.Err() != nil || report.stopped() {
+			break
+		}
+
+		var outputPath string
+		switch {
+		case file.Name == retroTranslationsName:
+			outputPath = filepath.Join(outputFolderPath, "translation", file.Locale+".json")
+		case file.Locale == locale:
+			outputPath = filepath.Join(outputFolderPath, "common", file.Name+".json")
+		default:
+			continue
+		}
+
+		slog.Info("processing lang file", "file", filepath.Base(file.Path))
+		variables, err := retro.ProcessLangFile(file.Path)
+		if err != nil {
+			report.add(filepath.Base(file.Path), fmt.Errorf("error parsing lang file: %w", err))
+			continue
+		}
+		err = writeJSONFile(variables, outputPath)
+		if err != nil {
+			report.add(filepath.Base(file.Path), fmt.Errorf("error writing file: %w", err))
+		}
+	}
+	return nil
+}
+
+// runRetroParse is the parse command for a Dofus Retro data folder, whose
+// lang files have no class types nor schemas to export.
+func runRetroParse(ctx context.Context, flagSet *flag.FlagSet, dataFolderPath, outputFolderPath, locale, gameVersion string, clean, failFast bool) error {
+	err := prepareOutputFolder(outputFolderPath, "common", clean)
+	if err != nil {
+		return fmt.Errorf("error preparing output folder: %w", err)
+	}
+	state, err := newIncrementalState(outputFolderPath, optionsFingerprint(flagSet), false)
+	if err != nil {
+		return err
+	}
+	state.describeInputs(dataFolderPath, gameVersion)
+
+	report := newFailureReport(failFast)
+	err = processRetroFolder(ctx, dataFolderPath, outputFolderPath, locale, report)
+	if err != nil {
+		return fmt.Errorf("error processing lang folder: %w", err)
+	}
+
+	err = state.write(outputFolderPath)
+	if err != nil {
+		return fmt.Errorf("error writing manifest: %w", err)
+	}
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return report.summarize()
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/brequet/dofus-data-file-parser/pkg/parser"
+)
+
+func runSwlCommand(ctx context.Context, args []string) error {
+	flagSet, debug := newFlagSet("swl", "swlPath outputFolderPath")
+	failFast := flagSet.Bool("fail-fast", false, "stop at the first file which fails to be processed")
+	err := parseFlags(flagSet, debug, args, 2)
+	if err != nil {
+		return err
+	}
+
+	swlPath := flagSet.Arg(0)
+	outputFolderPath := flagSet.Arg(1)
+
+	report := newFailureReport(*failFast)
+	err = extractSwlFiles(ctx, swlPath, outputFolderPath, report)
+	if err != nil {
+		return err
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	return report.summarize()
+}
+
+// extractSwlFiles writes the SWF movie of a SWL file, or of every SWL file of
+// a folder tree, as <name>.swf next to <name>.json listing its classes and
+// frames, keeping the paths relative to the folder.
+func extractSwlFiles(ctx context.Context, swlPath, outputFolderPath string, report *failureReport) error {
+	info, err := os.Stat(swlPath)
+	if err != nil {
+		return fmt.Errorf("error reading swl path: %w", err)
+	}
+	rootPath := swlPath
+	if !info.IsDir() {
+		rootPath = filepath.Dir(swlPath)
+	}
+
+	fileCount := 0
+	err = filepath.WalkDir(swlPath, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if ctx.Err() != nil || report.stopped() {
+			return filepath.SkipAll
+		}
+		if entry.IsDir() || filepath.Ext(filePath) != ".swl" {
+			return nil
+		}
+
+		relativePath, err := filepath.Rel(rootPath, filePath)
+		if err != nil {
+			return err
+		}
+		swl, err := parser.ProcessSwlFile(filePath, parseOptions())
+		if err != nil {
+			report.add(relativePath, err)
+			return nil
+		}
+
+		outputPath := filepath.Join(outputFolderPath, strings.TrimSuffix(relativePath, ".swl"))
+		err = os.MkdirAll(filepath.Dir(outputPath), 0755)
+		if err != nil {
+			return fmt.Errorf("error creating folder: %w", err)
+		}
+		err = os.WriteFile(outputPath+".swf", swl.Swf, 0644)
+		if err != nil {
+			report.add(relativePath, fmt.Errorf("error writing file: %w", err))
+			return nil
+		}
+		err = writeJSONFile(swl, outputPath+".json")
+		if err != nil {
+			report.add(relativePath, fmt.Errorf("error writing file: %w", err))
+			return nil
+		}
+		fileCount++
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error listing swl files: %w", err)
+	}
+	slog.Info("swl files extracted", "count", fileCount)
+
+	return nil
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strings"
+
+	"github.com/brequet/dofus-data-file-parser/pkg/parser"
+)
+
+// unitySerializedFileFlag is the flag of the bundle entries which are
+// serialized files, the others being the resources they refer to.
+const unitySerializedFileFlag = 4
+
+// processUnityFolder exports the asset bundles of a Dofus 3 data folder: the
+// entries of each bundle are written to unity/<bundle>/ and listed in
+// unity/bundles.json, and the MonoBehaviours of their serialized files are
+// decoded into modules written to the common folder, as <class>.json.
+func processUnityFolder(ctx context.Context, dataFolderPath, outputFolderPath string, report *failureReport) error {
+	fileNames, err := listFilesWithExtension(dataFolderPath, ".bundle")
+	if err != nil {
+		return err
+	}
+
+	bundles := map[string]parser.UnityBundle{}
+	serializedFiles := map[string]parser.UnitySerializedFile{}
+	for _, fileName := range fileNames {
+		if ctx.Err() != nil || report.stopped() {
+			break
+		}
+
+		slog.Info("processing bundle", "file", fileName)
+		bundle, err := parser.ProcessUnityBundleFile(filepath.Join(dataFolderPath, fileName), parseOptions())
+		if err != nil {
+			report.add(fileName, err)
+			continue
+		}
+
+		err = bundle.ExtractAll(filepath.Join(outputFolderPath, "unity", strings.TrimSuffix(fileName, ".bundle")))
+		if err != nil {
+			report.add(fileName, fmt.Errorf("error extracting bundle: %w", err))
+			continue
+		}
+		bundles[fileName] = bundle
+
+		for _, entry := range bundle.Entries {
+			if entry.Flags&unitySerializedFileFlag == 0 {
+				continue
+			}
+			serializedFile, err := parser.ParseUnitySerializedFile(bundle.Read(entry), parseOptions())
+			if err != nil {
+				report.add(fileName, fmt.Errorf("error parsing serialized file %s: %w", entry.Name, err))
+				continue
+			}
+			serializedFiles[filepath.Base(entry.Name)] = serializedFile
+		}
+	}
+
+	err = writeJSONFile(bundles, filepath.Join(outputFolderPath, "unity", "bundles.json"))
+	if err != nil {
+		return err
+	}
+
+	modules, err := parser.UnityModules(serializedFiles, parseOptions())
+	if err != nil {
+		report.add("modules", err)
+		return nil
+	}
+	for className, objects := range modules {
+		if !filepath.IsLocal(className) || filepath.Base(className) != className {
+			report.add(className, fmt.Errorf("invalid class name: %s", className))
+			continue
+		}
+		slog.Info("writing module", "class", className, "objects", len(objects))
+		err = writeJSONFile(objects, filepath.Join(outputFolderPath, "common", className+".json"))
+		if err != nil {
+			report.add(className, fmt.Errorf("error writing file: %w", err))
+		}
+	}
+	return nil
+}
+
+// runUnityParse is the parse command for a Dofus 3 data folder, whose modules
+// are decoded from the MonoBehaviours of its asset bundles. Their class types,
+// schemas and translations are not exported.
+func runUnityParse(ctx context.Context, flagSet *flag.FlagSet, dataFolderPath, outputFolderPath, gameVersion string, clean, failFast bool) error {
+	err := prepareOutputFolder(outputFolderPath, "unity", clean)
+	if err != nil {
+		return fmt.Errorf("error preparing output folder: %w", err)
+	}
+	state, err := newIncrementalState(outputFolderPath, optionsFingerprint(flagSet), false)
+	if err != nil {
+		return err
+	}
+	state.describeInputs(dataFolderPath, gameVersion)
+	slog.Warn("the translations of Dofus 3 are not decoded, only its modules are exported")
+
+	report := newFailureReport(failFast)
+	err = processUnityFolder(ctx, dataFolderPath, outputFolderPath, report)
+	if err != nil {
+		return fmt.Errorf("error processing bundles: %w", err)
+	}
+
+	err = state.write(outputFolderPath)
+	if err != nil {
+		return fmt.Errorf("error writing manifest: %w", err)
+	}
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return report.summarize()
+}
@@ -1,5 +1,9 @@
 module github.com/brequet/dofus-data-file-parser
 
-go 1.22.0
+go 1.23.0
 
 require golang.org/x/text v0.16.0
+
+require github.com/mattn/go-sqlite3 v1.14.22
+
+require github.com/klauspost/compress v1.17.11
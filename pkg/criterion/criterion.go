@@ -0,0 +1,173 @@
+// Package criterion parses the criterion expressions of items, quests and
+// other game data, such as "PO>3&BI=0", into a syntax tree.
+package criterion
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Node is a node of a criterion expression: a Criterion or a Group.
+type Node interface {
+	node()
+}
+
+// Criterion is a single condition, e.g. "PL>10" has the id "PL", the operator
+// ">" and the value "10".
+type Criterion struct {
+	Id       string `json:"id"`
+	Operator string `json:"operator"`
+	Value    string `json:"value"`
+}
+
+// Group combines conditions with the "&" (and) or "|" (or) operator.
+type Group struct {
+	Operator string `json:"operator"`
+	Operands []Node `json:"operands"`
+}
+
+func (Criterion) node() {}
+func (Group) node()     {}
+
+// operators of a single criterion, between its id and its value
+const criterionOperators = "=!<>~"
+
+// Parse parses a criterion expression. "&" binds tighter than "|", and
+// parentheses group conditions. It returns nil for an empty expression.
+func Parse(expression string) (Node, error) {
+	tokens := tokenize(expression)
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+
+	p := &exprParser{tokens: tokens}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, fmt.Errorf("error parsing criterion %q: %w", expression, err)
+	}
+	if p.position < len(p.tokens) {
+		return nil, fmt.Errorf("error parsing criterion %q: unexpected %q", expression, p.tokens[p.position])
+	}
+	return node, nil
+}
+
+// tokenize splits an expression into parentheses, operators and criteria.
+func tokenize(expression string) []string {
+	tokens := make([]string, 0)
+	start := 0
+	for i, c := range expression {
+		if !strings.ContainsRune("()&|", c) {
+			continue
+		}
+		if criterion := strings.TrimSpace(expression[start:i]); criterion != "" {
+			tokens = append(tokens, criterion)
+		}
+		tokens = append(tokens, string(c))
+		start = i + 1
+	}
+	if criterion := strings.TrimSpace(expression[start:]); criterion != "" {
+		tokens = append(tokens, criterion)
+	}
+	return tokens
+}
+
+type exprParser struct {
+	tokens   []string
+	position int
+}
+
+func (p *exprParser) peek() string {
+	if p.position >= len(p.tokens) {
+		return ""
+	}
+	return p.tokens[p.position]
+}
+
+func (p *exprParser) parseOr() (Node, error) {
+	return p.parseGroup("|", p.parseAnd)
+}
+
+func (p *exprParser) parseAnd() (Node, error) {
+	return p.parseGroup("&", p.parseOperand)
+}
+
+// parseGroup parses operands separated by operator, returning the operand
+// itself when there is a single one.
+func (p *exprParser) parseGroup(operator string, parseOperand func() (Node, error)) (Node, error) {
+	operand, err := parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	operands := []Node{operand}
+	for p.peek() == operator {
+		p.position++
+		operand, err = parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, operand)
+	}
+
+	if len(operands) == 1 {
+		return operand, nil
+	}
+	return Group{Operator: operator, Operands: operands}, nil
+}
+
+func (p *exprParser) parseOperand() (Node, error) {
+	token := p.peek()
+	switch token {
+	case "":
+		return nil, fmt.Errorf("unexpected end of expression")
+	case "&", "|", ")":
+		return nil, fmt.Errorf("unexpected %q", token)
+	case "(":
+		p.position++
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+		p.position++
+		return node, nil
+	}
+
+	p.position++
+	return parseCriterion(token)
+}
+
+func parseCriterion(token string) (Criterion, error) {
+	index := strings.IndexAny(token, criterionOperators)
+	if index <= 0 {
+		return Criterion{}, fmt.Errorf("invalid criterion %q", token)
+	}
+
+	return Criterion{
+		Id:       token[:index],
+		Operator: token[index : index+1],
+		Value:    token[index+1:],
+	}, nil
+}
+
+// String formats a node back to the criterion syntax.
+func String(node Node) string {
+	switch n := node.(type) {
+	case Criterion:
+		return n.Id + n.Operator + n.Value
+	case Group:
+		operands := make([]string, 0, len(n.Operands))
+		for _, operand := range n.Operands {
+			operandString := String(operand)
+			if group, ok := operand.(Group); ok && group.Operator != n.Operator {
+				operandString = "(" + operandString + ")"
+			}
+			operands = append(operands, operandString)
+		}
+		return strings.Join(operands, n.Operator)
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,149 @@
+package criterion
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/brequet/dofus-data-file-parser/pkg/parser"
+)
+
+// DefaultLabels names the most common criteria.
+var DefaultLabels = map[string]string{
+	"CA": "Agility",
+	"CC": "Chance",
+	"CI": "Intelligence",
+	"CS": "Strength",
+	"CV": "Vitality",
+	"CW": "Wisdom",
+	"Ca": "Base agility",
+	"Cc": "Base chance",
+	"Ci": "Base intelligence",
+	"Cs": "Base strength",
+	"Cv": "Base vitality",
+	"Cw": "Base wisdom",
+	"PG": "Breed",
+	"PK": "Kamas",
+	"PL": "Level",
+	"PO": "Item",
+	"PS": "Sex",
+	"Pa": "Alignment level",
+	"Pj": "Job",
+	"Ps": "Alignment",
+	"Qa": "Active quest",
+	"Qf": "Finished quest",
+}
+
+var operatorTexts = map[string]string{
+	"=": "=",
+	"!": "≠",
+	"<": "<",
+	">": ">",
+	"~": "~",
+}
+
+// Renderer formats criterion expressions as human readable text.
+type Renderer struct {
+	// Labels names the criteria by id, DefaultLabels being used when nil.
+	// Criteria without a label keep their id.
+	Labels map[string]string
+	// Values, when set, formats the value of a criterion, e.g. the name of an
+	// item from the i18n data for "PO" criteria. Values for which it returns
+	// false are kept as is.
+	Values func(criterion Criterion) (string, bool)
+}
+
+// Render formats a node, e.g. "PL>10&PO=42" as "Level > 10 and Item = 42".
+func (r Renderer) Render(node Node) string {
+	switch n := node.(type) {
+	case Criterion:
+		return r.renderCriterion(n)
+	case Group:
+		separator := " and "
+		if n.Operator == "|" {
+			separator = " or "
+		}
+		operands := make([]string, 0, len(n.Operands))
+		for _, operand := range n.Operands {
+			text := r.Render(operand)
+			if _, ok := operand.(Group); ok {
+				text = "(" + text + ")"
+			}
+			operands = append(operands, text)
+		}
+		return strings.Join(operands, separator)
+	default:
+		return ""
+	}
+}
+
+func (r Renderer) renderCriterion(criterion Criterion) string {
+	labels := r.Labels
+	if labels == nil {
+		labels = DefaultLabels
+	}
+
+	label, ok := labels[criterion.Id]
+	if !ok {
+		label = criterion.Id
+	}
+	operator, ok := operatorTexts[criterion.Operator]
+	if !ok {
+		operator = criterion.Operator
+	}
+	value := criterion.Value
+	if r.Values != nil {
+		if text, ok := r.Values(criterion); ok {
+			value = text
+		}
+	}
+
+	return label + " " + operator + " " + value
+}
+
+// TranslatedValues returns a Renderer.Values function naming the values of the
+// given criteria ids, which must be integer ids, with names, e.g. the item
+// names for "PO" criteria.
+func TranslatedValues(criterionIds []string, names map[int]string) func(Criterion) (string, bool) {
+	return func(criterion Criterion) (string, bool) {
+		if !slices.Contains(criterionIds, criterion.Id) {
+			return "", false
+		}
+		id, err := strconv.Atoi(criterion.Value)
+		if err != nil {
+			return "", false
+		}
+		name, ok := names[id]
+		return name, ok
+	}
+}
+
+// Enrich adds the syntax tree and the description of the criteria fields of
+// an object to its JSON export, as "<field>Tree" and "<field>Description"
+// entries. Criteria fields are the string fields whose name contains
+// "criteri", e.g. "criteria" or "usabilityCriterions".
+func (r Renderer) Enrich(object parser.Object) map[string]any {
+	objectMap, ok := object.(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	var entries map[string]any
+	for key, value := range objectMap {
+		expression, ok := value.(string)
+		if !ok || !strings.Contains(strings.ToLower(key), "criteri") {
+			continue
+		}
+
+		node, err := Parse(expression)
+		if err != nil || node == nil {
+			continue
+		}
+		if entries == nil {
+			entries = map[string]any{}
+		}
+		entries[key+"Tree"] = node
+		entries[key+"Description"] = r.Render(node)
+	}
+	return entries
+}
@@ -0,0 +1,267 @@
+// Package cytrus downloads game files from Cytrus, the CDN the Ankama Launcher
+// installs and updates the games from, so that their data can be parsed
+// without the game being installed.
+package cytrus
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+const DefaultBaseURL = "https://cytrus.cdn.ankama.com"
+
+// ErrHashMismatch is returned when a downloaded file does not have the hash
+// of the manifest.
+var ErrHashMismatch = errors.New("hash mismatch")
+
+// Client downloads files from Cytrus.
+type Client struct {
+	// BaseURL is the URL of the CDN, DefaultBaseURL when empty.
+	BaseURL string
+	// HTTPClient sends the requests, http.DefaultClient when nil.
+	HTTPClient *http.Client
+}
+
+// Versions maps the games (e.g. "dofus") to their platforms ("windows",
+// "darwin", "linux"), then to the current version of each release ("main",
+// "beta").
+type Versions map[string]map[string]map[string]string
+
+// Versions returns the current versions of the games.
+func (c Client) Versions(ctx context.Context) (Versions, error) {
+	content, err := c.get(ctx, "cytrus.json")
+	if err != nil {
+		return nil, err
+	}
+
+	var index struct {
+		Games map[string]struct {
+			Platforms map[string]map[string]string `json:"platforms"`
+		} `json:"games"`
+	}
+	err = json.Unmarshal(content, &index)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling versions: %w", err)
+	}
+
+	versions := Versions{}
+	for game, gameIndex := range index.Games {
+		versions[game] = gameIndex.Platforms
+	}
+	return versions, nil
+}
+
+// Manifest returns the manifest of a version of a game.
+func (c Client) Manifest(ctx context.Context, game, release, platform, version string) (Manifest, error) {
+	content, err := c.get(ctx, fmt.Sprintf("%s/releases/%s/%s/%s.manifest", game, release, platform, version))
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	manifest, err := ParseManifest(content)
+	if err != nil {
+		return Manifest{}, fmt.Errorf("error parsing manifest: %w", err)
+	}
+	return manifest, nil
+}
+
+// Download writes files of the manifest to a folder, at their path in the
+// game, and checks their hash. Only the bundles holding their chunks are
+// downloaded.
+func (c Client) Download(ctx context.Context, game string, manifest Manifest, files []File, folderPath string) error {
+	// chunk hash -> bundle holding it, and where
+	chunkBundles := map[string]string{}
+	bundleChunks := map[string]Chunk{}
+	for _, fragment := range manifest.Fragments {
+		for _, bundle := range fragment.Bundles {
+			for _, chunk := range bundle.Chunks {
+				chunkBundles[hex.EncodeToString(chunk.Hash)] = hex.EncodeToString(bundle.Hash)
+				bundleChunks[hex.EncodeToString(chunk.Hash)] = chunk
+			}
+		}
+	}
+
+	// bundle hash -> chunk hash -> parts of the files made of the chunk
+	type filePart struct {
+		path   string
+		offset int64
+	}
+	neededBundles := map[string]map[string][]filePart{}
+	for _, file := range files {
+		if file.Symlink != "" {
+			continue
+		}
+		if !filepath.IsLocal(file.Name) {
+			return fmt.Errorf("invalid file path: %s", file.Name)
+		}
+
+		filePath := filepath.Join(folderPath, filepath.FromSlash(file.Name))
+		err := os.MkdirAll(filepath.Dir(filePath), 0755)
+		if err != nil {
+			return fmt.Errorf("error creating folder: %w", err)
+		}
+		// the file is written once its bundles are downloaded, this only
+		// truncates it
+		output, err := os.Create(filePath)
+		if err != nil {
+			return fmt.Errorf("error creating file: %w", err)
+		}
+		err = output.Close()
+		if err != nil {
+			return fmt.Errorf("error creating file: %w", err)
+		}
+
+		chunks := file.Chunks
+		if len(chunks) == 0 && file.Size > 0 {
+			chunks = []Chunk{{Hash: file.Hash, Size: file.Size}}
+		}
+		for _, chunk := range chunks {
+			chunkHash := hex.EncodeToString(chunk.Hash)
+			bundleHash, ok := chunkBundles[chunkHash]
+			if !ok || len(bundleHash) < 2 {
+				return fmt.Errorf("chunk %s of %s not found in bundles", chunkHash, file.Name)
+			}
+			if neededBundles[bundleHash] == nil {
+				neededBundles[bundleHash] = map[string][]filePart{}
+			}
+			neededBundles[bundleHash][chunkHash] = append(neededBundles[bundleHash][chunkHash], filePart{path: filePath, offset: chunk.Offset})
+		}
+	}
+
+	for _, bundleHash := range slices.Sorted(maps.Keys(neededBundles)) {
+		bundle, err := c.get(ctx, fmt.Sprintf("%s/bundles/%s/%s", game, bundleHash[:2], bundleHash))
+		if err != nil {
+			return err
+		}
+
+		// file path -> chunks of the bundle to write to it
+		fileChunks := map[string][]fileChunk{}
+		for chunkHash, parts := range neededBundles[bundleHash] {
+			chunk := bundleChunks[chunkHash]
+			if chunk.Offset < 0 || chunk.Size < 0 || chunk.Offset+chunk.Size > int64(len(bundle)) {
+				return fmt.Errorf("chunk %s out of bundle %s", chunkHash, bundleHash)
+			}
+			for _, part := range parts {
+				fileChunks[part.path] = append(fileChunks[part.path], fileChunk{data: bundle[chunk.Offset : chunk.Offset+chunk.Size], offset: part.offset})
+			}
+		}
+		for _, filePath := range slices.Sorted(maps.Keys(fileChunks)) {
+			err = writeFileChunks(filePath, fileChunks[filePath])
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, file := range files {
+		if file.Symlink != "" {
+			continue
+		}
+		err := checkFileHash(filepath.Join(folderPath, filepath.FromSlash(file.Name)), file.Hash)
+		if err != nil {
+			return fmt.Errorf("error checking %s: %w", file.Name, err)
+		}
+	}
+	return nil
+}
+
+// fileChunk is data of a bundle to write at an offset of a file.
+type fileChunk struct {
+	data   []byte
+	offset int64
+}
+
+// writeFileChunks writes chunks to an existing file, which is closed before
+// returning for the files of every bundle not to stay open.
+func writeFileChunks(filePath string, chunks []fileChunk) error {
+	file, err := os.OpenFile(filePath, os.O_WRONLY, 0)
+	if err != nil {
+		return fmt.Errorf("error opening file: %w", err)
+	}
+	for _, chunk := range chunks {
+		_, err = file.WriteAt(chunk.data, chunk.offset)
+		if err != nil {
+			file.Close()
+			return fmt.Errorf("error writing file: %w", err)
+		}
+	}
+	err = file.Close()
+	if err != nil {
+		return fmt.Errorf("error closing file: %w", err)
+	}
+	return nil
+}
+
+// FilesWithPrefix returns the files of the manifest whose path starts with one
+// of the prefixes, e.g. "data/common/".
+func FilesWithPrefix(manifest Manifest, prefixes ...string) []File {
+	files := make([]File, 0)
+	for _, fragment := range manifest.Fragments {
+		for _, file := range fragment.Files {
+			if slices.ContainsFunc(prefixes, func(prefix string) bool { return strings.HasPrefix(file.Name, prefix) }) {
+				files = append(files, file)
+			}
+		}
+	}
+	return files
+}
+
+func checkFileHash(filePath string, hash []byte) error {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	hasher := sha1.New()
+	_, err = io.Copy(hasher, file)
+	if err != nil {
+		return err
+	}
+	if sum := hasher.Sum(nil); !slices.Equal(sum, hash) {
+		return fmt.Errorf("%w: %x instead of %x", ErrHashMismatch, sum, hash)
+	}
+	return nil
+}
+
+func (c Client) get(ctx context.Context, path string) ([]byte, error) {
+	baseURL := c.BaseURL
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	url := strings.TrimSuffix(baseURL, "/") + "/" + path
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("error downloading %s: %w", url, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error downloading %s: %s", url, response.Status)
+	}
+	content, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error downloading %s: %w", url, err)
+	}
+	return content, nil
+}
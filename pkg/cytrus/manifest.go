@@ -0,0 +1,205 @@
+package cytrus
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// Manifest lists the files of a game version, grouped in fragments (the main
+// game files, the files of a language...).
+type Manifest struct {
+	Fragments []Fragment
+}
+
+type Fragment struct {
+	Name    string
+	Files   []File
+	Bundles []Bundle
+}
+
+// File is a file of the game. Its content is the concatenation of its chunks,
+// or a single chunk of the same hash as the file when it has none.
+type File struct {
+	Name       string
+	Size       int64
+	Hash       []byte // SHA-1
+	Chunks     []Chunk
+	Executable bool
+	Symlink    string
+}
+
+// Bundle is a file of the CDN holding chunks of game files.
+type Bundle struct {
+	Hash   []byte
+	Chunks []Chunk
+}
+
+// Chunk is a part of a file, or of a bundle, at the given offset.
+type Chunk struct {
+	Hash   []byte // SHA-1
+	Size   int64
+	Offset int64
+}
+
+var errInvalidManifest = errors.New("invalid manifest")
+
+// ParseManifest parses a manifest, serialized with FlatBuffers.
+func ParseManifest(data []byte) (Manifest, error) {
+	b := &flatBuffer{data: data}
+	root := b.table(b.reference(0))
+
+	manifest := Manifest{}
+	for _, fragmentTable := range root.tables(0) {
+		fragment := Fragment{Name: string(fragmentTable.bytes(0))}
+		for _, fileTable := range fragmentTable.tables(1) {
+			fragment.Files = append(fragment.Files, File{
+				Name:       string(fileTable.bytes(0)),
+				Size:       fileTable.int64(1),
+				Hash:       fileTable.bytes(2),
+				Chunks:     readChunks(fileTable.tables(3)),
+				Executable: fileTable.bool(4),
+				Symlink:    string(fileTable.bytes(5)),
+			})
+		}
+		for _, bundleTable := range fragmentTable.tables(2) {
+			fragment.Bundles = append(fragment.Bundles, Bundle{
+				Hash:   bundleTable.bytes(0),
+				Chunks: readChunks(bundleTable.tables(1)),
+			})
+		}
+		manifest.Fragments = append(manifest.Fragments, fragment)
+	}
+
+	if b.err != nil {
+		return Manifest{}, b.err
+	}
+	return manifest, nil
+}
+
+func readChunks(chunkTables []flatTable) []Chunk {
+	chunks := make([]Chunk, 0, len(chunkTables))
+	for _, chunkTable := range chunkTables {
+		chunks = append(chunks, Chunk{
+			Hash:   chunkTable.bytes(0),
+			Size:   chunkTable.int64(1),
+			Offset: chunkTable.int64(2),
+		})
+	}
+	return chunks
+}
+
+// flatBuffer reads FlatBuffers data. Reading out of bounds records an error
+// and yields zero values, like parser.DataInput.
+type flatBuffer struct {
+	data []byte
+	err  error
+}
+
+func (b *flatBuffer) read(pos, n int) []byte {
+	if b.err != nil {
+		return nil
+	}
+	if pos < 0 || n < 0 || pos+n > len(b.data) {
+		b.err = fmt.Errorf("%w: reading %d bytes at %#x", errInvalidManifest, n, pos)
+		return nil
+	}
+	return b.data[pos : pos+n]
+}
+
+func (b *flatBuffer) uint16(pos int) int {
+	data := b.read(pos, 2)
+	if data == nil {
+		return 0
+	}
+	return int(binary.LittleEndian.Uint16(data))
+}
+
+func (b *flatBuffer) uint32(pos int) int {
+	data := b.read(pos, 4)
+	if data == nil {
+		return 0
+	}
+	return int(binary.LittleEndian.Uint32(data))
+}
+
+// reference returns the position pointed to by the offset at pos.
+func (b *flatBuffer) reference(pos int) int {
+	return pos + b.uint32(pos)
+}
+
+func (b *flatBuffer) table(pos int) flatTable {
+	vtable := pos - int(int32(b.uint32(pos)))
+	return flatTable{b: b, pos: pos, vtable: vtable, vtableSize: b.uint16(vtable)}
+}
+
+type flatTable struct {
+	b          *flatBuffer
+	pos        int
+	vtable     int
+	vtableSize int
+}
+
+// field returns the position of the field of the given index, 0 when it is
+// absent from the table.
+func (t flatTable) field(index int) int {
+	entry := 4 + 2*index
+	if t.b.err != nil || entry+2 > t.vtableSize {
+		return 0
+	}
+	offset := t.b.uint16(t.vtable + entry)
+	if offset == 0 {
+		return 0
+	}
+	return t.pos + offset
+}
+
+func (t flatTable) int64(index int) int64 {
+	pos := t.field(index)
+	if pos == 0 {
+		return 0
+	}
+	data := t.b.read(pos, 8)
+	if data == nil {
+		return 0
+	}
+	return int64(binary.LittleEndian.Uint64(data))
+}
+
+func (t flatTable) bool(index int) bool {
+	pos := t.field(index)
+	if pos == 0 {
+		return false
+	}
+	data := t.b.read(pos, 1)
+	return data != nil && data[0] != 0
+}
+
+// bytes returns a string or a vector of bytes.
+func (t flatTable) bytes(index int) []byte {
+	pos := t.field(index)
+	if pos == 0 {
+		return nil
+	}
+	vector := t.b.reference(pos)
+	return t.b.read(vector+4, t.b.uint32(vector))
+}
+
+// tables returns a vector of tables.
+func (t flatTable) tables(index int) []flatTable {
+	pos := t.field(index)
+	if pos == 0 {
+		return nil
+	}
+	vector := t.b.reference(pos)
+	length := t.b.uint32(vector)
+	if t.b.read(vector+4, 4*length) == nil {
+		return nil
+	}
+
+	tables := make([]flatTable, 0, length)
+	for i := 0; i < length && t.b.err == nil; i++ {
+		tables = append(tables, t.b.table(t.b.reference(vector+4+4*i)))
+	}
+	return tables
+}
@@ -0,0 +1,91 @@
+// Package dataset builds denormalized datasets joining several modules of a
+// data folder with the texts of a locale, such as the item encyclopedia.
+package dataset
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+
+	"github.com/brequet/dofus-data-file-parser/pkg/parser"
+)
+
+// Dataset loads the modules of a Dofus data folder on demand, along with the
+// texts of a locale. It is safe for concurrent use.
+type Dataset struct {
+	dataFolderPath string
+	options        parser.ParseOptions
+	translations   parser.Translations
+
+	mu      sync.Mutex
+	modules map[string]parser.D2oData
+}
+
+// Open loads the texts of the locale from the data folder, modules being
+// loaded when first needed.
+func Open(dataFolderPath, locale string, opts ...parser.ParseOptions) (*Dataset, error) {
+	var options parser.ParseOptions
+	if len(opts) > 0 {
+		options = opts[0]
+	}
+
+	translations, err := parser.ProcessD2iFile(filepath.Join(dataFolderPath, "i18n", "i18n_"+locale+".d2i"), options)
+	if err != nil {
+		return nil, fmt.Errorf("error loading translations of locale %s: %w", locale, err)
+	}
+
+	return &Dataset{
+		dataFolderPath: dataFolderPath,
+		options:        options,
+		translations:   translations,
+		modules:        map[string]parser.D2oData{},
+	}, nil
+}
+
+// Module returns the parsed module of the given name, e.g. "Items".
+func (d *Dataset) Module(name string) (parser.D2oData, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if data, ok := d.modules[name]; ok {
+		return data, nil
+	}
+
+	data, err := parser.ProcessD2oFile(filepath.Join(d.dataFolderPath, "common", name+".d2o"), d.options)
+	if err != nil {
+		return parser.D2oData{}, fmt.Errorf("error parsing module %s: %w", name, err)
+	}
+	d.modules[name] = data
+	return data, nil
+}
+
+// Text returns the text with the given id, empty when there is none.
+func (d *Dataset) Text(id int) string {
+	return d.translations[id]
+}
+
+// decodeModule decodes the objects of a module into T values, keyed by the
+// id returned by key.
+func decodeModule[T any](d *Dataset, moduleName string, key func(T) int) (map[int]T, error) {
+	data, err := d.Module(moduleName)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[int]T, len(data.Objects))
+	for _, object := range data.Objects {
+		var value T
+		err = parser.DecodeInto(object, &value)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding object of module %s: %w", moduleName, err)
+		}
+		values[key(value)] = value
+	}
+	return values, nil
+}
+
+// NamedRef references an object of another module along with its name.
+type NamedRef struct {
+	Id   int    `json:"id"`
+	Name string `json:"name"`
+}
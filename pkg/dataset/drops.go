@@ -0,0 +1,136 @@
+package dataset
+
+import "sort"
+
+// MonsterDrops is the drop table of a monster.
+type MonsterDrops struct {
+	MonsterId int    `json:"monsterId"`
+	Name      string `json:"name"`
+	MinLevel  int    `json:"minLevel"`
+	MaxLevel  int    `json:"maxLevel"`
+	IsBoss    bool   `json:"isBoss"`
+	Drops     []Drop `json:"drops"`
+}
+
+// ItemDrops lists the monsters dropping an item.
+type ItemDrops struct {
+	ItemId    int    `json:"itemId"`
+	Name      string `json:"name"`
+	DroppedBy []Drop `json:"droppedBy"`
+}
+
+// Drop is an entry of a drop table, referencing the item in a monster drop
+// table and the monster in an item one.
+type Drop struct {
+	Item    *NamedRef `json:"item,omitempty"`
+	Monster *NamedRef `json:"monster,omitempty"`
+	// PercentByGrade is the drop rate for each of the 5 monster grades.
+	PercentByGrade []float64 `json:"percentByGrade"`
+	Count          int       `json:"count"`
+	Criteria       string    `json:"criteria,omitempty"`
+}
+
+type monster struct {
+	Id     int            `json:"id"`
+	NameId int            `json:"nameId"`
+	IsBoss bool           `json:"isBoss"`
+	Grades []monsterGrade `json:"grades"`
+	Drops  []monsterDrop  `json:"drops"`
+}
+
+type monsterGrade struct {
+	Level int `json:"level"`
+}
+
+type monsterDrop struct {
+	ObjectId             int     `json:"objectId"`
+	PercentDropForGrade1 float64 `json:"percentDropForGrade1"`
+	PercentDropForGrade2 float64 `json:"percentDropForGrade2"`
+	PercentDropForGrade3 float64 `json:"percentDropForGrade3"`
+	PercentDropForGrade4 float64 `json:"percentDropForGrade4"`
+	PercentDropForGrade5 float64 `json:"percentDropForGrade5"`
+	Count                int     `json:"count"`
+	Criteria             string  `json:"criteria"`
+}
+
+func (d monsterDrop) percentByGrade() []float64 {
+	return []float64{d.PercentDropForGrade1, d.PercentDropForGrade2, d.PercentDropForGrade3, d.PercentDropForGrade4, d.PercentDropForGrade5}
+}
+
+// BuildMonsterDrops joins the drops of the Monsters module with the Items
+// module, returning the drop table of each monster sorted by monster id.
+func BuildMonsterDrops(d *Dataset) ([]MonsterDrops, error) {
+	monsters, err := decodeModule(d, "Monsters", func(m monster) int { return m.Id })
+	if err != nil {
+		return nil, err
+	}
+	items, err := decodeModule(d, "Items", func(i item) int { return i.Id })
+	if err != nil {
+		return nil, err
+	}
+
+	tables := make([]MonsterDrops, 0, len(monsters))
+	for _, monster := range monsters {
+		table := MonsterDrops{
+			MonsterId: monster.Id,
+			Name:      d.Text(monster.NameId),
+			IsBoss:    monster.IsBoss,
+			Drops:     make([]Drop, 0, len(monster.Drops)),
+		}
+		for i, grade := range monster.Grades {
+			if i == 0 || grade.Level < table.MinLevel {
+				table.MinLevel = grade.Level
+			}
+			table.MaxLevel = max(table.MaxLevel, grade.Level)
+		}
+
+		for _, drop := range monster.Drops {
+			table.Drops = append(table.Drops, Drop{
+				Item:           &NamedRef{Id: drop.ObjectId, Name: d.Text(items[drop.ObjectId].NameId)},
+				PercentByGrade: drop.percentByGrade(),
+				Count:          drop.Count,
+				Criteria:       drop.Criteria,
+			})
+		}
+
+		tables = append(tables, table)
+	}
+
+	sort.Slice(tables, func(i, j int) bool {
+		return tables[i].MonsterId < tables[j].MonsterId
+	})
+	return tables, nil
+}
+
+// BuildItemDrops inverts the monster drop tables, returning the monsters
+// dropping each item, sorted by item id then monster id.
+func BuildItemDrops(d *Dataset) ([]ItemDrops, error) {
+	monsterTables, err := BuildMonsterDrops(d)
+	if err != nil {
+		return nil, err
+	}
+
+	tablesByItem := map[int]*ItemDrops{}
+	for _, monsterTable := range monsterTables {
+		for _, drop := range monsterTable.Drops {
+			table, ok := tablesByItem[drop.Item.Id]
+			if !ok {
+				table = &ItemDrops{ItemId: drop.Item.Id, Name: drop.Item.Name}
+				tablesByItem[drop.Item.Id] = table
+			}
+
+			drop.Item = nil
+			drop.Monster = &NamedRef{Id: monsterTable.MonsterId, Name: monsterTable.Name}
+			table.DroppedBy = append(table.DroppedBy, drop)
+		}
+	}
+
+	tables := make([]ItemDrops, 0, len(tablesByItem))
+	for _, table := range tablesByItem {
+		tables = append(tables, *table)
+	}
+	sort.Slice(tables, func(i, j int) bool {
+		return tables[i].ItemId < tables[j].ItemId
+	})
+	return tables, nil
+}
@@ -0,0 +1,123 @@
+package dataset
+
+import (
+	"sort"
+
+	"github.com/brequet/dofus-data-file-parser/pkg/effect"
+)
+
+// EncyclopediaItem is an item joined with its type, set, effects and recipe,
+// texts being those of the dataset locale.
+type EncyclopediaItem struct {
+	Id          int          `json:"id"`
+	Name        string       `json:"name"`
+	Description string       `json:"description,omitempty"`
+	Level       int          `json:"level"`
+	IconId      int          `json:"iconId"`
+	Type        *NamedRef    `json:"type,omitempty"`
+	Set         *NamedRef    `json:"set,omitempty"`
+	Criteria    string       `json:"criteria,omitempty"`
+	Effects     []EffectText `json:"effects"`
+	Recipe      *Recipe      `json:"recipe,omitempty"`
+}
+
+// EffectText is a decoded effect along with its description.
+type EffectText struct {
+	effect.Effect
+	Text string `json:"text"`
+}
+
+type item struct {
+	Id              int    `json:"id"`
+	NameId          int    `json:"nameId"`
+	TypeId          int    `json:"typeId"`
+	DescriptionId   int    `json:"descriptionId"`
+	IconId          int    `json:"iconId"`
+	Level           int    `json:"level"`
+	ItemSetId       int    `json:"itemSetId"`
+	Criteria        string `json:"criteria"`
+	PossibleEffects []any  `json:"possibleEffects"`
+}
+
+type itemType struct {
+	Id     int `json:"id"`
+	NameId int `json:"nameId"`
+}
+
+type itemSet struct {
+	Id     int `json:"id"`
+	NameId int `json:"nameId"`
+}
+
+type effectDefinition struct {
+	Id            int `json:"id"`
+	DescriptionId int `json:"descriptionId"`
+}
+
+// BuildEncyclopedia joins the Items, ItemTypes, ItemSets, Effects and Recipes
+// modules, along with Jobs for the recipes, into one document per item,
+// sorted by id.
+func BuildEncyclopedia(d *Dataset) ([]EncyclopediaItem, error) {
+	items, err := decodeModule(d, "Items", func(i item) int { return i.Id })
+	if err != nil {
+		return nil, err
+	}
+	itemTypes, err := decodeModule(d, "ItemTypes", func(t itemType) int { return t.Id })
+	if err != nil {
+		return nil, err
+	}
+	itemSets, err := decodeModule(d, "ItemSets", func(s itemSet) int { return s.Id })
+	if err != nil {
+		return nil, err
+	}
+	effects, err := decodeModule(d, "Effects", func(e effectDefinition) int { return e.Id })
+	if err != nil {
+		return nil, err
+	}
+	recipes, err := BuildRecipes(d)
+	if err != nil {
+		return nil, err
+	}
+	recipesByResult := make(map[int]*Recipe, len(recipes))
+	for i := range recipes {
+		recipesByResult[recipes[i].ResultId] = &recipes[i]
+	}
+
+	encyclopedia := make([]EncyclopediaItem, 0, len(items))
+	for _, item := range items {
+		entry := EncyclopediaItem{
+			Id:          item.Id,
+			Name:        d.Text(item.NameId),
+			Description: d.Text(item.DescriptionId),
+			Level:       item.Level,
+			IconId:      item.IconId,
+			Criteria:    item.Criteria,
+			Effects:     make([]EffectText, 0, len(item.PossibleEffects)),
+		}
+		if itemType, ok := itemTypes[item.TypeId]; ok {
+			entry.Type = &NamedRef{Id: itemType.Id, Name: d.Text(itemType.NameId)}
+		}
+		if itemSet, ok := itemSets[item.ItemSetId]; ok {
+			entry.Set = &NamedRef{Id: itemSet.Id, Name: d.Text(itemSet.NameId)}
+		}
+
+		for _, object := range item.PossibleEffects {
+			decoded, err := effect.Decode(object)
+			if err != nil {
+				continue
+			}
+			entry.Effects = append(entry.Effects, EffectText{
+				Effect: decoded,
+				Text:   decoded.Describe(d.Text(effects[decoded.EffectId].DescriptionId)),
+			})
+		}
+
+		entry.Recipe = recipesByResult[item.Id]
+		encyclopedia = append(encyclopedia, entry)
+	}
+
+	sort.Slice(encyclopedia, func(i, j int) bool {
+		return encyclopedia[i].Id < encyclopedia[j].Id
+	})
+	return encyclopedia, nil
+}
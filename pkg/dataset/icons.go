@@ -0,0 +1,177 @@
+package dataset
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/brequet/dofus-data-file-parser/pkg/parser"
+)
+
+// Icon is the image of an object of a module, e.g. of an item, found in the
+// archives of the gfx folder.
+type Icon struct {
+	Module string `json:"module"`
+	Id     int    `json:"id"`
+	Name   string `json:"name"`
+	IconId int    `json:"iconId"`
+	// Archive is the path of the archive holding the image, relative to the
+	// gfx folder, and Entry the name of the image in the archive.
+	Archive string `json:"archive"`
+	Entry   string `json:"entry"`
+	// Path is the normalized path of the image, <gfx folder>/<icon id>.<ext>,
+	// e.g. items/16001.png, under which ExtractIcons writes it.
+	Path string `json:"path"`
+}
+
+type iconObject struct {
+	Id     int `json:"id"`
+	NameId int `json:"nameId"`
+	IconId int `json:"iconId"`
+	GfxId  int `json:"gfxId"`
+}
+
+// iconSources are the modules whose objects have an icon, with the folder of
+// content/gfx holding their images.
+var iconSources = []struct {
+	module    string
+	gfxFolder string
+	iconId    func(iconObject) int
+}{
+	{module: "Items", gfxFolder: "items", iconId: func(o iconObject) int { return o.IconId }},
+	{module: "Spells", gfxFolder: "spells", iconId: func(o iconObject) int { return o.IconId }},
+	{module: "Monsters", gfxFolder: "monsters", iconId: func(o iconObject) int { return o.GfxId }},
+}
+
+// GfxIndex indexes the images of the .d2p archives of the gfx folder
+// (content/gfx) by folder and id, the id being the number ending the name of
+// the entry, e.g. 16001 for 16001.png or sort_16001.png.
+type GfxIndex struct {
+	gfxFolderPath string
+	images        map[string]map[int]gfxImage // gfx folder -> id -> image
+}
+
+type gfxImage struct {
+	archivePath string // relative to the gfx folder
+	archive     parser.D2pArchive
+	entry       parser.D2pEntry
+}
+
+// IndexGfx reads the index of the archives of a gfx folder.
+func IndexGfx(gfxFolderPath string, opts ...parser.ParseOptions) (GfxIndex, error) {
+	index := GfxIndex{gfxFolderPath: gfxFolderPath, images: map[string]map[int]gfxImage{}}
+	err := filepath.WalkDir(gfxFolderPath, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || filepath.Ext(filePath) != ".d2p" {
+			return nil
+		}
+
+		archivePath, err := filepath.Rel(gfxFolderPath, filePath)
+		if err != nil {
+			return err
+		}
+		archivePath = filepath.ToSlash(archivePath)
+		folder, _, _ := strings.Cut(archivePath, "/")
+
+		archive, err := parser.ProcessD2pFile(filePath, opts...)
+		if err != nil {
+			return fmt.Errorf("error reading %s: %w", archivePath, err)
+		}
+		if index.images[folder] == nil {
+			index.images[folder] = map[int]gfxImage{}
+		}
+		for _, d2pEntry := range archive.Entries {
+			id, ok := gfxEntryId(d2pEntry.Name)
+			if ok {
+				index.images[folder][id] = gfxImage{archivePath: archivePath, archive: archive, entry: d2pEntry}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return GfxIndex{}, err
+	}
+	return index, nil
+}
+
+func gfxEntryId(entryName string) (int, bool) {
+	name := path.Base(entryName)
+	name = strings.TrimSuffix(name, path.Ext(name))
+	name = strings.TrimLeftFunc(name, func(r rune) bool { return !unicode.IsDigit(r) })
+	id, err := strconv.Atoi(name)
+	return id, err == nil
+}
+
+// BuildIcons maps the objects of the Items, Spells and Monsters modules to
+// their image, sorted by module and id. The modules missing from the data
+// folder and the objects without image are left out.
+func BuildIcons(d *Dataset, gfx GfxIndex) ([]Icon, error) {
+	icons := make([]Icon, 0)
+	for _, source := range iconSources {
+		if _, err := os.Stat(filepath.Join(d.dataFolderPath, "common", source.module+".d2o")); err != nil {
+			continue
+		}
+		objects, err := decodeModule(d, source.module, func(o iconObject) int { return o.Id })
+		if err != nil {
+			return nil, err
+		}
+
+		for _, object := range objects {
+			iconId := source.iconId(object)
+			image, ok := gfx.images[source.gfxFolder][iconId]
+			if !ok {
+				continue
+			}
+			icons = append(icons, Icon{
+				Module:  source.module,
+				Id:      object.Id,
+				Name:    d.Text(object.NameId),
+				IconId:  iconId,
+				Archive: image.archivePath,
+				Entry:   image.entry.Name,
+				Path:    fmt.Sprintf("%s/%d%s", source.gfxFolder, iconId, path.Ext(image.entry.Name)),
+			})
+		}
+	}
+
+	sort.Slice(icons, func(i, j int) bool {
+		if icons[i].Module != icons[j].Module {
+			return icons[i].Module < icons[j].Module
+		}
+		return icons[i].Id < icons[j].Id
+	})
+	return icons, nil
+}
+
+// ExtractIcons writes the images of the icons under the output folder, at
+// their normalized path.
+func ExtractIcons(icons []Icon, gfx GfxIndex, outputFolderPath string) error {
+	written := map[string]bool{}
+	for _, icon := range icons {
+		if written[icon.Path] {
+			continue // shared by several objects
+		}
+		folder, _, _ := strings.Cut(icon.Path, "/")
+		image := gfx.images[folder][icon.IconId]
+
+		outputPath := filepath.Join(outputFolderPath, filepath.FromSlash(icon.Path))
+		err := os.MkdirAll(filepath.Dir(outputPath), 0755)
+		if err != nil {
+			return fmt.Errorf("error creating folder: %w", err)
+		}
+		err = os.WriteFile(outputPath, image.archive.Read(image.entry), 0644)
+		if err != nil {
+			return fmt.Errorf("error writing file: %w", err)
+		}
+		written[icon.Path] = true
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package dataset
+
+import "sort"
+
+// Recipe is a crafting recipe whose item and job references are resolved.
+type Recipe struct {
+	ResultId    int          `json:"resultId"`
+	ResultName  string       `json:"resultName"`
+	ResultLevel int          `json:"resultLevel"`
+	Job         *NamedRef    `json:"job,omitempty"`
+	Ingredients []Ingredient `json:"ingredients"`
+}
+
+type Ingredient struct {
+	ItemId   int    `json:"itemId"`
+	Name     string `json:"name"`
+	Level    int    `json:"level"`
+	Quantity int    `json:"quantity"`
+}
+
+type recipe struct {
+	ResultId      int   `json:"resultId"`
+	ResultLevel   int   `json:"resultLevel"`
+	IngredientIds []int `json:"ingredientIds"`
+	Quantities    []int `json:"quantities"`
+	JobId         int   `json:"jobId"`
+}
+
+type job struct {
+	Id     int `json:"id"`
+	NameId int `json:"nameId"`
+}
+
+// BuildRecipes resolves the item and job references of the Recipes module,
+// returning the recipes sorted by result item id.
+func BuildRecipes(d *Dataset) ([]Recipe, error) {
+	recipes, err := decodeModule(d, "Recipes", func(r recipe) int { return r.ResultId })
+	if err != nil {
+		return nil, err
+	}
+	items, err := decodeModule(d, "Items", func(i item) int { return i.Id })
+	if err != nil {
+		return nil, err
+	}
+	jobs, err := decodeModule(d, "Jobs", func(j job) int { return j.Id })
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := make([]Recipe, 0, len(recipes))
+	for _, recipe := range recipes {
+		entry := Recipe{
+			ResultId:    recipe.ResultId,
+			ResultName:  d.Text(items[recipe.ResultId].NameId),
+			ResultLevel: recipe.ResultLevel,
+			Ingredients: make([]Ingredient, 0, len(recipe.IngredientIds)),
+		}
+		if job, ok := jobs[recipe.JobId]; ok {
+			entry.Job = &NamedRef{Id: job.Id, Name: d.Text(job.NameId)}
+		}
+
+		for i, ingredientId := range recipe.IngredientIds {
+			ingredient := Ingredient{
+				ItemId: ingredientId,
+				Name:   d.Text(items[ingredientId].NameId),
+				Level:  items[ingredientId].Level,
+			}
+			if i < len(recipe.Quantities) {
+				ingredient.Quantity = recipe.Quantities[i]
+			}
+			entry.Ingredients = append(entry.Ingredients, ingredient)
+		}
+
+		resolved = append(resolved, entry)
+	}
+
+	sort.Slice(resolved, func(i, j int) bool {
+		return resolved[i].ResultId < resolved[j].ResultId
+	})
+	return resolved, nil
+}
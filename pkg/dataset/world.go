@@ -0,0 +1,146 @@
+package dataset
+
+import "sort"
+
+// SuperArea is a continent of the world, with its areas.
+type SuperArea struct {
+	Id         int    `json:"id"`
+	Name       string `json:"name"`
+	WorldMapId int    `json:"worldMapId"`
+	Areas      []Area `json:"areas"`
+}
+
+// Area is a region of a super area, with its sub areas.
+type Area struct {
+	Id       int       `json:"id"`
+	Name     string    `json:"name"`
+	SubAreas []SubArea `json:"subAreas"`
+}
+
+// SubArea is a zone of an area, with its maps.
+type SubArea struct {
+	Id    int    `json:"id"`
+	Name  string `json:"name"`
+	Level int    `json:"level"`
+	Maps  []Map  `json:"maps"`
+}
+
+// Map is a map of a sub area along with its position, Name being empty for
+// most maps.
+type Map struct {
+	Id         int    `json:"id"`
+	Name       string `json:"name,omitempty"`
+	PosX       int    `json:"posX"`
+	PosY       int    `json:"posY"`
+	Outdoor    bool   `json:"outdoor"`
+	WorldMapId int    `json:"worldMapId"`
+}
+
+type superArea struct {
+	Id         int `json:"id"`
+	NameId     int `json:"nameId"`
+	WorldmapId int `json:"worldmapId"`
+}
+
+type area struct {
+	Id          int `json:"id"`
+	NameId      int `json:"nameId"`
+	SuperAreaId int `json:"superAreaId"`
+}
+
+type subArea struct {
+	Id     int   `json:"id"`
+	NameId int   `json:"nameId"`
+	AreaId int   `json:"areaId"`
+	MapIds []int `json:"mapIds"`
+	Level  int   `json:"level"`
+}
+
+type mapPosition struct {
+	Id       int  `json:"id"`
+	PosX     int  `json:"posX"`
+	PosY     int  `json:"posY"`
+	Outdoor  bool `json:"outdoor"`
+	NameId   int  `json:"nameId"`
+	WorldMap int  `json:"worldMap"`
+}
+
+// BuildWorld joins the SuperAreas, Areas, SubAreas and MapPositions modules
+// into the tree of the world, one document per super area. Every level is
+// sorted by id, and maps without a position are left out.
+func BuildWorld(d *Dataset) ([]SuperArea, error) {
+	superAreas, err := decodeModule(d, "SuperAreas", func(s superArea) int { return s.Id })
+	if err != nil {
+		return nil, err
+	}
+	areas, err := decodeModule(d, "Areas", func(a area) int { return a.Id })
+	if err != nil {
+		return nil, err
+	}
+	subAreas, err := decodeModule(d, "SubAreas", func(s subArea) int { return s.Id })
+	if err != nil {
+		return nil, err
+	}
+	positions, err := decodeModule(d, "MapPositions", func(p mapPosition) int { return p.Id })
+	if err != nil {
+		return nil, err
+	}
+
+	subAreasByArea := map[int][]SubArea{}
+	for _, subArea := range subAreas {
+		entry := SubArea{
+			Id:    subArea.Id,
+			Name:  d.Text(subArea.NameId),
+			Level: subArea.Level,
+			Maps:  make([]Map, 0, len(subArea.MapIds)),
+		}
+		for _, mapId := range subArea.MapIds {
+			position, ok := positions[mapId]
+			if !ok {
+				continue
+			}
+			entry.Maps = append(entry.Maps, Map{
+				Id:         mapId,
+				Name:       d.Text(position.NameId),
+				PosX:       position.PosX,
+				PosY:       position.PosY,
+				Outdoor:    position.Outdoor,
+				WorldMapId: position.WorldMap,
+			})
+		}
+		sort.Slice(entry.Maps, func(i, j int) bool { return entry.Maps[i].Id < entry.Maps[j].Id })
+		subAreasByArea[subArea.AreaId] = append(subAreasByArea[subArea.AreaId], entry)
+	}
+
+	areasBySuperArea := map[int][]Area{}
+	for _, area := range areas {
+		entry := Area{
+			Id:       area.Id,
+			Name:     d.Text(area.NameId),
+			SubAreas: subAreasByArea[area.Id],
+		}
+		if entry.SubAreas == nil {
+			entry.SubAreas = []SubArea{}
+		}
+		sort.Slice(entry.SubAreas, func(i, j int) bool { return entry.SubAreas[i].Id < entry.SubAreas[j].Id })
+		areasBySuperArea[area.SuperAreaId] = append(areasBySuperArea[area.SuperAreaId], entry)
+	}
+
+	world := make([]SuperArea, 0, len(superAreas))
+	for _, superArea := range superAreas {
+		entry := SuperArea{
+			Id:         superArea.Id,
+			Name:       d.Text(superArea.NameId),
+			WorldMapId: superArea.WorldmapId,
+			Areas:      areasBySuperArea[superArea.Id],
+		}
+		if entry.Areas == nil {
+			entry.Areas = []Area{}
+		}
+		sort.Slice(entry.Areas, func(i, j int) bool { return entry.Areas[i].Id < entry.Areas[j].Id })
+		world = append(world, entry)
+	}
+
+	sort.Slice(world, func(i, j int) bool { return world[i].Id < world[j].Id })
+	return world, nil
+}
@@ -0,0 +1,255 @@
+// Package diff compares the game data of two Dofus versions.
+package diff
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/brequet/dofus-data-file-parser/pkg/parser"
+)
+
+// Changelog lists the changes between two Dofus data folders. Modules and
+// locales without changes are omitted.
+type Changelog struct {
+	AddedModules   []string                      `json:"addedModules"`
+	RemovedModules []string                      `json:"removedModules"`
+	Modules        map[string]ModuleChanges      `json:"modules"`
+	Translations   map[string]TranslationChanges `json:"translations"`
+}
+
+type ModuleChanges struct {
+	Added   []int          `json:"added"`
+	Removed []int          `json:"removed"`
+	Changed []ObjectChange `json:"changed"`
+}
+
+type ObjectChange struct {
+	Id     int      `json:"id"`
+	Fields []string `json:"fields"`
+}
+
+type TranslationChanges struct {
+	Added   []int `json:"added"`
+	Removed []int `json:"removed"`
+	Changed []int `json:"changed"`
+}
+
+func (c ModuleChanges) IsEmpty() bool {
+	return len(c.Added) == 0 && len(c.Removed) == 0 && len(c.Changed) == 0
+}
+
+func (c TranslationChanges) IsEmpty() bool {
+	return len(c.Added) == 0 && len(c.Removed) == 0 && len(c.Changed) == 0
+}
+
+// CompareDataFolders compares the modules (common folder) and translations
+// (i18n folder) of two Dofus data folders.
+func CompareDataFolders(oldDataFolderPath, newDataFolderPath string) (Changelog, error) {
+	changelog := Changelog{
+		AddedModules:   []string{},
+		RemovedModules: []string{},
+		Modules:        map[string]ModuleChanges{},
+		Translations:   map[string]TranslationChanges{},
+	}
+
+	oldModules, newModules, err := listFilePairs(oldDataFolderPath, newDataFolderPath, "common", "*.d2o")
+	if err != nil {
+		return Changelog{}, err
+	}
+	for name := range newModules {
+		if _, ok := oldModules[name]; !ok {
+			changelog.AddedModules = append(changelog.AddedModules, name)
+		}
+	}
+	for name, oldPath := range oldModules {
+		newPath, ok := newModules[name]
+		if !ok {
+			changelog.RemovedModules = append(changelog.RemovedModules, name)
+			continue
+		}
+
+		changes, err := CompareD2oFiles(oldPath, newPath)
+		if err != nil {
+			return Changelog{}, fmt.Errorf("error comparing module %s: %w", name, err)
+		}
+		if !changes.IsEmpty() {
+			changelog.Modules[name] = changes
+		}
+	}
+	sort.Strings(changelog.AddedModules)
+	sort.Strings(changelog.RemovedModules)
+
+	oldLocales, newLocales, err := listFilePairs(oldDataFolderPath, newDataFolderPath, "i18n", "*.d2i")
+	if err != nil {
+		return Changelog{}, err
+	}
+	locales := map[string]bool{}
+	for name := range oldLocales {
+		locales[name] = true
+	}
+	for name := range newLocales {
+		locales[name] = true
+	}
+	for name := range locales {
+		// a locale missing from one of the folders compares with no texts
+		oldTranslations, err := readTranslations(oldLocales[name])
+		if err != nil {
+			return Changelog{}, err
+		}
+
+		newTranslations, err := readTranslations(newLocales[name])
+		if err != nil {
+			return Changelog{}, err
+		}
+
+		changes := CompareTranslations(oldTranslations, newTranslations)
+		if !changes.IsEmpty() {
+			changelog.Translations[strings.TrimPrefix(name, "i18n_")] = changes
+		}
+	}
+
+	return changelog, nil
+}
+
+// CompareD2oFiles compares the objects of two versions of a module, matching
+// them by id.
+func CompareD2oFiles(oldD2oFilePath, newD2oFilePath string) (ModuleChanges, error) {
+	oldData, err := parser.ProcessD2oFile(oldD2oFilePath)
+	if err != nil {
+		return ModuleChanges{}, fmt.Errorf("error parsing %s: %w", oldD2oFilePath, err)
+	}
+
+	newData, err := parser.ProcessD2oFile(newD2oFilePath)
+	if err != nil {
+		return ModuleChanges{}, fmt.Errorf("error parsing %s: %w", newD2oFilePath, err)
+	}
+
+	return CompareD2oData(oldData, newData), nil
+}
+
+func CompareD2oData(oldData, newData parser.D2oData) ModuleChanges {
+	changes := ModuleChanges{
+		Added:   []int{},
+		Removed: []int{},
+		Changed: []ObjectChange{},
+	}
+
+	for _, id := range newData.ObjectIDs() {
+		if _, ok := oldData.ObjectByID(id); !ok {
+			changes.Added = append(changes.Added, id)
+		}
+	}
+
+	for _, id := range oldData.ObjectIDs() {
+		oldObject, _ := oldData.ObjectByID(id)
+		newObject, ok := newData.ObjectByID(id)
+		if !ok {
+			changes.Removed = append(changes.Removed, id)
+			continue
+		}
+
+		fields := changedFields(oldObject, newObject)
+		if len(fields) > 0 {
+			changes.Changed = append(changes.Changed, ObjectChange{Id: id, Fields: fields})
+		}
+	}
+
+	sort.Ints(changes.Added)
+	sort.Ints(changes.Removed)
+	sort.Slice(changes.Changed, func(i, j int) bool { return changes.Changed[i].Id < changes.Changed[j].Id })
+	return changes
+}
+
+func CompareTranslations(oldTranslations, newTranslations parser.Translations) TranslationChanges {
+	changes := TranslationChanges{
+		Added:   []int{},
+		Removed: []int{},
+		Changed: []int{},
+	}
+
+	for id, newText := range newTranslations {
+		oldText, ok := oldTranslations[id]
+		if !ok {
+			changes.Added = append(changes.Added, id)
+		} else if oldText != newText {
+			changes.Changed = append(changes.Changed, id)
+		}
+	}
+	for id := range oldTranslations {
+		if _, ok := newTranslations[id]; !ok {
+			changes.Removed = append(changes.Removed, id)
+		}
+	}
+
+	sort.Ints(changes.Added)
+	sort.Ints(changes.Removed)
+	sort.Ints(changes.Changed)
+	return changes
+}
+
+func readTranslations(d2iFilePath string) (parser.Translations, error) {
+	if d2iFilePath == "" {
+		return parser.Translations{}, nil
+	}
+
+	translations, err := parser.ProcessD2iFile(d2iFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %s: %w", d2iFilePath, err)
+	}
+	return translations, nil
+}
+
+// changedFields returns the sorted names of the top level fields differing
+// between two objects.
+func changedFields(oldObject, newObject parser.Object) []string {
+	oldMap, oldOk := oldObject.(map[string]any)
+	newMap, newOk := newObject.(map[string]any)
+	if !oldOk || !newOk {
+		if reflect.DeepEqual(oldObject, newObject) {
+			return nil
+		}
+		return []string{"ClassType_"}
+	}
+
+	fields := []string{}
+	for name, newValue := range newMap {
+		if oldValue, ok := oldMap[name]; !ok || !reflect.DeepEqual(oldValue, newValue) {
+			fields = append(fields, name)
+		}
+	}
+	for name := range oldMap {
+		if _, ok := newMap[name]; !ok {
+			fields = append(fields, name)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// listFilePairs returns, by file name without extension, the paths of the files of a subfolder
+// of the old and new data folders matching the pattern.
+func listFilePairs(oldDataFolderPath, newDataFolderPath, subfolder, pattern string) (map[string]string, map[string]string, error) {
+	oldFiles, err := filepath.Glob(filepath.Join(oldDataFolderPath, subfolder, pattern))
+	if err != nil {
+		return nil, nil, fmt.Errorf("error listing files: %w", err)
+	}
+
+	newFiles, err := filepath.Glob(filepath.Join(newDataFolderPath, subfolder, pattern))
+	if err != nil {
+		return nil, nil, fmt.Errorf("error listing files: %w", err)
+	}
+
+	return filesByName(oldFiles), filesByName(newFiles), nil
+}
+
+func filesByName(filePaths []string) map[string]string {
+	files := make(map[string]string, len(filePaths))
+	for _, filePath := range filePaths {
+		name := filepath.Base(filePath)
+		files[strings.TrimSuffix(name, filepath.Ext(name))] = filePath
+	}
+	return files
+}
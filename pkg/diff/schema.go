@@ -0,0 +1,209 @@
+package diff
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/brequet/dofus-data-file-parser/pkg/parser"
+)
+
+// SchemaChanges lists the changes of the class definitions between two Dofus
+// data folders, classes being named after their package, e.g.
+// "com.ankamagames.dofus.datacenter.items.Item". Classes without changes are
+// omitted.
+type SchemaChanges struct {
+	AddedClasses   []string                `json:"addedClasses"`
+	RemovedClasses []string                `json:"removedClasses"`
+	Classes        map[string]ClassChanges `json:"classes"`
+}
+
+type ClassChanges struct {
+	AddedFields   []FieldDefinition `json:"addedFields"`
+	RemovedFields []FieldDefinition `json:"removedFields"`
+	RetypedFields []FieldTypeChange `json:"retypedFields"`
+}
+
+type FieldDefinition struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+type FieldTypeChange struct {
+	Name    string `json:"name"`
+	OldType string `json:"oldType"`
+	NewType string `json:"newType"`
+}
+
+func (c SchemaChanges) IsEmpty() bool {
+	return len(c.AddedClasses) == 0 && len(c.RemovedClasses) == 0 && len(c.Classes) == 0
+}
+
+func (c ClassChanges) IsEmpty() bool {
+	return len(c.AddedFields) == 0 && len(c.RemovedFields) == 0 && len(c.RetypedFields) == 0
+}
+
+// CompareSchemas compares the class definitions of the modules of two Dofus
+// data folders, without decoding their objects.
+func CompareSchemas(oldDataFolderPath, newDataFolderPath string) (SchemaChanges, error) {
+	oldModules, newModules, err := listFilePairs(oldDataFolderPath, newDataFolderPath, "common", "*.d2o")
+	if err != nil {
+		return SchemaChanges{}, err
+	}
+
+	oldClasses, err := readClasses(oldModules)
+	if err != nil {
+		return SchemaChanges{}, err
+	}
+
+	newClasses, err := readClasses(newModules)
+	if err != nil {
+		return SchemaChanges{}, err
+	}
+
+	return CompareClasses(oldClasses, newClasses), nil
+}
+
+// CompareClasses compares two sets of classes, by qualified name, matching
+// the fields of a class by name.
+func CompareClasses(oldClasses, newClasses map[string]parser.Class) SchemaChanges {
+	changes := SchemaChanges{
+		AddedClasses:   []string{},
+		RemovedClasses: []string{},
+		Classes:        map[string]ClassChanges{},
+	}
+
+	for name := range newClasses {
+		if _, ok := oldClasses[name]; !ok {
+			changes.AddedClasses = append(changes.AddedClasses, name)
+		}
+	}
+	for name, oldClass := range oldClasses {
+		newClass, ok := newClasses[name]
+		if !ok {
+			changes.RemovedClasses = append(changes.RemovedClasses, name)
+			continue
+		}
+
+		classChanges := compareClassFields(oldClass.Fields, newClass.Fields)
+		if !classChanges.IsEmpty() {
+			changes.Classes[name] = classChanges
+		}
+	}
+
+	sort.Strings(changes.AddedClasses)
+	sort.Strings(changes.RemovedClasses)
+	return changes
+}
+
+// compareClassFields compares the fields of two versions of a class, listing
+// them in the order of the class definition.
+func compareClassFields(oldFields, newFields []parser.GameDataField) ClassChanges {
+	changes := ClassChanges{
+		AddedFields:   []FieldDefinition{},
+		RemovedFields: []FieldDefinition{},
+		RetypedFields: []FieldTypeChange{},
+	}
+
+	oldTypes := fieldTypes(oldFields)
+	newTypes := fieldTypes(newFields)
+	for _, field := range newFields {
+		newType := fieldTypeName(field)
+		oldType, ok := oldTypes[field.Name]
+		if !ok {
+			changes.AddedFields = append(changes.AddedFields, FieldDefinition{Name: field.Name, Type: newType})
+		} else if oldType != newType {
+			changes.RetypedFields = append(changes.RetypedFields, FieldTypeChange{Name: field.Name, OldType: oldType, NewType: newType})
+		}
+	}
+	for _, field := range oldFields {
+		if _, ok := newTypes[field.Name]; !ok {
+			changes.RemovedFields = append(changes.RemovedFields, FieldDefinition{Name: field.Name, Type: fieldTypeName(field)})
+		}
+	}
+
+	return changes
+}
+
+func fieldTypes(fields []parser.GameDataField) map[string]string {
+	types := make(map[string]string, len(fields))
+	for _, field := range fields {
+		types[field.Name] = fieldTypeName(field)
+	}
+	return types
+}
+
+// fieldTypeName describes the type of a field, e.g. "Vector<EffectInstance>".
+func fieldTypeName(field parser.GameDataField) string {
+	switch {
+	case field.Type == parser.Vector:
+		return fmt.Sprintf("Vector<%s>", fieldTypeName(*field.SubType))
+	case field.Type > 0 && field.TypeName != "":
+		return field.TypeName
+	default:
+		return field.Type.String()
+	}
+}
+
+// readClasses reads the class tables of modules, by qualified class name.
+func readClasses(modules map[string]string) (map[string]parser.Class, error) {
+	classes := map[string]parser.Class{}
+	for name, d2oFilePath := range modules {
+		reader, err := parser.NewD2oReader(d2oFilePath)
+		if err != nil {
+			return nil, fmt.Errorf("error reading classes of module %s: %w", name, err)
+		}
+
+		for _, class := range reader.Classes() {
+			classes[class.PackageName+"."+class.PackageClass] = class
+		}
+		reader.Close()
+	}
+	return classes, nil
+}
+
+// Text renders the changes as a human-readable report.
+func (c SchemaChanges) Text() string {
+	if c.IsEmpty() {
+		return "No schema changes.\n"
+	}
+
+	var text bytes.Buffer
+	if len(c.AddedClasses) > 0 {
+		text.WriteString("Added classes:\n")
+		for _, name := range c.AddedClasses {
+			text.WriteString(fmt.Sprintf("  + %s\n", name))
+		}
+	}
+	if len(c.RemovedClasses) > 0 {
+		text.WriteString("Removed classes:\n")
+		for _, name := range c.RemovedClasses {
+			text.WriteString(fmt.Sprintf("  - %s\n", name))
+		}
+	}
+
+	if len(c.Classes) > 0 {
+		names := make([]string, 0, len(c.Classes))
+		for name := range c.Classes {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		text.WriteString("Changed classes:\n")
+		for _, name := range names {
+			classChanges := c.Classes[name]
+			text.WriteString(fmt.Sprintf("  %s\n", name))
+			for _, field := range classChanges.AddedFields {
+				text.WriteString(fmt.Sprintf("    + %s: %s\n", field.Name, field.Type))
+			}
+			for _, field := range classChanges.RemovedFields {
+				text.WriteString(fmt.Sprintf("    - %s: %s\n", field.Name, field.Type))
+			}
+			for _, field := range classChanges.RetypedFields {
+				text.WriteString(fmt.Sprintf("    ~ %s: %s -> %s\n", field.Name, field.OldType, field.NewType))
+			}
+		}
+	}
+
+	return text.String()
+}
@@ -0,0 +1,68 @@
+package effect
+
+import (
+	"strconv"
+	"strings"
+)
+
+// FormatDescription fills a description pattern of the Effects module with
+// params, "#1" being replaced by the first one. Blocks like "{~1~2 to }" are
+// kept only when the params they refer to are not empty, blocks with other
+// conditions (plurals, genders...) being dropped.
+func FormatDescription(pattern string, params ...string) string {
+	// See PatternDecoder.as
+	var description strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; {
+		case c == '{':
+			end := strings.IndexByte(pattern[i:], '}')
+			if end < 0 {
+				description.WriteString(pattern[i:])
+				return description.String()
+			}
+			description.WriteString(formatBlock(pattern[i+1:i+end], params))
+			i += end
+		case c == '#' && i+1 < len(pattern) && pattern[i+1] >= '1' && pattern[i+1] <= '9':
+			description.WriteString(param(params, int(pattern[i+1]-'0')))
+			i++
+		default:
+			description.WriteByte(c)
+		}
+	}
+	return description.String()
+}
+
+// formatBlock formats the content of a conditional block.
+func formatBlock(block string, params []string) string {
+	for strings.HasPrefix(block, "~") && len(block) > 1 {
+		condition := block[1]
+		if condition < '1' || condition > '9' || param(params, int(condition-'0')) == "" {
+			return ""
+		}
+		block = block[2:]
+	}
+	return FormatDescription(block, params...)
+}
+
+func param(params []string, n int) string {
+	if n > len(params) {
+		return ""
+	}
+	return params[n-1]
+}
+
+// Describe formats the description pattern of the effect with its values: its
+// minimum, its maximum when it differs, then its dice value.
+func (e Effect) Describe(pattern string) string {
+	params := make([]string, 3)
+	if e.Min != 0 || e.Max != 0 {
+		params[0] = strconv.Itoa(e.Min)
+	}
+	if e.Max != e.Min {
+		params[1] = strconv.Itoa(e.Max)
+	}
+	if e.Dice != nil && e.Dice.Value != 0 {
+		params[2] = strconv.Itoa(e.Dice.Value)
+	}
+	return strings.TrimSpace(FormatDescription(pattern, params...))
+}
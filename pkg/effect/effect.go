@@ -0,0 +1,129 @@
+// Package effect decodes the effect instances of spells and items into a
+// structured form, with their zone and value range.
+package effect
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/brequet/dofus-data-file-parser/pkg/parser"
+)
+
+// Effect is a decoded effect instance.
+type Effect struct {
+	EffectId   int    `json:"effectId"`
+	TargetId   int    `json:"targetId,omitempty"`
+	TargetMask string `json:"targetMask,omitempty"`
+	Duration   int    `json:"duration,omitempty"`
+	Delay      int    `json:"delay,omitempty"`
+	Random     int    `json:"random,omitempty"`
+	Group      int    `json:"group,omitempty"`
+	Triggers   string `json:"triggers,omitempty"`
+	Zone       *Zone  `json:"zone,omitempty"` // nil when the effect has no rawZone
+	Dice       *Dice  `json:"dice,omitempty"` // only set for EffectInstanceDice
+	// Min and Max are the range of the value of the effect, equal for fixed
+	// values. Both are 0 for effects without a value.
+	Min int `json:"min"`
+	Max int `json:"max"`
+}
+
+// Dice are the values of an EffectInstanceDice. DiceNum is the minimum value
+// and DiceSide the maximum one, 0 when the value is fixed.
+type Dice struct {
+	DiceNum  int `json:"diceNum"`
+	DiceSide int `json:"diceSide"`
+	Value    int `json:"value"`
+}
+
+// effectInstance holds the fields of the EffectInstance classes, those absent
+// from the class of an object staying nil.
+type effectInstance struct {
+	EffectId   int    `json:"effectId"`
+	TargetId   int    `json:"targetId"`
+	TargetMask string `json:"targetMask"`
+	Duration   int    `json:"duration"`
+	Delay      int    `json:"delay"`
+	Random     int    `json:"random"`
+	Group      int    `json:"group"`
+	Triggers   string `json:"triggers"`
+	RawZone    string `json:"rawZone"`
+	DiceNum    *int   `json:"diceNum"`
+	DiceSide   *int   `json:"diceSide"`
+	Value      *int   `json:"value"`
+	Min        *int   `json:"min"`
+	Max        *int   `json:"max"`
+}
+
+// IsEffectInstance tells whether an object is an EffectInstance or an
+// instance of one of its subclasses.
+func IsEffectInstance(object parser.Object) bool {
+	objectMap, ok := object.(map[string]any)
+	if !ok {
+		return false
+	}
+	className, _ := objectMap["ClassType_"].(string)
+	return strings.HasPrefix(className, "EffectInstance")
+}
+
+// Decode decodes an effect instance object.
+func Decode(object parser.Object) (Effect, error) {
+	if !IsEffectInstance(object) {
+		return Effect{}, fmt.Errorf("not an effect instance: %v", object)
+	}
+
+	var instance effectInstance
+	err := parser.DecodeInto(object, &instance)
+	if err != nil {
+		return Effect{}, fmt.Errorf("error decoding effect instance: %w", err)
+	}
+
+	effect := Effect{
+		EffectId:   instance.EffectId,
+		TargetId:   instance.TargetId,
+		TargetMask: instance.TargetMask,
+		Duration:   instance.Duration,
+		Delay:      instance.Delay,
+		Random:     instance.Random,
+		Group:      instance.Group,
+		Triggers:   instance.Triggers,
+	}
+
+	if instance.RawZone != "" {
+		zone, err := ParseZone(instance.RawZone)
+		if err != nil {
+			return Effect{}, err
+		}
+		effect.Zone = &zone
+	}
+
+	switch {
+	case instance.DiceNum != nil && instance.DiceSide != nil:
+		effect.Dice = &Dice{DiceNum: *instance.DiceNum, DiceSide: *instance.DiceSide}
+		if instance.Value != nil {
+			effect.Dice.Value = *instance.Value
+		}
+		effect.Min = effect.Dice.DiceNum
+		effect.Max = max(effect.Dice.DiceSide, effect.Dice.DiceNum)
+	case instance.Min != nil && instance.Max != nil:
+		effect.Min, effect.Max = *instance.Min, *instance.Max
+	case instance.Value != nil:
+		effect.Min, effect.Max = *instance.Value, *instance.Value
+	}
+
+	return effect, nil
+}
+
+// Enrich adds the decoded effect to the JSON export of effect instances, as
+// an "Effect_" entry. Other objects, and effects which cannot be decoded, are
+// left untouched.
+func Enrich(object parser.Object) map[string]any {
+	if !IsEffectInstance(object) {
+		return nil
+	}
+
+	effect, err := Decode(object)
+	if err != nil {
+		return nil
+	}
+	return map[string]any{"Effect_": effect}
+}
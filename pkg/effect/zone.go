@@ -0,0 +1,101 @@
+package effect
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Zone is the area of effect described by the rawZone field of an effect
+// instance, e.g. "C2" for a circle of radius 2.
+type Zone struct {
+	Shape             string `json:"shape"`               // letter of the shape, e.g. "C"
+	ShapeName         string `json:"shapeName,omitempty"` // empty for shapes without a known name
+	Size              int    `json:"size"`
+	MinSize           int    `json:"minSize,omitempty"`
+	EfficiencyPercent int    `json:"efficiencyPercent,omitempty"` // damage lost per cell away from the center
+	MaxEfficiency     int    `json:"maxEfficiency,omitempty"`
+}
+
+var shapeNames = map[string]string{
+	"A": "all",
+	"C": "circle",
+	"D": "checkerboard",
+	"G": "square",
+	"L": "line",
+	"O": "ring",
+	"P": "point",
+	"Q": "crossWithoutCenter",
+	"T": "perpendicularLine",
+	"V": "cone",
+	"X": "cross",
+	"l": "lineFromCaster",
+}
+
+// shapesWithMinSize are the shapes whose second parameter is a minimum size
+// rather than an efficiency percent.
+const shapesWithMinSize = "CXQ+#"
+
+// hashChars encodes sizes on a single character, as in the zones of older
+// data files, e.g. "Cb" for a circle of size 1.
+const hashChars = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_"
+
+// ParseZone decodes a rawZone string. Parameters are either comma separated
+// numbers, or a single character encoding the size.
+func ParseZone(rawZone string) (Zone, error) {
+	// See EffectInstance.as
+	if rawZone == "" {
+		return Zone{}, fmt.Errorf("empty zone")
+	}
+
+	zone := Zone{
+		Shape:     rawZone[:1],
+		ShapeName: shapeNames[rawZone[:1]],
+	}
+	rawParams := rawZone[1:]
+	if rawParams == "" {
+		return zone, nil
+	}
+
+	params, err := parseZoneParams(rawParams)
+	if err != nil {
+		return Zone{}, fmt.Errorf("invalid zone %q: %w", rawZone, err)
+	}
+
+	// fields set from each parameter, in order
+	var targets []*int
+	switch {
+	case zone.Shape == "l":
+		targets = []*int{&zone.MinSize, &zone.Size, &zone.EfficiencyPercent, &zone.MaxEfficiency}
+	case strings.Contains(shapesWithMinSize, zone.Shape):
+		targets = []*int{&zone.Size, &zone.MinSize, &zone.EfficiencyPercent, &zone.MaxEfficiency}
+	default:
+		targets = []*int{&zone.Size, &zone.EfficiencyPercent, &zone.MaxEfficiency}
+	}
+	if len(params) > len(targets) {
+		return Zone{}, fmt.Errorf("invalid zone %q: too many parameters", rawZone)
+	}
+	for i, param := range params {
+		*targets[i] = param
+	}
+
+	return zone, nil
+}
+
+func parseZoneParams(rawParams string) ([]int, error) {
+	if len(rawParams) == 1 {
+		if index := strings.Index(hashChars, rawParams); index >= 0 && (rawParams[0] < '0' || rawParams[0] > '9') {
+			return []int{index}, nil
+		}
+	}
+
+	params := make([]int, 0)
+	for _, rawParam := range strings.Split(rawParams, ",") {
+		param, err := strconv.Atoi(rawParam)
+		if err != nil {
+			return nil, fmt.Errorf("invalid parameter %q", rawParam)
+		}
+		params = append(params, param)
+	}
+	return params, nil
+}
@@ -0,0 +1,99 @@
+package exporter
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Compression of the written files, empty for none.
+type Compression string
+
+const (
+	CompressionNone Compression = ""
+	CompressionGzip Compression = "gzip"
+	CompressionZstd Compression = "zstd"
+)
+
+func ParseCompression(name string) (Compression, error) {
+	switch c := Compression(name); c {
+	case CompressionNone, CompressionGzip, CompressionZstd:
+		return c, nil
+	}
+	return "", fmt.Errorf("unknown compression %q (gzip or zstd)", name)
+}
+
+// Extension is the extension appended to the names of the compressed files.
+func (c Compression) Extension() string {
+	switch c {
+	case CompressionGzip:
+		return ".gz"
+	case CompressionZstd:
+		return ".zst"
+	}
+	return ""
+}
+
+// Create creates the file outputPath, with the extension of the compression
+// appended, returning a writer compressing what is written to it. Closing
+// the writer flushes the compressed data and closes the file.
+func (c Compression) Create(outputPath string) (io.WriteCloser, error) {
+	file, err := os.Create(outputPath + c.Extension())
+	if err != nil {
+		return nil, fmt.Errorf("error creating file: %w", err)
+	}
+
+	var compressor io.WriteCloser
+	switch c {
+	case CompressionNone:
+		return file, nil
+	case CompressionGzip:
+		compressor = gzip.NewWriter(file)
+	case CompressionZstd:
+		compressor, err = zstd.NewWriter(file)
+		if err != nil {
+			file.Close()
+			return nil, fmt.Errorf("error creating zstd writer: %w", err)
+		}
+	default:
+		file.Close()
+		return nil, fmt.Errorf("unknown compression %q", c)
+	}
+	return &compressedFile{WriteCloser: compressor, file: file}, nil
+}
+
+// WriteFile is os.WriteFile compressing the data, see Create.
+func (c Compression) WriteFile(outputPath string, data []byte) error {
+	writer, err := c.Create(outputPath)
+	if err != nil {
+		return err
+	}
+	_, err = writer.Write(data)
+	if err != nil {
+		writer.Close()
+		return err
+	}
+	return writer.Close()
+}
+
+type compressedFile struct {
+	io.WriteCloser
+	file   *os.File
+	closed bool
+}
+
+// Close can be called again, e.g. deferred, doing nothing.
+func (f *compressedFile) Close() error {
+	if f.closed {
+		return nil
+	}
+	f.closed = true
+	err := f.WriteCloser.Close()
+	if closeErr := f.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
@@ -0,0 +1,104 @@
+package exporter
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/brequet/dofus-data-file-parser/pkg/parser"
+)
+
+// CSVExporter writes modules as CSV files, one per class of the module, under
+// <outputFolderPath>/<module>/<class>.csv. Vector and custom type values are
+// stored as JSON, like in the SQLite export.
+type CSVExporter struct {
+	outputFolderPath string
+}
+
+func NewCSVExporter(outputFolderPath string) *CSVExporter {
+	return &CSVExporter{
+		outputFolderPath: outputFolderPath,
+	}
+}
+
+func (e *CSVExporter) Name() string {
+	return "csv"
+}
+
+func (e *CSVExporter) Export(module *Module) error {
+	data, err := module.Data()
+	if err != nil {
+		return err
+	}
+
+	return e.ExportModule(module.Name, data)
+}
+
+// ExportModule writes a CSV file per class having objects in the module.
+func (e *CSVExporter) ExportModule(moduleName string, data parser.D2oData) error {
+	moduleFolderPath := filepath.Join(e.outputFolderPath, moduleName)
+	err := os.MkdirAll(moduleFolderPath, 0755)
+	if err != nil {
+		return fmt.Errorf("error creating module folder: %w", err)
+	}
+
+	for _, class := range data.Classes {
+		columns, rows, err := data.ToFlatTable(class)
+		if err != nil {
+			return fmt.Errorf("error flattening class %s: %w", class.PackageClass, err)
+		}
+		if len(rows) == 0 {
+			continue
+		}
+
+		err = writeCSVFile(filepath.Join(moduleFolderPath, class.PackageClass+".csv"), columns, rows)
+		if err != nil {
+			return fmt.Errorf("error writing class %s: %w", class.PackageClass, err)
+		}
+	}
+
+	return nil
+}
+
+func writeCSVFile(csvFilePath string, columns []string, rows [][]any) error {
+	csvFile, err := os.Create(csvFilePath)
+	if err != nil {
+		return fmt.Errorf("error creating file: %w", err)
+	}
+	defer csvFile.Close()
+
+	writer := csv.NewWriter(csvFile)
+	err = writer.Write(columns)
+	if err != nil {
+		return err
+	}
+
+	record := make([]string, len(columns))
+	for _, row := range rows {
+		for i, value := range row {
+			record[i] = formatCSVValue(value)
+		}
+		err = writer.Write(record)
+		if err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func formatCSVValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,106 @@
+package exporter
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/brequet/dofus-data-file-parser/pkg/generator"
+	"github.com/brequet/dofus-data-file-parser/pkg/parser"
+)
+
+// GoEmbedExporter writes each module as a Go package embedding its objects,
+// under <outputFolderPath>/<package>: the class types (types.go), the objects
+// as compact JSON Lines (<package>.jsonl) and their accessors (<package>.go),
+// e.g. items.ByID(id). See generator.GenerateGoEmbedAccessors.
+type GoEmbedExporter struct {
+	outputFolderPath string
+	goOptions        generator.GoOptions
+}
+
+func NewGoEmbedExporter(outputFolderPath string) *GoEmbedExporter {
+	return &GoEmbedExporter{
+		outputFolderPath: outputFolderPath,
+	}
+}
+
+// SetGoOptions sets the options of the generated Go files, whose package name
+// is the one of each module. TagCase is ignored, the embedded objects keeping
+// the names of the D2O fields.
+func (e *GoEmbedExporter) SetGoOptions(options generator.GoOptions) {
+	e.goOptions = options
+}
+
+func (e *GoEmbedExporter) Name() string {
+	return "go-embed"
+}
+
+func (e *GoEmbedExporter) Export(module *Module) error {
+	goOptions := e.goOptions
+	goOptions.PackageName = generator.GoEmbedPackageName(module.Name)
+	// the struct tags must match the field names of the embedded objects
+	goOptions.TagCase = ""
+	packageFolderPath := filepath.Join(e.outputFolderPath, goOptions.PackageName)
+	err := os.MkdirAll(packageFolderPath, 0755)
+	if err != nil {
+		return fmt.Errorf("error creating package folder: %w", err)
+	}
+
+	classTable := module.Classes()
+	classIds := make([]int, 0, len(classTable))
+	for classId := range classTable {
+		classIds = append(classIds, classId)
+	}
+	sort.Ints(classIds)
+	classes := make([]parser.Class, 0, len(classIds))
+	for _, classId := range classIds {
+		classes = append(classes, classTable[classId])
+	}
+
+	typesFileContent, err := generator.GenerateGoFromClasses(classes, goOptions)
+	if err != nil {
+		return fmt.Errorf("error generating golang from classes: %w", err)
+	}
+	err = os.WriteFile(filepath.Join(packageFolderPath, "types.go"), typesFileContent, 0644)
+	if err != nil {
+		return fmt.Errorf("error writing file: %w", err)
+	}
+
+	accessorsFileContent, err := generator.GenerateGoEmbedAccessors(module.Name, classTable, goOptions)
+	if err != nil {
+		return fmt.Errorf("error generating golang accessors: %w", err)
+	}
+	err = os.WriteFile(filepath.Join(packageFolderPath, goOptions.PackageName+".go"), accessorsFileContent, 0644)
+	if err != nil {
+		return fmt.Errorf("error writing file: %w", err)
+	}
+
+	return e.writeData(filepath.Join(packageFolderPath, generator.GoEmbedDataFileName(module.Name)), module)
+}
+
+func (e *GoEmbedExporter) writeData(outputPath string, module *Module) error {
+	outputFile, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("error creating file: %w", err)
+	}
+	defer outputFile.Close()
+
+	writer := bufio.NewWriter(outputFile)
+	writeOptions := parser.JSONWriteOptions{IncludeObjectIds: true}
+	if data := module.decodedData(); data != nil {
+		err = data.WriteJSONL(writer, writeOptions)
+	} else {
+		err = module.Reader.WriteJSONLContext(module.Context(), writer, writeOptions)
+	}
+	if err != nil {
+		return err
+	}
+
+	err = writer.Flush()
+	if err != nil {
+		return err
+	}
+	return outputFile.Close()
+}
@@ -0,0 +1,124 @@
+// Package exporter writes parsed game data to the various output formats.
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/brequet/dofus-data-file-parser/pkg/parser"
+)
+
+// Exporter writes modules to an output format. Export is called once per
+// module, possibly from several goroutines at once.
+type Exporter interface {
+	Name() string
+	Export(module *Module) error
+}
+
+// Finisher is implemented by exporters writing their output once all the
+// modules are exported, such as the class types exporter.
+type Finisher interface {
+	Finish() error
+}
+
+// Module is a D2O module to export. Its objects are decoded on demand, either
+// all at once with Data or one at a time through Reader, so that exporters
+// streaming their output do not hold every object in memory.
+type Module struct {
+	Name   string // file name without extension, e.g. "Items"
+	Reader *parser.D2oReader
+
+	ctx  context.Context
+	mu   sync.Mutex
+	data *parser.D2oData
+}
+
+func NewModule(name string, reader *parser.D2oReader) *Module {
+	return NewModuleContext(context.Background(), name, reader)
+}
+
+// NewModuleContext creates a module whose export stops when ctx is done.
+func NewModuleContext(ctx context.Context, name string, reader *parser.D2oReader) *Module {
+	return &Module{
+		Name:   name,
+		Reader: reader,
+		ctx:    ctx,
+	}
+}
+
+// Context returns the context of the export, which exporters decoding objects
+// one at a time should check.
+func (m *Module) Context() context.Context {
+	return m.ctx
+}
+
+func (m *Module) Classes() map[int]parser.Class {
+	return m.Reader.Classes()
+}
+
+// Data decodes all the objects of the module, once.
+func (m *Module) Data() (parser.D2oData, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.data == nil {
+		data, err := m.Reader.ReadAllContext(m.ctx)
+		if err != nil {
+			return parser.D2oData{}, err
+		}
+		m.data = &data
+	}
+	return *m.data, nil
+}
+
+// decodedData returns the objects of the module if they are already decoded.
+func (m *Module) decodedData() *parser.D2oData {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.data
+}
+
+// Factory creates an exporter writing to the given folder.
+type Factory func(outputFolderPath string) (Exporter, error)
+
+var (
+	factoriesMutex sync.Mutex
+	factories      = map[string]Factory{}
+)
+
+// Register makes an exporter available by name, for instance from the init
+// function of a package adding a custom backend to the command line tool.
+func Register(name string, factory Factory) {
+	factoriesMutex.Lock()
+	defer factoriesMutex.Unlock()
+
+	factories[name] = factory
+}
+
+// New creates the exporter registered with the given name.
+func New(name, outputFolderPath string) (Exporter, error) {
+	factoriesMutex.Lock()
+	factory, ok := factories[name]
+	factoriesMutex.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown exporter: %s", name)
+	}
+
+	return factory(outputFolderPath)
+}
+
+// Names returns the sorted names of the registered exporters.
+func Names() []string {
+	factoriesMutex.Lock()
+	defer factoriesMutex.Unlock()
+
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,120 @@
+package exporter
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/brequet/dofus-data-file-parser/pkg/generator"
+	"github.com/brequet/dofus-data-file-parser/pkg/parser"
+)
+
+// JSONExporter writes each module to <outputFolderPath>/<module>.d2o.json, or
+// .d2o.ndjson with one object per line, streaming objects from the D2O file.
+type JSONExporter struct {
+	outputFolderPath string
+	ndjson           bool
+	compression      Compression
+	writeOptions     parser.JSONWriteOptions
+}
+
+func NewJSONExporter(outputFolderPath string, ndjson bool, translations parser.Translations) *JSONExporter {
+	return &JSONExporter{
+		outputFolderPath: outputFolderPath,
+		ndjson:           ndjson,
+		writeOptions: parser.JSONWriteOptions{
+			Pretty:            true,
+			EmbedTranslations: translations,
+			IncludeObjectIds:  true,
+		},
+	}
+}
+
+// AddEnricher adds entries to the exported objects, see
+// parser.JSONWriteOptions.Enrichers. It must be called before exporting.
+func (e *JSONExporter) AddEnricher(enrich parser.Enricher) {
+	e.writeOptions.Enrichers = append(e.writeOptions.Enrichers, enrich)
+}
+
+// SetCompression compresses the written files, adding the extension of the
+// compression to their names. It must be called before exporting.
+func (e *JSONExporter) SetCompression(compression Compression) {
+	e.compression = compression
+}
+
+func (e *JSONExporter) Name() string {
+	if e.ndjson {
+		return "ndjson"
+	}
+	return "json"
+}
+
+func (e *JSONExporter) Export(module *Module) error {
+	outputPath := filepath.Join(e.outputFolderPath, module.Name+".d2o."+e.Name())
+	outputFile, err := e.compression.Create(outputPath)
+	if err != nil {
+		return err
+	}
+	defer outputFile.Close()
+
+	// buffered since objects are written one at a time
+	writer := bufio.NewWriter(outputFile)
+	err = e.write(writer, module)
+	if err != nil {
+		return err
+	}
+
+	err = writer.Flush()
+	if err != nil {
+		return err
+	}
+	return outputFile.Close()
+}
+
+func (e *JSONExporter) write(w io.Writer, module *Module) error {
+	// decoded objects are reused when another exporter already needed them
+	if data := module.decodedData(); data != nil {
+		if e.ndjson {
+			return data.WriteJSONL(w, e.writeOptions)
+		}
+		return data.WriteJSON(w, e.writeOptions)
+	}
+
+	if e.ndjson {
+		return module.Reader.WriteJSONLContext(module.Context(), w, e.writeOptions)
+	}
+	return module.Reader.WriteJSONContext(module.Context(), w, e.writeOptions)
+}
+
+// JSONSchemaExporter writes the JSON Schema of the JSON export of each module
+// to <outputFolderPath>/<module>.d2o.schema.json.
+type JSONSchemaExporter struct {
+	outputFolderPath string
+	compression      Compression
+}
+
+func NewJSONSchemaExporter(outputFolderPath string) *JSONSchemaExporter {
+	return &JSONSchemaExporter{
+		outputFolderPath: outputFolderPath,
+	}
+}
+
+// SetCompression compresses the written files, see
+// JSONExporter.SetCompression.
+func (e *JSONSchemaExporter) SetCompression(compression Compression) {
+	e.compression = compression
+}
+
+func (e *JSONSchemaExporter) Name() string {
+	return "schema"
+}
+
+func (e *JSONSchemaExporter) Export(module *Module) error {
+	schema, err := generator.GenerateJSONSchemaFromClasses(module.Name, module.Classes())
+	if err != nil {
+		return fmt.Errorf("error generating json schema: %w", err)
+	}
+
+	return e.compression.WriteFile(filepath.Join(e.outputFolderPath, module.Name+".d2o.schema.json"), schema)
+}
@@ -0,0 +1,66 @@
+package exporter
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/brequet/dofus-data-file-parser/pkg/parser"
+)
+
+// WritePOFile writes the texts of a locale as a gettext PO file. Each text is
+// an entry whose context is its id, or its key for named texts, and whose
+// msgid is the text in the source locale, the one the game is written in. The
+// text itself is used as msgid when it has no source text.
+func WritePOFile(poFilePath, locale string, data, source parser.D2iData) error {
+	var fileContent bytes.Buffer
+
+	fileContent.WriteString("msgid \"\"\n")
+	fileContent.WriteString("msgstr \"\"\n")
+	fileContent.WriteString(fmt.Sprintf("\"Language: %s\\n\"\n", locale))
+	fileContent.WriteString("\"MIME-Version: 1.0\\n\"\n")
+	fileContent.WriteString("\"Content-Type: text/plain; charset=UTF-8\\n\"\n")
+	fileContent.WriteString("\"Content-Transfer-Encoding: 8bit\\n\"\n")
+
+	ids := make([]int, 0, len(data.Texts))
+	for id := range data.Texts {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	for _, id := range ids {
+		sourceText := source.Texts[id].Text
+		fileContent.WriteString(buildPOEntry(strconv.Itoa(id), sourceText, data.Texts[id].Text))
+	}
+
+	keys := make([]string, 0, len(data.NamedTexts))
+	for key := range data.NamedTexts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fileContent.WriteString(buildPOEntry(key, source.NamedTexts[key], data.NamedTexts[key]))
+	}
+
+	return os.WriteFile(poFilePath, fileContent.Bytes(), 0644)
+}
+
+func buildPOEntry(context, sourceText, text string) string {
+	if sourceText == "" {
+		sourceText = text
+	}
+	if sourceText == "" {
+		// an empty msgid is reserved for the header
+		return ""
+	}
+
+	return fmt.Sprintf("\nmsgctxt %s\nmsgid %s\nmsgstr %s\n", quotePOString(context), quotePOString(sourceText), quotePOString(text))
+}
+
+var poStringReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+
+func quotePOString(str string) string {
+	return `"` + poStringReplacer.Replace(str) + `"`
+}
@@ -0,0 +1,151 @@
+package exporter
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/brequet/dofus-data-file-parser/pkg/parser"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// SQLiteExporter writes modules into a single SQLite database, with one table
+// per class and a translations table. It is safe for concurrent use, exports
+// being serialized as SQLite allows a single writer.
+type SQLiteExporter struct {
+	db *sql.DB
+	mu sync.Mutex
+}
+
+func NewSQLiteExporter(databasePath string) (*SQLiteExporter, error) {
+	db, err := sql.Open("sqlite3", databasePath)
+	if err != nil {
+		return nil, fmt.Errorf("error opening database: %w", err)
+	}
+
+	_, err = db.Exec(`CREATE TABLE IF NOT EXISTS "translations" ("locale" TEXT NOT NULL, "id" INTEGER NOT NULL, "text" TEXT NOT NULL, PRIMARY KEY ("locale", "id"))`)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error creating translations table: %w", err)
+	}
+
+	return &SQLiteExporter{
+		db: db,
+	}, nil
+}
+
+func (e *SQLiteExporter) Close() error {
+	return e.db.Close()
+}
+
+func (e *SQLiteExporter) Name() string {
+	return "sqlite"
+}
+
+func (e *SQLiteExporter) Export(module *Module) error {
+	data, err := module.Data()
+	if err != nil {
+		return err
+	}
+
+	return e.ExportModule(module.Name, data)
+}
+
+// ExportModule inserts the objects of a module in the table of their class.
+// Objects of a class shared by several modules go to the same table, the
+// module they come from being stored in the "_module" column.
+func (e *SQLiteExporter) ExportModule(moduleName string, data parser.D2oData) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	tx, err := e.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, class := range data.Classes {
+		err = createClassTable(tx, class)
+		if err != nil {
+			return err
+		}
+
+		// rows of a previous export of the module are replaced
+		_, err = tx.Exec(fmt.Sprintf(`DELETE FROM %s WHERE "_module" = ?`, parser.QuoteSQLIdentifier(class.PackageClass)), moduleName)
+		if err != nil {
+			return fmt.Errorf("error clearing %s: %w", class.PackageClass, err)
+		}
+
+		columns, rows, err := data.ToFlatTable(class)
+		if err != nil {
+			return fmt.Errorf("error flattening class %s: %w", class.PackageClass, err)
+		}
+		if len(rows) == 0 {
+			continue
+		}
+
+		quotedColumns := []string{`"_module"`}
+		placeholders := []string{"?"}
+		for _, column := range columns {
+			quotedColumns = append(quotedColumns, parser.QuoteSQLIdentifier(column))
+			placeholders = append(placeholders, "?")
+		}
+
+		statement, err := tx.Prepare(fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", parser.QuoteSQLIdentifier(class.PackageClass), strings.Join(quotedColumns, ", "), strings.Join(placeholders, ", ")))
+		if err != nil {
+			return fmt.Errorf("error preparing insert into %s: %w", class.PackageClass, err)
+		}
+
+		for _, row := range rows {
+			_, err = statement.Exec(append([]any{moduleName}, row...)...)
+			if err != nil {
+				statement.Close()
+				return fmt.Errorf("error inserting into %s: %w", class.PackageClass, err)
+			}
+		}
+		statement.Close()
+	}
+
+	return tx.Commit()
+}
+
+func (e *SQLiteExporter) ExportTranslations(locale string, translations parser.Translations) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	tx, err := e.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	statement, err := tx.Prepare(`INSERT OR REPLACE INTO "translations" ("locale", "id", "text") VALUES (?, ?, ?)`)
+	if err != nil {
+		return fmt.Errorf("error preparing insert into translations: %w", err)
+	}
+	defer statement.Close()
+
+	for id, text := range translations {
+		_, err = statement.Exec(locale, id, text)
+		if err != nil {
+			return fmt.Errorf("error inserting translation %d: %w", id, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func createClassTable(tx *sql.Tx, class parser.Class) error {
+	columnDefinitions := []string{`"_module" TEXT NOT NULL`}
+	for _, field := range class.Fields {
+		columnDefinitions = append(columnDefinitions, field.SQLColumnDefinition(parser.SQLite))
+	}
+
+	_, err := tx.Exec(fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s)", parser.QuoteSQLIdentifier(class.PackageClass), strings.Join(columnDefinitions, ", ")))
+	if err != nil {
+		return fmt.Errorf("error creating table %s: %w", class.PackageClass, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,389 @@
+package exporter
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/brequet/dofus-data-file-parser/pkg/generator"
+	"github.com/brequet/dofus-data-file-parser/pkg/parser"
+)
+
+// TypesExporter generates the class types of the exported modules, in Go,
+// TypeScript (interfaces or Zod schemas), Python (dataclasses or Pydantic),
+// Kotlin, Rust or C#, their Markdown documentation ("docs"), or the graph of
+// their references ("dot" or "mermaid"), under <outputFolderPath>/<lang>.
+// Types are written by Finish, once the classes of every module are known.
+type TypesExporter struct {
+	lang             string
+	outputFolderPath string
+	goOptions        generator.GoOptions
+	templates        generator.Templates
+
+	mu          sync.Mutex
+	classTables map[string]map[int]parser.Class // module name -> class table
+}
+
+// TypesLanguages are the languages supported by the types exporter.
+var TypesLanguages = []string{"go", "ts", "zod", "python", "pydantic", "kotlin", "rust", "csharp", "docs", "dot", "mermaid"}
+
+func NewTypesExporter(lang, outputFolderPath string) (*TypesExporter, error) {
+	if !slices.Contains(TypesLanguages, lang) {
+		return nil, fmt.Errorf("unsupported language: %s", lang)
+	}
+
+	return &TypesExporter{
+		lang:             lang,
+		outputFolderPath: outputFolderPath,
+		classTables:      map[string]map[int]parser.Class{},
+	}, nil
+}
+
+// SetGoOptions sets the options of the generated Go files.
+func (e *TypesExporter) SetGoOptions(options generator.GoOptions) {
+	e.goOptions = options
+}
+
+// SetTemplates sets the templates the class files are generated with.
+func (e *TypesExporter) SetTemplates(templates generator.Templates) {
+	e.templates = templates
+}
+
+func (e *TypesExporter) Name() string {
+	return e.lang
+}
+
+func (e *TypesExporter) Export(module *Module) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.classTables[module.Name] = module.Classes()
+	return nil
+}
+
+// Finish writes the types. Class tables are merged in module name order, for
+// the generated types not to depend on the order modules were exported in.
+func (e *TypesExporter) Finish() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	moduleNames := make([]string, 0, len(e.classTables))
+	for moduleName := range e.classTables {
+		moduleNames = append(moduleNames, moduleName)
+	}
+	sort.Strings(moduleNames)
+
+	err := os.MkdirAll(filepath.Join(e.outputFolderPath, e.lang), 0755)
+	if err != nil {
+		return fmt.Errorf("error creating %s folder: %w", e.lang, err)
+	}
+
+	switch e.lang {
+	case "docs":
+		return exportClassDocsToMarkdown(moduleNames, e.classTables, e.outputFolderPath)
+	case "dot":
+		graph := generator.BuildClassGraph(e.classTables)
+		return writeClassGraph(generator.GenerateDOTFromClassGraph(graph), filepath.Join(e.outputFolderPath, "dot", "classes.dot"))
+	case "mermaid":
+		graph := generator.BuildClassGraph(e.classTables)
+		return writeClassGraph(generator.GenerateMermaidFromClassGraph(graph), filepath.Join(e.outputFolderPath, "mermaid", "classes.mmd"))
+	}
+
+	classes := map[string]map[string]parser.Class{}
+	for _, moduleName := range moduleNames {
+		addClassesByPackage(classes, e.classTables[moduleName])
+	}
+
+	switch e.lang {
+	case "ts":
+		return exportClassTypesToTypeScript(classes, e.outputFolderPath, e.templates)
+	case "python":
+		return exportClassTypesToPython(classes, e.outputFolderPath, e.templates)
+	case "zod":
+		return exportClassTypesToZod(classes, e.outputFolderPath, e.templates)
+	case "pydantic":
+		return exportClassTypesToPydantic(classes, e.outputFolderPath, e.templates)
+	case "kotlin":
+		return exportClassTypesToKotlin(classes, e.outputFolderPath, e.templates)
+	case "rust":
+		return exportClassTypesToRust(classes, e.outputFolderPath, e.templates)
+	case "csharp":
+		return exportClassTypesToCSharp(classes, e.outputFolderPath, e.templates)
+	default:
+		goOptions := e.goOptions
+		goOptions.Templates = e.templates
+		return exportClassTypesToGolang(classes, e.outputFolderPath, goOptions)
+	}
+}
+
+// addClassesByPackage adds the classes of a class table to classes, by package
+// and class name, keeping the classes already known.
+func addClassesByPackage(classes map[string]map[string]parser.Class, classTable map[int]parser.Class) {
+	classIds := make([]int, 0, len(classTable))
+	for classId := range classTable {
+		classIds = append(classIds, classId)
+	}
+	sort.Ints(classIds)
+
+	for _, classId := range classIds {
+		class := classTable[classId]
+		if classes[class.PackageName] == nil {
+			classes[class.PackageName] = map[string]parser.Class{}
+		}
+		if _, ok := classes[class.PackageName][class.PackageClass]; !ok {
+			classes[class.PackageName][class.PackageClass] = class
+		}
+	}
+}
+
+// sortedClassList returns the classes of a package sorted by name.
+func sortedClassList(classMap map[string]parser.Class) []parser.Class {
+	classList := make([]parser.Class, 0, len(classMap))
+	for _, class := range classMap {
+		classList = append(classList, class)
+	}
+	sort.Slice(classList, func(i, j int) bool {
+		return classList[i].PackageClass < classList[j].PackageClass
+	})
+	return classList
+}
+
+// allSortedClasses returns the classes of every package, sorted by package
+// and class name.
+func allSortedClasses(classes map[string]map[string]parser.Class) []parser.Class {
+	packageNames := make([]string, 0, len(classes))
+	for packageName := range classes {
+		packageNames = append(packageNames, packageName)
+	}
+	sort.Strings(packageNames)
+
+	allClasses := make([]parser.Class, 0)
+	for _, packageName := range packageNames {
+		allClasses = append(allClasses, sortedClassList(classes[packageName])...)
+	}
+	return allClasses
+}
+
+func exportClassTypesToGolang(classes map[string]map[string]parser.Class, outputFolderPath string, options generator.GoOptions) error {
+	allClasses := allSortedClasses(classes)
+	options.ExtendedClasses = generator.ExtendedClassNames(allClasses)
+	for packageName, classMap := range classes {
+		classList := sortedClassList(classMap)
+
+		goFileContent, err := generator.GenerateGoFromClasses(classList, options)
+		if err != nil {
+			return fmt.Errorf("error generating golang from classes: %w", err)
+		}
+
+		fileName := packageName[strings.LastIndex(packageName, ".")+1:] + ".go"
+
+		goFilePath := filepath.Join(outputFolderPath, "go", fileName)
+		err = os.WriteFile(goFilePath, goFileContent, 0644)
+		if err != nil {
+			return fmt.Errorf("error writing file: %w", err)
+		}
+	}
+
+	hierarchyFileContent, err := generator.GenerateGoHierarchyFromClasses(allClasses, options)
+	if err != nil {
+		return fmt.Errorf("error generating golang hierarchy: %w", err)
+	}
+
+	err = os.WriteFile(filepath.Join(outputFolderPath, "go", "hierarchy.go"), hierarchyFileContent, 0644)
+	if err != nil {
+		return fmt.Errorf("error writing file: %w", err)
+	}
+
+	registryFileContent, err := generator.GenerateGoRegistryFromClasses(allClasses, options)
+	if err != nil {
+		return fmt.Errorf("error generating golang registry: %w", err)
+	}
+
+	err = os.WriteFile(filepath.Join(outputFolderPath, "go", "registry.go"), registryFileContent, 0644)
+	if err != nil {
+		return fmt.Errorf("error writing file: %w", err)
+	}
+
+	return nil
+}
+
+func exportClassTypesToTypeScript(classes map[string]map[string]parser.Class, outputFolderPath string, templates generator.Templates) error {
+	for packageName, classMap := range classes {
+		classList := sortedClassList(classMap)
+
+		tsFileContent, err := generator.GenerateTypeScriptFromClasses(classList, templates)
+		if err != nil {
+			return fmt.Errorf("error generating typescript from classes: %w", err)
+		}
+
+		fileName := packageName[strings.LastIndex(packageName, ".")+1:] + ".d.ts"
+
+		tsFilePath := filepath.Join(outputFolderPath, "ts", fileName)
+		err = os.WriteFile(tsFilePath, tsFileContent, 0644)
+		if err != nil {
+			return fmt.Errorf("error writing file: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func exportClassTypesToPython(classes map[string]map[string]parser.Class, outputFolderPath string, templates generator.Templates) error {
+	for packageName, classMap := range classes {
+		classList := sortedClassList(classMap)
+
+		pyFileContent, err := generator.GeneratePythonDataclassesFromClasses(classList, templates)
+		if err != nil {
+			return fmt.Errorf("error generating python from classes: %w", err)
+		}
+
+		fileName := packageName[strings.LastIndex(packageName, ".")+1:] + ".py"
+
+		pyFilePath := filepath.Join(outputFolderPath, "python", fileName)
+		err = os.WriteFile(pyFilePath, pyFileContent, 0644)
+		if err != nil {
+			return fmt.Errorf("error writing file: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// exportClassTypesToZod writes the schemas of every package to a single
+// schemas.ts file, as they reference each other.
+func exportClassTypesToZod(classes map[string]map[string]parser.Class, outputFolderPath string, templates generator.Templates) error {
+	tsFileContent, err := generator.GenerateZodFromClasses(allSortedClasses(classes), templates)
+	if err != nil {
+		return fmt.Errorf("error generating zod from classes: %w", err)
+	}
+
+	err = os.WriteFile(filepath.Join(outputFolderPath, "zod", "schemas.ts"), tsFileContent, 0644)
+	if err != nil {
+		return fmt.Errorf("error writing file: %w", err)
+	}
+
+	return nil
+}
+
+// exportClassTypesToPydantic writes the models of every package to a single
+// models.py file, as they reference each other.
+func exportClassTypesToPydantic(classes map[string]map[string]parser.Class, outputFolderPath string, templates generator.Templates) error {
+	pyFileContent, err := generator.GeneratePydanticFromClasses(allSortedClasses(classes), templates)
+	if err != nil {
+		return fmt.Errorf("error generating pydantic from classes: %w", err)
+	}
+
+	err = os.WriteFile(filepath.Join(outputFolderPath, "pydantic", "models.py"), pyFileContent, 0644)
+	if err != nil {
+		return fmt.Errorf("error writing file: %w", err)
+	}
+
+	return nil
+}
+
+func exportClassTypesToKotlin(classes map[string]map[string]parser.Class, outputFolderPath string, templates generator.Templates) error {
+	for packageName, classMap := range classes {
+		classList := sortedClassList(classMap)
+
+		ktFileContent, err := generator.GenerateKotlinFromClasses(classList, templates)
+		if err != nil {
+			return fmt.Errorf("error generating kotlin from classes: %w", err)
+		}
+
+		fileName := packageName[strings.LastIndex(packageName, ".")+1:] + ".kt"
+
+		ktFilePath := filepath.Join(outputFolderPath, "kotlin", fileName)
+		err = os.WriteFile(ktFilePath, ktFileContent, 0644)
+		if err != nil {
+			return fmt.Errorf("error writing file: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// exportClassTypesToRust writes a module per package, and the mod.rs declaring
+// them.
+func exportClassTypesToRust(classes map[string]map[string]parser.Class, outputFolderPath string, templates generator.Templates) error {
+	moduleNames := make([]string, 0, len(classes))
+	for packageName, classMap := range classes {
+		classList := sortedClassList(classMap)
+
+		rsFileContent, err := generator.GenerateRustFromClasses(classList, templates)
+		if err != nil {
+			return fmt.Errorf("error generating rust from classes: %w", err)
+		}
+
+		moduleName := packageName[strings.LastIndex(packageName, ".")+1:]
+		moduleNames = append(moduleNames, moduleName)
+
+		rsFilePath := filepath.Join(outputFolderPath, "rust", moduleName+".rs")
+		err = os.WriteFile(rsFilePath, rsFileContent, 0644)
+		if err != nil {
+			return fmt.Errorf("error writing file: %w", err)
+		}
+	}
+
+	err := os.WriteFile(filepath.Join(outputFolderPath, "rust", "mod.rs"), generator.GenerateRustModFromModules(moduleNames), 0644)
+	if err != nil {
+		return fmt.Errorf("error writing file: %w", err)
+	}
+
+	return nil
+}
+
+func exportClassTypesToCSharp(classes map[string]map[string]parser.Class, outputFolderPath string, templates generator.Templates) error {
+	for packageName, classMap := range classes {
+		classList := sortedClassList(classMap)
+
+		csFileContent, err := generator.GenerateCSharpFromClasses(classList, templates)
+		if err != nil {
+			return fmt.Errorf("error generating c# from classes: %w", err)
+		}
+
+		fileName := packageName[strings.LastIndex(packageName, ".")+1:] + ".cs"
+
+		csFilePath := filepath.Join(outputFolderPath, "csharp", fileName)
+		err = os.WriteFile(csFilePath, csFileContent, 0644)
+		if err != nil {
+			return fmt.Errorf("error writing file: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// exportClassDocsToMarkdown writes the documentation page of each module, as
+// <module>.md, and an index.md linking them.
+func exportClassDocsToMarkdown(moduleNames []string, classTables map[string]map[int]parser.Class, outputFolderPath string) error {
+	for _, moduleName := range moduleNames {
+		mdFileContent, err := generator.GenerateMarkdownFromClasses(moduleName, classTables[moduleName])
+		if err != nil {
+			return fmt.Errorf("error generating markdown from classes: %w", err)
+		}
+
+		err = os.WriteFile(filepath.Join(outputFolderPath, "docs", moduleName+".md"), mdFileContent, 0644)
+		if err != nil {
+			return fmt.Errorf("error writing file: %w", err)
+		}
+	}
+
+	err := os.WriteFile(filepath.Join(outputFolderPath, "docs", "index.md"), generator.GenerateMarkdownIndex(moduleNames), 0644)
+	if err != nil {
+		return fmt.Errorf("error writing file: %w", err)
+	}
+
+	return nil
+}
+
+func writeClassGraph(graphFileContent []byte, graphFilePath string) error {
+	err := os.WriteFile(graphFilePath, graphFileContent, 0644)
+	if err != nil {
+		return fmt.Errorf("error writing file: %w", err)
+	}
+	return nil
+}
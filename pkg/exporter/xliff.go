@@ -0,0 +1,147 @@
+package exporter
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/brequet/dofus-data-file-parser/pkg/parser"
+)
+
+type xliff12 struct {
+	XMLName xml.Name    `xml:"urn:oasis:names:tc:xliff:document:1.2 xliff"`
+	Version string      `xml:"version,attr"`
+	File    xliff12File `xml:"file"`
+}
+
+type xliff12File struct {
+	Original       string             `xml:"original,attr"`
+	SourceLanguage string             `xml:"source-language,attr"`
+	TargetLanguage string             `xml:"target-language,attr"`
+	Datatype       string             `xml:"datatype,attr"`
+	TransUnits     []xliff12TransUnit `xml:"body>trans-unit"`
+}
+
+type xliff12TransUnit struct {
+	Id     string `xml:"id,attr"`
+	Source string `xml:"source"`
+	Target string `xml:"target"`
+}
+
+type xliff20 struct {
+	XMLName xml.Name    `xml:"urn:oasis:names:tc:xliff:document:2.0 xliff"`
+	Version string      `xml:"version,attr"`
+	SrcLang string      `xml:"srcLang,attr"`
+	TrgLang string      `xml:"trgLang,attr"`
+	File    xliff20File `xml:"file"`
+}
+
+type xliff20File struct {
+	Id    string        `xml:"id,attr"`
+	Units []xliff20Unit `xml:"unit"`
+}
+
+type xliff20Unit struct {
+	Id     string `xml:"id,attr"`
+	Source string `xml:"segment>source"`
+	Target string `xml:"segment>target"`
+}
+
+// xliffEntry is a translation unit, common to both XLIFF versions.
+type xliffEntry struct {
+	id     string
+	source string
+	target string
+}
+
+// WriteXLIFFFile writes the texts of a locale as an XLIFF 1.2 or 2.0 file, the
+// source texts being those of the source locale, like in WritePOFile. Texts
+// having an undiacritical variant get a second unit, "<id>.undiacritical".
+func WriteXLIFFFile(xliffFilePath, version, locale, sourceLocale string, data, source parser.D2iData) error {
+	entries := buildXLIFFEntries(data, source)
+	original := "i18n_" + locale + ".d2i"
+
+	var document any
+	switch version {
+	case "1.2":
+		transUnits := make([]xliff12TransUnit, 0, len(entries))
+		for _, entry := range entries {
+			transUnits = append(transUnits, xliff12TransUnit{Id: entry.id, Source: entry.source, Target: entry.target})
+		}
+		document = xliff12{
+			Version: version,
+			File: xliff12File{
+				Original:       original,
+				SourceLanguage: sourceLocale,
+				TargetLanguage: locale,
+				Datatype:       "plaintext",
+				TransUnits:     transUnits,
+			},
+		}
+	case "2.0":
+		units := make([]xliff20Unit, 0, len(entries))
+		for _, entry := range entries {
+			units = append(units, xliff20Unit{Id: entry.id, Source: entry.source, Target: entry.target})
+		}
+		document = xliff20{
+			Version: version,
+			SrcLang: sourceLocale,
+			TrgLang: locale,
+			File: xliff20File{
+				Id:    original,
+				Units: units,
+			},
+		}
+	default:
+		return fmt.Errorf("unsupported XLIFF version: %s", version)
+	}
+
+	xmlStr, err := xml.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshalling xml: %w", err)
+	}
+
+	return os.WriteFile(xliffFilePath, append([]byte(xml.Header), xmlStr...), 0644)
+}
+
+func buildXLIFFEntries(data, source parser.D2iData) []xliffEntry {
+	entries := make([]xliffEntry, 0, len(data.Texts)+len(data.NamedTexts))
+
+	ids := make([]int, 0, len(data.Texts))
+	for id := range data.Texts {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	for _, id := range ids {
+		text := data.Texts[id]
+		sourceText := source.Texts[id]
+		entries = append(entries, newXLIFFEntry(strconv.Itoa(id), sourceText.Text, text.Text))
+		if text.UndiacriticalText != "" {
+			sourceUndiacriticalText := sourceText.UndiacriticalText
+			if sourceUndiacriticalText == "" {
+				sourceUndiacriticalText = sourceText.Text
+			}
+			entries = append(entries, newXLIFFEntry(strconv.Itoa(id)+".undiacritical", sourceUndiacriticalText, text.UndiacriticalText))
+		}
+	}
+
+	keys := make([]string, 0, len(data.NamedTexts))
+	for key := range data.NamedTexts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		entries = append(entries, newXLIFFEntry(key, source.NamedTexts[key], data.NamedTexts[key]))
+	}
+
+	return entries
+}
+
+func newXLIFFEntry(id, sourceText, text string) xliffEntry {
+	if sourceText == "" {
+		sourceText = text
+	}
+	return xliffEntry{id: id, source: sourceText, target: text}
+}
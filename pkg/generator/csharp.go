@@ -0,0 +1,82 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/brequet/dofus-data-file-parser/pkg/parser"
+)
+
+// csharpNamespace is the namespace of the generated C# files.
+const csharpNamespace = "Types"
+
+// GenerateCSharpFromClasses generates classes with System.Text.Json
+// attributes, subclasses extending the class of their parent.
+func GenerateCSharpFromClasses(classes []parser.Class, templates ...Templates) ([]byte, error) {
+	data := TemplateData{
+		PackageName: csharpNamespace,
+		Classes:     make([]TemplateClass, 0, len(classes)),
+	}
+	for _, class := range classes {
+		templateClass := TemplateClass{Name: class.PackageClass, Parent: class.Parent, Class: class}
+		fields := class.Fields
+		if class.Parent != "" {
+			// inherited fields come first and are those of the parent class
+			fields = fields[class.ParentFields:]
+		}
+
+		// members cannot be named after their class
+		propertyNames := map[string]bool{class.PackageClass: true}
+		for _, field := range fields {
+			propertyType := mapFieldTypeToCSharpType(field, false)
+			templateField := TemplateField{
+				Name:      uniqueGoFieldName(field.Name, propertyNames),
+				FieldName: field.Name,
+				Type:      propertyType,
+				Tag:       fmt.Sprintf("[JsonPropertyName(%q)]", field.Name),
+			}
+			// non nullable references are set once deserialized
+			if propertyType == "string" || strings.HasPrefix(propertyType, "List<") {
+				templateField.Default = "null!"
+			}
+			templateClass.Fields = append(templateClass.Fields, templateField)
+		}
+		data.Classes = append(data.Classes, templateClass)
+	}
+
+	return getTemplates(templates).execute("csharp.tmpl", data)
+}
+
+func mapFieldTypeToCSharpType(field parser.GameDataField, vectorElement bool) string {
+	if isNullable(field, vectorElement) {
+		return mapNonNullFieldTypeToCSharpType(field) + "?"
+	}
+	return mapNonNullFieldTypeToCSharpType(field)
+}
+
+func mapNonNullFieldTypeToCSharpType(field parser.GameDataField) string {
+	switch {
+	case field.Type == parser.Vector:
+		return "List<" + mapFieldTypeToCSharpType(*field.SubType, true) + ">"
+	case field.Type > 0:
+		if field.TypeName == "" {
+			return "JsonElement?"
+		}
+		return field.TypeName
+	}
+
+	switch field.Type {
+	case parser.Integer, parser.I18n:
+		return "int"
+	case parser.UnsignedInteger:
+		return "uint"
+	case parser.Boolean:
+		return "bool"
+	case parser.String:
+		return "string"
+	case parser.Number:
+		return "double"
+	default:
+		return "JsonElement?"
+	}
+}
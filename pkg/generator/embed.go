@@ -0,0 +1,155 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/brequet/dofus-data-file-parser/pkg/parser"
+)
+
+// GoEmbedPackageName returns the package of the Go embed package of a module,
+// e.g. "items" for Items.
+func GoEmbedPackageName(moduleName string) string {
+	return strings.ToLower(toGoIdentifier(moduleName))
+}
+
+// GoEmbedDataFileName is the name of the JSON Lines file of the objects of a
+// module, embedded in its package, written with their "ObjectId_" entry.
+func GoEmbedDataFileName(moduleName string) string {
+	return GoEmbedPackageName(moduleName) + ".jsonl"
+}
+
+// GenerateGoEmbedAccessors generates the accessors of the objects of a module
+// embedded in its package: ByID, IDs, All and Raw. Objects are decoded as the
+// root class of the module, the one of its first class, along with the
+// classes generated by GenerateGoFromClasses in the same package; Raw gives
+// the fields of the subclasses. The package name of the options is replaced
+// by GoEmbedPackageName.
+func GenerateGoEmbedAccessors(moduleName string, classTable map[int]parser.Class, opts ...GoOptions) ([]byte, error) {
+	options := getGoOptions(opts)
+	options.PackageName = GoEmbedPackageName(moduleName)
+	typeName := rootClassName(classTable)
+	if typeName == "" {
+		return nil, fmt.Errorf("module %s has no class", moduleName)
+	}
+
+	var fileContent bytes.Buffer
+
+	options.writePreamble(&fileContent)
+	fileContent.WriteString(`import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"slices"
+	"sync"
+)
+
+`)
+	fileContent.WriteString(fmt.Sprintf("//go:embed %s\nvar data []byte\n\n", GoEmbedDataFileName(moduleName)))
+	fileContent.WriteString(`var (
+	loadOnce sync.Once
+	objects  map[int]json.RawMessage // id -> JSON object
+	ids      []int
+)
+
+// load indexes the embedded objects by id, once.
+func load() {
+	loadOnce.Do(func() {
+		objects = map[int]json.RawMessage{}
+		for _, line := range bytes.Split(data, []byte("\n")) {
+			if len(line) == 0 {
+				continue
+			}
+			var entry struct {
+				ObjectId int ` + "`json:\"ObjectId_\"`" + `
+			}
+			err := json.Unmarshal(line, &entry)
+			if err != nil {
+				panic("` + options.PackageName + `: invalid embedded object: " + err.Error())
+			}
+			objects[entry.ObjectId] = line
+			ids = append(ids, entry.ObjectId)
+		}
+	})
+}
+
+`)
+	fileContent.WriteString(fmt.Sprintf(`// IDs returns the ids of the %[1]s objects, in ascending order.
+func IDs() []int {
+	load()
+	return slices.Clone(ids)
+}
+
+// Raw returns the JSON of an object, false when there is none of this id. It
+// holds the fields of the class named by its "ClassType_" entry, which may
+// extend %[2]s.
+func Raw(id int) (json.RawMessage, bool) {
+	load()
+	object, ok := objects[id]
+	return object, ok
+}
+
+// ByID returns the object of the given id, false when there is none.
+func ByID(id int) (*%[2]s, bool) {
+	raw, ok := Raw(id)
+	if !ok {
+		return nil, false
+	}
+	return decode(raw), true
+}
+
+// All returns every object, in id order.
+func All() []%[2]s {
+	load()
+	all := make([]%[2]s, 0, len(ids))
+	for _, id := range ids {
+		all = append(all, *decode(objects[id]))
+	}
+	return all
+}
+
+func decode(raw json.RawMessage) *%[2]s {
+	object := &%[2]s{}
+	err := json.Unmarshal(raw, object)
+	if err != nil {
+		panic("%[3]s: invalid embedded object: " + err.Error())
+	}
+	return object
+}
+`, moduleName, typeName, options.PackageName))
+
+	accessorsGoFileContent, err := formatGolangFile(fileContent.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format file to golang: %w", err)
+	}
+
+	return accessorsGoFileContent, nil
+}
+
+// rootClassName returns the root class of the first class of a class table,
+// the class of the objects of the module and of the classes extending it.
+func rootClassName(classTable map[int]parser.Class) string {
+	classIds := make([]int, 0, len(classTable))
+	classesByName := map[string]parser.Class{}
+	for classId, class := range classTable {
+		classIds = append(classIds, classId)
+		classesByName[class.PackageClass] = class
+	}
+	if len(classIds) == 0 {
+		return ""
+	}
+	sort.Ints(classIds)
+
+	class := classTable[classIds[0]]
+	for seen := map[string]bool{}; class.Parent != "" && !seen[class.Parent]; {
+		parent, ok := classesByName[class.Parent]
+		if !ok {
+			break
+		}
+		seen[class.Parent] = true
+		class = parent
+	}
+	return class.PackageClass
+}
@@ -0,0 +1,110 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/brequet/dofus-data-file-parser/pkg/parser"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// GenerateGoEnumFromObjects generates a const block with one constant per
+// object of a module, named after the enum name and the object name, whose
+// value is the object id. Object names come from the "name" field, or from the
+// "nameId" field resolved with translations, falling back to the first string
+// or I18n field of the class.
+func GenerateGoEnumFromObjects(enumName string, data parser.D2oData, translations parser.Translations, opts ...GoOptions) ([]byte, error) {
+	enumName = toGoIdentifier(enumName)
+
+	var fileContent bytes.Buffer
+
+	getGoOptions(opts).writePreamble(&fileContent)
+	fileContent.WriteString(fmt.Sprintf("// %s ids\n", enumName))
+	fileContent.WriteString("const (\n")
+
+	classesByName := map[string]parser.Class{}
+	for _, class := range data.Classes {
+		classesByName[class.PackageClass] = class
+	}
+
+	constantNames := map[string]bool{}
+	for _, objectId := range data.ObjectIDs() {
+		object, _ := data.ObjectByID(objectId)
+		objectMap, ok := object.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		if id, ok := objectMap["id"].(int); ok {
+			objectId = id
+		}
+
+		className, _ := objectMap["ClassType_"].(string)
+		name := getEnumObjectName(objectMap, classesByName[className], translations)
+		constantName := enumName + toGoIdentifier(name)
+		if name == "" || constantNames[constantName] {
+			constantName = fmt.Sprintf("%s%s%d", enumName, toGoIdentifier(name), objectId)
+		}
+		constantNames[constantName] = true
+
+		if name != "" {
+			fileContent.WriteString(fmt.Sprintf("%s = %d // %s\n", constantName, objectId, strings.ReplaceAll(name, "\n", " ")))
+		} else {
+			fileContent.WriteString(fmt.Sprintf("%s = %d\n", constantName, objectId))
+		}
+	}
+	fileContent.WriteString(")\n")
+
+	enumGoFileContent, err := formatGolangFile(fileContent.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format file to golang: %w", err)
+	}
+
+	return enumGoFileContent, nil
+}
+
+func getEnumObjectName(objectMap map[string]any, class parser.Class, translations parser.Translations) string {
+	if name, ok := objectMap["name"].(string); ok {
+		return name
+	}
+	if nameId, ok := objectMap["nameId"].(int); ok && translations[nameId] != "" {
+		return translations[nameId]
+	}
+
+	for _, field := range class.Fields {
+		switch value := objectMap[field.Name].(type) {
+		case string:
+			if field.Type == parser.String {
+				return value
+			}
+		case int:
+			if field.Type == parser.I18n && translations[value] != "" {
+				return translations[value]
+			}
+		}
+	}
+
+	return ""
+}
+
+// toGoIdentifier turns a text into an exported Go identifier, stripping
+// diacritics and titling the words it is made of.
+func toGoIdentifier(text string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	stripped, _, err := transform.String(t, text)
+	if err != nil {
+		stripped = text
+	}
+
+	var identifier strings.Builder
+	for _, word := range strings.FieldsFunc(stripped, func(r rune) bool {
+		return r > unicode.MaxASCII || (!unicode.IsLetter(r) && !unicode.IsDigit(r))
+	}) {
+		identifier.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	return identifier.String()
+}
@@ -4,14 +4,18 @@ import (
 	"bytes"
 	"fmt"
 	"go/format"
+	"log/slog"
+	"slices"
+	"sort"
+	"unicode"
 
 	"github.com/brequet/dofus-data-file-parser/pkg/parser"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 )
 
-func GenerateGoFromClasses(classes []parser.Class) ([]byte, error) {
-	fileContent, err := buildFileContent(classes)
+func GenerateGoFromClasses(classes []parser.Class, opts ...GoOptions) ([]byte, error) {
+	fileContent, err := buildFileContent(classes, getGoOptions(opts))
 	if err != nil {
 		return nil, fmt.Errorf("build file content: %w", err)
 	}
@@ -34,60 +38,108 @@ func formatGolangFile(fileContent []byte) ([]byte, error) {
 	return formattedSrc, nil
 }
 
-func buildFileContent(classList []parser.Class) ([]byte, error) {
-	var fileContent bytes.Buffer
-
-	fileContent.WriteString("package types\n\n")
-
+func buildFileContent(classList []parser.Class, options GoOptions) ([]byte, error) {
+	data := TemplateData{
+		Header:      options.headerLines(),
+		BuildTags:   options.BuildTags,
+		PackageName: options.packageName(),
+		Classes:     make([]TemplateClass, 0, len(classList)),
+	}
 	for _, class := range classList {
-		fileContent.WriteString(buildClassStruct(class))
+		data.Classes = append(data.Classes, buildClassStruct(class, options))
 	}
 
-	return fileContent.Bytes(), nil
+	return options.Templates.execute("go.tmpl", data)
 }
 
-func buildClassStruct(class parser.Class) string {
-	var fileContent bytes.Buffer
+func buildClassStruct(class parser.Class, options GoOptions) TemplateClass {
+	templateClass := TemplateClass{
+		Name:   class.PackageClass,
+		Parent: class.Parent,
+		Class:  class,
+	}
 
-	fileContent.WriteString(fmt.Sprintf("type %s struct {\n", class.PackageClass))
-	for _, field := range class.Fields {
-		fileContent.WriteString(buildField(field))
+	fields := class.Fields
+	// the embedded parent is a field too, named after its type
+	goNames := map[string]bool{}
+	if class.Parent != "" {
+		// inherited fields come first and are those of the embedded parent
+		fields = fields[class.ParentFields:]
+		goNames[class.Parent] = true
 	}
-	fileContent.WriteString("}\n\n")
 
-	return fileContent.String()
-}
+	fieldNameCounts := map[string]int{}
+	for _, field := range fields {
+		var comments []string
+		fieldNameCounts[field.Name]++
+		if count := fieldNameCounts[field.Name]; count > 1 {
+			slog.Warn("duplicate field name", "class", class.PackageClass, "field", field.Name, "occurrence", count)
+			comments = append(comments, fmt.Sprintf("%s is declared %d times in %s", field.Name, count, class.PackageClass))
+			field.Name = fmt.Sprintf("%s_%d", field.Name, count)
+		}
+
+		goName := uniqueGoFieldName(field.Name, goNames)
+		if goName != toTitledString(field.Name) {
+			comments = append(comments, fmt.Sprintf("%s is the %q field", goName, field.Name))
+		}
+		templateClass.Fields = append(templateClass.Fields, TemplateField{
+			Name:      goName,
+			FieldName: field.Name,
+			Type:      mapFieldTypeToGolangType(field, options),
+			Tag:       options.structTag(field.Name),
+			Comments:  comments,
+		})
+	}
 
-func buildField(field parser.GameDataField) string {
-	var fileContent bytes.Buffer
+	return templateClass
+}
 
-	if field.Type == parser.Vector {
-		fileContent.WriteString(handleVectorFieldType(field))
-	} else if field.Type < 0 {
-		fileContent.WriteString(fmt.Sprintf("%s %s `json:\"%s\"`\n", toTitledString(field.Name), mapSimpleFieldTypeToGolangType(field.Type), field.Name))
-	} else {
-		// custom type
-		fileContent.WriteString(fmt.Sprintf("// %s custom type not implemented (%s)\n", field.Name, field.Type))
-		// TODO
+// uniqueGoFieldName returns the exported Go name of a field, suffixed when the
+// struct already has a field of that name (e.g. "nameId" and "nameID"), and
+// adds it to the names of the struct.
+func uniqueGoFieldName(fieldName string, goNames map[string]bool) string {
+	goName := toGoIdentifier(fieldName)
+	if goName == "" {
+		goName = "Field"
+	} else if unicode.IsDigit(rune(goName[0])) {
+		goName = "F" + goName
 	}
 
-	return fileContent.String()
+	uniqueName := goName
+	for i := 2; goNames[uniqueName]; i++ {
+		uniqueName = fmt.Sprintf("%s_%d", goName, i)
+	}
+	goNames[uniqueName] = true
+	return uniqueName
 }
 
-func handleVectorFieldType(field parser.GameDataField) string {
-	var fileContent bytes.Buffer
-
-	if field.SubType.Type == parser.Vector {
-		// TODO
-		fileContent.WriteString(fmt.Sprintf("// %s vector subtype not implemented\n", field.Name))
-	} else if field.SubType.Type < 0 {
-		fileContent.WriteString(fmt.Sprintf("%s []%s `json:\"%s\"`\n", toTitledString(field.Name), mapSimpleFieldTypeToGolangType(field.SubType.Type), field.Name))
-	} else {
-		// TODO
-		fileContent.WriteString(fmt.Sprintf("// %s vector custom subtype not implemented (%s)\n", field.Name, field.SubType.Type))
+func mapFieldTypeToGolangType(field parser.GameDataField, options GoOptions) string {
+	switch {
+	case field.Type == parser.Vector:
+		return mapVectorFieldTypeToGolangType(field, options)
+	case field.Type == parser.Number && options.NullableNumbers:
+		return "*float64"
+	case field.Type < 0:
+		return mapSimpleFieldTypeToGolangType(field.Type)
+	default:
+		return mapCustomFieldTypeToGolangType(field, options)
 	}
+}
 
-	return fileContent.String()
+func mapVectorFieldTypeToGolangType(field parser.GameDataField, options GoOptions) string {
+	subType := *field.SubType
+	switch {
+	case subType.Type == parser.Vector:
+		return "[]" + mapVectorFieldTypeToGolangType(subType, options)
+	case subType.Type < 0:
+		return "[]" + mapSimpleFieldTypeToGolangType(subType.Type)
+	case subType.TypeName == "":
+		return "[]any"
+	case slices.Contains(options.ExtendedClasses, subType.TypeName):
+		return "[]" + subType.TypeName + "Type"
+	default:
+		return "[]" + subType.TypeName
+	}
 }
 
 func mapSimpleFieldTypeToGolangType(fieldType parser.FieldType) string {
@@ -109,6 +161,81 @@ func mapSimpleFieldTypeToGolangType(fieldType parser.FieldType) string {
 	}
 }
 
+// mapCustomFieldTypeToGolangType returns a pointer to the referenced struct, as
+// custom type objects may be null, the interface of the referenced class when
+// it is extended, or an empty interface when it is not known.
+func mapCustomFieldTypeToGolangType(field parser.GameDataField, options GoOptions) string {
+	switch {
+	case field.TypeName == "":
+		return "any"
+	case slices.Contains(options.ExtendedClasses, field.TypeName):
+		return field.TypeName + "Type"
+	default:
+		return "*" + field.TypeName
+	}
+}
+
 func toTitledString(str string) string {
 	return cases.Title(language.Und, cases.NoLower).String(str)
 }
+
+// ExtendedClassNames returns the names of the classes extended by other
+// classes, sorted.
+func ExtendedClassNames(classes []parser.Class) []string {
+	parentNames := make([]string, 0)
+	for _, class := range classes {
+		if class.Parent != "" && !slices.Contains(parentNames, class.Parent) {
+			parentNames = append(parentNames, class.Parent)
+		}
+	}
+	sort.Strings(parentNames)
+	return parentNames
+}
+
+// GenerateGoHierarchyFromClasses generates, for each class extended by other
+// classes, an interface implemented by the class and by its subclasses through
+// the embedded parent struct, so that objects of a polymorphic vector can be
+// handled as one type. See GoOptions.ExtendedClasses.
+func GenerateGoHierarchyFromClasses(classes []parser.Class, opts ...GoOptions) ([]byte, error) {
+	var fileContent bytes.Buffer
+
+	getGoOptions(opts).writePreamble(&fileContent)
+	for _, parentName := range ExtendedClassNames(classes) {
+		fileContent.WriteString(fmt.Sprintf("// %sType is implemented by %s and the classes extending it.\n", parentName, parentName))
+		fileContent.WriteString(fmt.Sprintf("type %sType interface {\nGet%s() *%s\n}\n\n", parentName, parentName, parentName))
+		fileContent.WriteString(fmt.Sprintf("func (c *%s) Get%s() *%s {\nreturn c\n}\n\n", parentName, parentName, parentName))
+	}
+
+	hierarchyGoFileContent, err := formatGolangFile(fileContent.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format file to golang: %w", err)
+	}
+
+	return hierarchyGoFileContent, nil
+}
+
+func GenerateGoRegistryFromClasses(classes []parser.Class, opts ...GoOptions) ([]byte, error) {
+	classNames := make([]string, 0, len(classes))
+	for _, class := range classes {
+		classNames = append(classNames, class.PackageClass)
+	}
+	sort.Strings(classNames)
+
+	var fileContent bytes.Buffer
+
+	getGoOptions(opts).writePreamble(&fileContent)
+	fileContent.WriteString("import \"github.com/brequet/dofus-data-file-parser/pkg/runtime\"\n\n")
+	fileContent.WriteString("// Register adds every generated type to the registry.\n")
+	fileContent.WriteString("func Register(registry *runtime.ClassRegistry) {\n")
+	for _, className := range classNames {
+		fileContent.WriteString(fmt.Sprintf("registry.Register(%q, %s{})\n", className, className))
+	}
+	fileContent.WriteString("}\n")
+
+	registryGoFileContent, err := formatGolangFile(fileContent.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format file to golang: %w", err)
+	}
+
+	return registryGoFileContent, nil
+}
@@ -0,0 +1,197 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/brequet/dofus-data-file-parser/pkg/parser"
+)
+
+// i18nNodeName is the node the I18n fields of the graph link to.
+const i18nNodeName = "I18n"
+
+// EdgeKind is the kind of a link between classes of the class graph.
+type EdgeKind int
+
+const (
+	// FieldEdge links a class to the class of one of its custom type fields.
+	FieldEdge EdgeKind = iota
+	// I18nEdge links a class to the texts, for its I18n fields.
+	I18nEdge
+	// IdEdge links a class to the class whose id one of its *Id or *Ids
+	// fields holds, as inferred from the field name.
+	IdEdge
+)
+
+// ClassGraph is the graph of the classes of modules and of their references.
+type ClassGraph struct {
+	// Modules are the module names, sorted, and ModuleClasses the names of the
+	// classes of each, a class belonging to the first module declaring it.
+	Modules       []string
+	ModuleClasses map[string][]string
+	Edges         []ClassEdge
+}
+
+// ClassEdge is a reference from the field of a class to another class, or to
+// the I18n node.
+type ClassEdge struct {
+	From  string
+	To    string
+	Field string
+	Kind  EdgeKind
+}
+
+// BuildClassGraph builds the class graph of modules given their class tables.
+func BuildClassGraph(classTables map[string]map[int]parser.Class) ClassGraph {
+	graph := ClassGraph{ModuleClasses: map[string][]string{}}
+	for moduleName := range classTables {
+		graph.Modules = append(graph.Modules, moduleName)
+	}
+	sort.Strings(graph.Modules)
+
+	classes := map[string]parser.Class{}
+	for _, moduleName := range graph.Modules {
+		classTable := classTables[moduleName]
+		classIds := make([]int, 0, len(classTable))
+		for classId := range classTable {
+			classIds = append(classIds, classId)
+		}
+		sort.Ints(classIds)
+
+		for _, classId := range classIds {
+			class := classTable[classId]
+			if _, ok := classes[class.PackageClass]; ok {
+				continue
+			}
+			classes[class.PackageClass] = class
+			graph.ModuleClasses[moduleName] = append(graph.ModuleClasses[moduleName], class.PackageClass)
+		}
+	}
+
+	// lowercased class names, for inferring the classes of id fields
+	classNames := map[string]string{}
+	for className := range classes {
+		classNames[strings.ToLower(className)] = className
+	}
+
+	for _, moduleName := range graph.Modules {
+		for _, className := range graph.ModuleClasses[moduleName] {
+			for _, field := range classes[className].Fields {
+				graph.Edges = append(graph.Edges, fieldEdges(className, field, classNames)...)
+			}
+		}
+	}
+
+	return graph
+}
+
+func fieldEdges(className string, field parser.GameDataField, classNames map[string]string) []ClassEdge {
+	leaf := field
+	for leaf.Type == parser.Vector {
+		leaf = *leaf.SubType
+	}
+
+	switch {
+	case leaf.Type > 0 && leaf.TypeName != "":
+		return []ClassEdge{{From: className, To: leaf.TypeName, Field: field.Name, Kind: FieldEdge}}
+	case leaf.Type == parser.I18n:
+		return []ClassEdge{{From: className, To: i18nNodeName, Field: field.Name, Kind: I18nEdge}}
+	case leaf.Type == parser.Integer || leaf.Type == parser.UnsignedInteger || leaf.Type == parser.Number:
+		referencedClass := inferIdFieldClass(className, field.Name, classNames)
+		if referencedClass == "" {
+			return nil
+		}
+		return []ClassEdge{{From: className, To: referencedClass, Field: field.Name, Kind: IdEdge}}
+	default:
+		return nil
+	}
+}
+
+// inferIdFieldClass returns the class whose id a field named like "typeId" or
+// "dropMonsterIds" holds: the class prefixed with the name of the declaring
+// class (ItemType for the typeId of Item), or else the longest class name the
+// field name ends with (Monster). It returns an empty string when the field is
+// not an id field or no class matches.
+func inferIdFieldClass(className, fieldName string, classNames map[string]string) string {
+	base, ok := strings.CutSuffix(fieldName, "Ids")
+	if !ok {
+		base, ok = strings.CutSuffix(fieldName, "Id")
+	}
+	if !ok || base == "" {
+		return ""
+	}
+
+	base = strings.ToLower(base)
+	if referencedClass, ok := classNames[strings.ToLower(className)+base]; ok {
+		return referencedClass
+	}
+
+	referencedClass := ""
+	for lowerName, name := range classNames {
+		if strings.HasSuffix(base, lowerName) && len(name) > len(referencedClass) {
+			referencedClass = name
+		}
+	}
+	return referencedClass
+}
+
+// GenerateDOTFromClassGraph renders the class graph in the Graphviz DOT
+// language, the classes of each module being clustered.
+func GenerateDOTFromClassGraph(graph ClassGraph) []byte {
+	var fileContent bytes.Buffer
+
+	fileContent.WriteString("digraph classes {\n")
+	fileContent.WriteString("    rankdir=LR;\n")
+	fileContent.WriteString("    node [shape=box];\n")
+	fileContent.WriteString(fmt.Sprintf("    %q [shape=note];\n", i18nNodeName))
+	for i, moduleName := range graph.Modules {
+		fileContent.WriteString(fmt.Sprintf("    subgraph cluster_%d {\n", i))
+		fileContent.WriteString(fmt.Sprintf("        label=%q;\n", moduleName))
+		for _, className := range graph.ModuleClasses[moduleName] {
+			fileContent.WriteString(fmt.Sprintf("        %q;\n", className))
+		}
+		fileContent.WriteString("    }\n")
+	}
+	for _, edge := range graph.Edges {
+		style := "solid"
+		switch edge.Kind {
+		case I18nEdge:
+			style = "dashed"
+		case IdEdge:
+			style = "dotted"
+		}
+		fileContent.WriteString(fmt.Sprintf("    %q -> %q [label=%q, style=%s];\n", edge.From, edge.To, edge.Field, style))
+	}
+	fileContent.WriteString("}\n")
+
+	return fileContent.Bytes()
+}
+
+// GenerateMermaidFromClassGraph renders the class graph as a Mermaid
+// flowchart, the classes of each module being grouped in a subgraph.
+func GenerateMermaidFromClassGraph(graph ClassGraph) []byte {
+	var fileContent bytes.Buffer
+
+	fileContent.WriteString("flowchart LR\n")
+	fileContent.WriteString(fmt.Sprintf("    %s[/%s/]\n", i18nNodeName, i18nNodeName))
+	for _, moduleName := range graph.Modules {
+		// module and class names may be equal, which Mermaid does not allow
+		fileContent.WriteString(fmt.Sprintf("    subgraph module_%s [%s]\n", moduleName, moduleName))
+		for _, className := range graph.ModuleClasses[moduleName] {
+			fileContent.WriteString(fmt.Sprintf("        %s\n", className))
+		}
+		fileContent.WriteString("    end\n")
+	}
+	for _, edge := range graph.Edges {
+		arrow := "-->"
+		switch edge.Kind {
+		case I18nEdge, IdEdge:
+			arrow = "-.->"
+		}
+		fileContent.WriteString(fmt.Sprintf("    %s %s|%s| %s\n", edge.From, arrow, edge.Field, edge.To))
+	}
+
+	return fileContent.Bytes()
+}
@@ -0,0 +1,121 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/brequet/dofus-data-file-parser/pkg/parser"
+)
+
+const jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// GenerateJSONSchemaFromClasses generates the JSON Schema of the JSON exported
+// for a module, given the class table of the module.
+func GenerateJSONSchemaFromClasses(moduleName string, classTable map[int]parser.Class) ([]byte, error) {
+	classIds := make([]int, 0, len(classTable))
+	for classId := range classTable {
+		classIds = append(classIds, classId)
+	}
+	sort.Ints(classIds)
+
+	definitions := map[string]any{}
+	objectSchemas := make([]any, 0, len(classIds))
+	for _, classId := range classIds {
+		class := classTable[classId]
+		definitions[class.PackageClass] = buildClassJSONSchema(class)
+		objectSchemas = append(objectSchemas, classJSONSchemaRef(class.PackageClass))
+	}
+
+	schema := map[string]any{
+		"$schema": jsonSchemaDialect,
+		"title":   moduleName,
+		"type":    "object",
+		"properties": map[string]any{
+			"classes": map[string]any{
+				"type": "object",
+				"additionalProperties": map[string]any{
+					"type": "object",
+				},
+			},
+			"objects": map[string]any{
+				"type": "array",
+				"items": map[string]any{
+					"anyOf": objectSchemas,
+				},
+			},
+		},
+		"required": []string{"classes", "objects"},
+		"$defs":    definitions,
+	}
+
+	jsonStr, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling schema: %w", err)
+	}
+
+	return jsonStr, nil
+}
+
+func buildClassJSONSchema(class parser.Class) map[string]any {
+	properties := map[string]any{
+		// not a const, objects of subclasses are valid against their parent schema
+		"ClassType_": map[string]any{"type": "string"},
+		// only set on the objects of the module, not on nested objects
+		"ObjectId_": map[string]any{"type": "integer"},
+	}
+	required := []string{"ClassType_"}
+	for _, field := range class.Fields {
+		properties[field.Name] = mapFieldTypeToJSONSchema(field, false)
+		required = append(required, field.Name)
+	}
+
+	return map[string]any{
+		"type":       "object",
+		"properties": properties,
+		"required":   required,
+	}
+}
+
+func mapFieldTypeToJSONSchema(field parser.GameDataField, vectorElement bool) map[string]any {
+	if isNullable(field, vectorElement) {
+		return map[string]any{
+			"oneOf": []any{mapNonNullFieldTypeToJSONSchema(field), map[string]any{"type": "null"}},
+		}
+	}
+	return mapNonNullFieldTypeToJSONSchema(field)
+}
+
+func mapNonNullFieldTypeToJSONSchema(field parser.GameDataField) map[string]any {
+	switch {
+	case field.Type == parser.Vector:
+		return map[string]any{
+			"type":  "array",
+			"items": mapFieldTypeToJSONSchema(*field.SubType, true),
+		}
+	case field.Type > 0:
+		if field.TypeName == "" {
+			return map[string]any{}
+		}
+		return classJSONSchemaRef(field.TypeName)
+	}
+
+	switch field.Type {
+	case parser.Integer, parser.I18n:
+		return map[string]any{"type": "integer"}
+	case parser.UnsignedInteger:
+		return map[string]any{"type": "integer", "minimum": 0}
+	case parser.Boolean:
+		return map[string]any{"type": "boolean"}
+	case parser.String:
+		return map[string]any{"type": "string"}
+	case parser.Number:
+		return map[string]any{"type": "number"}
+	default:
+		return map[string]any{}
+	}
+}
+
+func classJSONSchemaRef(className string) map[string]any {
+	return map[string]any{"$ref": "#/$defs/" + className}
+}
@@ -0,0 +1,96 @@
+package generator
+
+import (
+	"unicode"
+
+	"github.com/brequet/dofus-data-file-parser/pkg/parser"
+)
+
+// kotlinPackageName is the package of the generated Kotlin files.
+const kotlinPackageName = "types"
+
+var kotlinKeywords = map[string]bool{
+	"as": true, "break": true, "class": true, "continue": true, "do": true,
+	"else": true, "false": true, "for": true, "fun": true, "if": true, "in": true,
+	"interface": true, "is": true, "null": true, "object": true, "package": true,
+	"return": true, "super": true, "this": true, "throw": true, "true": true,
+	"try": true, "typealias": true, "typeof": true, "val": true, "var": true,
+	"when": true, "while": true,
+}
+
+// GenerateKotlinFromClasses generates kotlinx.serialization data classes.
+// Subclasses are not generated as such, data classes being final: they hold
+// the fields of their parent too.
+func GenerateKotlinFromClasses(classes []parser.Class, templates ...Templates) ([]byte, error) {
+	data := TemplateData{
+		PackageName: kotlinPackageName,
+		Classes:     make([]TemplateClass, 0, len(classes)),
+	}
+	for _, class := range classes {
+		templateClass := TemplateClass{Name: class.PackageClass, Class: class}
+		for _, field := range class.Fields {
+			templateClass.Fields = append(templateClass.Fields, TemplateField{
+				Name:      toKotlinIdentifier(field.Name),
+				FieldName: field.Name,
+				Type:      mapFieldTypeToKotlinType(field, false),
+			})
+		}
+		data.Classes = append(data.Classes, templateClass)
+	}
+
+	return getTemplates(templates).execute("kotlin.tmpl", data)
+}
+
+func mapFieldTypeToKotlinType(field parser.GameDataField, vectorElement bool) string {
+	if isNullable(field, vectorElement) {
+		return mapNonNullFieldTypeToKotlinType(field) + "?"
+	}
+	return mapNonNullFieldTypeToKotlinType(field)
+}
+
+func mapNonNullFieldTypeToKotlinType(field parser.GameDataField) string {
+	switch {
+	case field.Type == parser.Vector:
+		return "List<" + mapFieldTypeToKotlinType(*field.SubType, true) + ">"
+	case field.Type > 0:
+		if field.TypeName == "" {
+			return "JsonElement?"
+		}
+		return field.TypeName
+	}
+
+	switch field.Type {
+	case parser.Integer, parser.I18n:
+		return "Int"
+	case parser.UnsignedInteger:
+		return "Long"
+	case parser.Boolean:
+		return "Boolean"
+	case parser.String:
+		return "String"
+	case parser.Number:
+		return "Double"
+	default:
+		return "JsonElement?"
+	}
+}
+
+// toKotlinIdentifier quotes the field names which are keywords or are not
+// valid identifiers.
+func toKotlinIdentifier(name string) string {
+	if kotlinKeywords[name] || !isIdentifier(name) {
+		return "`" + name + "`"
+	}
+	return name
+}
+
+// isIdentifier tells whether a name is made of letters, digits and
+// underscores, and does not start with a digit.
+func isIdentifier(name string) bool {
+	for i, r := range name {
+		if !unicode.IsLetter(r) && r != '_' && (i == 0 || !unicode.IsDigit(r)) {
+			return false
+		}
+	}
+	return name != ""
+}
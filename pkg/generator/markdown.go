@@ -0,0 +1,137 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/brequet/dofus-data-file-parser/pkg/parser"
+)
+
+// GenerateMarkdownFromClasses generates the documentation page of a module,
+// given its class table: one section per class, the class of the objects of
+// the module first, with its fields and the classes they reference.
+func GenerateMarkdownFromClasses(moduleName string, classTable map[int]parser.Class) ([]byte, error) {
+	classIds := make([]int, 0, len(classTable))
+	for classId := range classTable {
+		classIds = append(classIds, classId)
+	}
+	sort.Ints(classIds)
+
+	var fileContent bytes.Buffer
+
+	fileContent.WriteString(fmt.Sprintf("# %s\n\n", moduleName))
+	fileContent.WriteString("Classes:\n\n")
+	for _, classId := range classIds {
+		class := classTable[classId]
+		fileContent.WriteString(fmt.Sprintf("- %s (id %d)\n", markdownClassLink(class.PackageClass), classId))
+	}
+
+	for _, classId := range classIds {
+		fileContent.WriteString("\n")
+		fileContent.WriteString(buildMarkdownClassSection(classTable[classId]))
+	}
+
+	return fileContent.Bytes(), nil
+}
+
+func buildMarkdownClassSection(class parser.Class) string {
+	var fileContent bytes.Buffer
+
+	fileContent.WriteString(fmt.Sprintf("## %s\n\n", class.PackageClass))
+	fileContent.WriteString(fmt.Sprintf("Package: `%s`\n\n", class.PackageName))
+	if class.Parent != "" {
+		fileContent.WriteString(fmt.Sprintf("Extends %s, whose fields are the first %d.\n\n", markdownClassLink(class.Parent), class.ParentFields))
+	}
+
+	if len(class.Fields) == 0 {
+		fileContent.WriteString("No fields.\n")
+		return fileContent.String()
+	}
+
+	referencedClasses := make([]string, 0)
+	fileContent.WriteString("| Field | Type |\n")
+	fileContent.WriteString("| --- | --- |\n")
+	for _, field := range class.Fields {
+		fileContent.WriteString(fmt.Sprintf("| `%s` | %s |\n", field.Name, mapFieldTypeToMarkdownType(field)))
+		for _, className := range referencedClassNames(field) {
+			if !slices.Contains(referencedClasses, className) {
+				referencedClasses = append(referencedClasses, className)
+			}
+		}
+	}
+
+	if len(referencedClasses) > 0 {
+		sort.Strings(referencedClasses)
+		links := make([]string, 0, len(referencedClasses))
+		for _, className := range referencedClasses {
+			links = append(links, markdownClassLink(className))
+		}
+		fileContent.WriteString(fmt.Sprintf("\nReferences: %s\n", strings.Join(links, ", ")))
+	}
+
+	return fileContent.String()
+}
+
+func mapFieldTypeToMarkdownType(field parser.GameDataField) string {
+	switch {
+	case field.Type == parser.Vector:
+		return fmt.Sprintf("vector&lt;%s&gt;", mapFieldTypeToMarkdownType(*field.SubType))
+	case field.Type > 0:
+		if field.TypeName == "" {
+			return fmt.Sprintf("class %d", field.Type)
+		}
+		return markdownClassLink(field.TypeName)
+	}
+
+	switch field.Type {
+	case parser.Integer:
+		return "int"
+	case parser.Boolean:
+		return "bool"
+	case parser.String:
+		return "string"
+	case parser.Number:
+		return "number"
+	case parser.I18n:
+		return "i18n text id"
+	case parser.UnsignedInteger:
+		return "uint"
+	default:
+		return "unknown"
+	}
+}
+
+// referencedClassNames returns the names of the classes a field holds, through
+// nested vectors.
+func referencedClassNames(field parser.GameDataField) []string {
+	switch {
+	case field.Type == parser.Vector:
+		return referencedClassNames(*field.SubType)
+	case field.Type > 0 && field.TypeName != "":
+		return []string{field.TypeName}
+	default:
+		return nil
+	}
+}
+
+// markdownClassLink links to the section of a class, anchors being the
+// lowercased class names.
+func markdownClassLink(className string) string {
+	return fmt.Sprintf("[%s](#%s)", className, strings.ToLower(className))
+}
+
+// GenerateMarkdownIndex generates the index page of the documentation,
+// linking the page of each module.
+func GenerateMarkdownIndex(moduleNames []string) []byte {
+	var fileContent bytes.Buffer
+
+	fileContent.WriteString("# Game data modules\n\n")
+	for _, moduleName := range moduleNames {
+		fileContent.WriteString(fmt.Sprintf("- [%s](%s.md)\n", moduleName, moduleName))
+	}
+
+	return fileContent.Bytes()
+}
@@ -0,0 +1,68 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/brequet/dofus-data-file-parser/pkg/parser"
+)
+
+func GenerateMermaidERDiagramFromClasses(classes []parser.Class, classTable map[int]parser.Class) ([]byte, error) {
+	var fileContent bytes.Buffer
+	var relationships bytes.Buffer
+
+	fileContent.WriteString("erDiagram\n")
+	for _, class := range classes {
+		fileContent.WriteString(fmt.Sprintf("    %s {\n", class.PackageClass))
+		for _, field := range class.Fields {
+			attributeType, referencedType, isVector := mapFieldTypeToMermaidType(field)
+			if referencedType <= 0 {
+				fileContent.WriteString(fmt.Sprintf("        %s %s\n", attributeType, field.Name))
+				continue
+			}
+
+			referencedClass, ok := classTable[int(referencedType)]
+			if !ok {
+				return nil, fmt.Errorf("unknown class id %d referenced by %s.%s", referencedType, class.PackageClass, field.Name)
+			}
+			cardinality := "||--o|"
+			if isVector {
+				cardinality = "||--o{"
+			}
+			relationships.WriteString(fmt.Sprintf("    %s %s %s : \"%s\"\n", class.PackageClass, cardinality, referencedClass.PackageClass, field.Name))
+		}
+		fileContent.WriteString("    }\n")
+	}
+	fileContent.Write(relationships.Bytes())
+
+	return fileContent.Bytes(), nil
+}
+
+// mapFieldTypeToMermaidType returns the attribute type of a scalar field, or the
+// referenced class id (and whether it is held in a vector) for custom types.
+func mapFieldTypeToMermaidType(field parser.GameDataField) (string, parser.FieldType, bool) {
+	if field.Type == parser.Vector {
+		attributeType, referencedType, _ := mapFieldTypeToMermaidType(*field.SubType)
+		return attributeType + "[]", referencedType, true
+	}
+	if field.Type > 0 {
+		return "", field.Type, false
+	}
+
+	switch field.Type {
+	case parser.Integer:
+		return "int", 0, false
+	case parser.Boolean:
+		return "bool", 0, false
+	case parser.String:
+		return "string", 0, false
+	case parser.Number:
+		return "float", 0, false
+	case parser.I18n:
+		return "i18n", 0, false
+	case parser.UnsignedInteger:
+		return "uint", 0, false
+	default:
+		return "unknown", 0, false
+	}
+}
@@ -0,0 +1,22 @@
+package generator
+
+import (
+	"github.com/brequet/dofus-data-file-parser/pkg/parser"
+)
+
+// isNullable tells whether the values of a field may be exported as null, for
+// the backends to make its type optional: custom type objects, vector elements
+// included, are null when their class id is the null one, and numbers are null
+// when NaN, except in vectors where they are kept as is. Objects of an unknown
+// class are mapped to the catch-all type of each language, which holds null
+// already.
+func isNullable(field parser.GameDataField, vectorElement bool) bool {
+	switch {
+	case field.Type == parser.Number:
+		return !vectorElement
+	case field.Type > 0:
+		return field.TypeName != ""
+	default:
+		return false
+	}
+}
@@ -0,0 +1,141 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// GoOptions configures the Go generation functions, which take them as an
+// optional last argument.
+type GoOptions struct {
+	// PackageName is the package of the generated files, "types" when empty.
+	PackageName string
+	// BuildTags is the build constraint of the generated files, e.g.
+	// "dofus && !retro", none when empty.
+	BuildTags string
+	// Header is a comment written at the top of the generated files, such as
+	// a licence or the game version the files were generated from. It may
+	// span several lines.
+	Header string
+	// NullableNumbers generates Number fields as *float64 rather than float64,
+	// for the NaN values the parser reads as null to stay null once decoded.
+	// Numbers of vectors are never null.
+	NullableNumbers bool
+	// TagCase is the casing of the names of the struct tags: the field names as
+	// they are when empty, "camel" or "snake".
+	TagCase string
+	// OmitEmpty adds the omitempty option to the struct tags.
+	OmitEmpty bool
+	// ExtraTags are struct tags written along with the json one, with the same
+	// name, e.g. "db", "bson" or "msgpack".
+	ExtraTags []string
+	// ExtendedClasses are the classes whose fields and vector elements are
+	// generated as the <Class>Type interface of GenerateGoHierarchyFromClasses
+	// rather than as the class struct, for the objects of their subclasses to
+	// keep their own fields, e.g. the dice of an EffectInstanceDice. Such
+	// fields are filled by parser.DecodeInto given the registry of
+	// GenerateGoRegistryFromClasses. See ExtendedClassNames.
+	ExtendedClasses []string
+	// Templates are the templates the class files are generated with.
+	Templates Templates
+}
+
+// getGoOptions returns the options given to a generation function, if any.
+func getGoOptions(opts []GoOptions) GoOptions {
+	if len(opts) == 0 {
+		return GoOptions{}
+	}
+	return opts[0]
+}
+
+func (o GoOptions) packageName() string {
+	if o.PackageName == "" {
+		return "types"
+	}
+	return o.PackageName
+}
+
+// headerLines returns the lines of the header, none when it is empty.
+func (o GoOptions) headerLines() []string {
+	if o.Header == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimRight(o.Header, "\n"), "\n")
+}
+
+// writePreamble writes the header, build constraint and package clause of a
+// generated file.
+func (o GoOptions) writePreamble(fileContent *bytes.Buffer) {
+	if o.Header != "" {
+		for _, line := range o.headerLines() {
+			fileContent.WriteString(strings.TrimRight("// "+line, " ") + "\n")
+		}
+		// keeps the header from being the package documentation
+		fileContent.WriteString("\n")
+	}
+	if o.BuildTags != "" {
+		fileContent.WriteString(fmt.Sprintf("//go:build %s\n\n", o.BuildTags))
+	}
+	fileContent.WriteString(fmt.Sprintf("package %s\n\n", o.packageName()))
+}
+
+// structTag returns the struct tag of a field.
+func (o GoOptions) structTag(fieldName string) string {
+	name := fieldName
+	switch o.TagCase {
+	case "camel":
+		name = toCamelCase(fieldName)
+	case "snake":
+		name = toSnakeCase(fieldName)
+	}
+	if o.OmitEmpty {
+		name += ",omitempty"
+	}
+
+	tags := make([]string, 0, 1+len(o.ExtraTags))
+	for _, key := range append([]string{"json"}, o.ExtraTags...) {
+		tags = append(tags, fmt.Sprintf("%s:%q", key, name))
+	}
+	return "`" + strings.Join(tags, " ") + "`"
+}
+
+// toCamelCase turns names like "name_id" or "NameId" into "nameId".
+func toCamelCase(name string) string {
+	var camelName strings.Builder
+	upperNext := false
+	for i, r := range name {
+		switch {
+		case r == '_' || r == '-' || r == ' ':
+			upperNext = camelName.Len() > 0
+		case i == 0:
+			camelName.WriteRune(unicode.ToLower(r))
+		case upperNext:
+			camelName.WriteRune(unicode.ToUpper(r))
+			upperNext = false
+		default:
+			camelName.WriteRune(r)
+		}
+	}
+	return camelName.String()
+}
+
+// toSnakeCase turns names like "nameId" or "NameID" into "name_id".
+func toSnakeCase(name string) string {
+	runes := []rune(name)
+	var snakeName strings.Builder
+	for i, r := range runes {
+		if r == '-' || r == ' ' {
+			r = '_'
+		}
+		// a word starts at an uppercase letter following a lowercase one, or
+		// preceding one in acronyms ("ID" of "NPCId")
+		if unicode.IsUpper(r) && i > 0 && runes[i-1] != '_' &&
+			(!unicode.IsUpper(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+			snakeName.WriteRune('_')
+		}
+		snakeName.WriteRune(unicode.ToLower(r))
+	}
+	return snakeName.String()
+}
@@ -0,0 +1,87 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/brequet/dofus-data-file-parser/pkg/parser"
+)
+
+// GeneratePydanticFromClasses generates Pydantic models validating the JSON
+// export, in a single module as models of different packages reference each
+// other. Subclasses extend the model of their parent, and the extra keys of
+// the export, such as ClassType_, are kept.
+func GeneratePydanticFromClasses(classes []parser.Class, templates ...Templates) ([]byte, error) {
+	data := TemplateData{Classes: make([]TemplateClass, 0, len(classes))}
+	classNames := map[string]bool{}
+	for _, class := range classes {
+		classNames[class.PackageClass] = true
+	}
+
+	for _, class := range parentsFirst(classes) {
+		templateClass := TemplateClass{Name: class.PackageClass, Class: class}
+		fields := class.Fields
+		if classNames[class.Parent] {
+			templateClass.Parent = class.Parent
+			fields = fields[class.ParentFields:]
+		}
+		for _, field := range fields {
+			templateField := TemplateField{
+				Name:      toPythonIdentifier(field.Name),
+				FieldName: field.Name,
+				Type:      mapFieldTypeToPydanticType(field, false),
+			}
+			// keywords, invalid names and names Pydantic considers private are
+			// aliased
+			if strings.HasPrefix(field.Name, "_") || !isIdentifier(field.Name) {
+				templateField.Name = "field_" + toGoIdentifier(field.Name)
+			}
+			if templateField.Name != field.Name {
+				templateField.Tag = fmt.Sprintf("Field(alias=%q)", field.Name)
+			}
+			templateClass.Fields = append(templateClass.Fields, templateField)
+		}
+		data.Classes = append(data.Classes, templateClass)
+	}
+
+	return getTemplates(templates).execute("pydantic.tmpl", data)
+}
+
+func mapFieldTypeToPydanticType(field parser.GameDataField, vectorElement bool) string {
+	switch field.Type {
+	case parser.Vector:
+		return fmt.Sprintf("List[%s]", mapFieldTypeToPydanticType(*field.SubType, true))
+	case parser.I18n:
+		// texts are exported as ids, the translations being extra keys
+		return "int"
+	default:
+		return mapFieldTypeToPythonType(field, vectorElement)
+	}
+}
+
+// parentsFirst orders classes so that parents come before the classes
+// extending them, keeping the order of the classes otherwise.
+func parentsFirst(classes []parser.Class) []parser.Class {
+	classesByName := make(map[string]parser.Class, len(classes))
+	for _, class := range classes {
+		classesByName[class.PackageClass] = class
+	}
+
+	ordered := make([]parser.Class, 0, len(classes))
+	added := map[string]bool{}
+	var add func(class parser.Class)
+	add = func(class parser.Class) {
+		if added[class.PackageClass] {
+			return
+		}
+		added[class.PackageClass] = true
+		if parent, ok := classesByName[class.Parent]; ok {
+			add(parent)
+		}
+		ordered = append(ordered, class)
+	}
+	for _, class := range classes {
+		add(class)
+	}
+	return ordered
+}
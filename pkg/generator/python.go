@@ -0,0 +1,73 @@
+package generator
+
+import (
+	"fmt"
+
+	"github.com/brequet/dofus-data-file-parser/pkg/parser"
+)
+
+var pythonKeywords = map[string]bool{
+	"False": true, "None": true, "True": true, "and": true, "as": true, "assert": true,
+	"async": true, "await": true, "break": true, "class": true, "continue": true,
+	"def": true, "del": true, "elif": true, "else": true, "except": true,
+	"finally": true, "for": true, "from": true, "global": true, "if": true,
+	"import": true, "in": true, "is": true, "lambda": true, "nonlocal": true,
+	"not": true, "or": true, "pass": true, "raise": true, "return": true,
+	"try": true, "while": true, "with": true, "yield": true,
+}
+
+func GeneratePythonDataclassesFromClasses(classes []parser.Class, templates ...Templates) ([]byte, error) {
+	data := TemplateData{Classes: make([]TemplateClass, 0, len(classes))}
+	for _, class := range classes {
+		templateClass := TemplateClass{Name: class.PackageClass, Class: class}
+		for _, field := range class.Fields {
+			templateClass.Fields = append(templateClass.Fields, TemplateField{
+				Name:      toPythonIdentifier(field.Name),
+				FieldName: field.Name,
+				Type:      mapFieldTypeToPythonType(field, false),
+			})
+		}
+		data.Classes = append(data.Classes, templateClass)
+	}
+
+	return getTemplates(templates).execute("python.tmpl", data)
+}
+
+func mapFieldTypeToPythonType(field parser.GameDataField, vectorElement bool) string {
+	if isNullable(field, vectorElement) {
+		return fmt.Sprintf("Optional[%s]", mapNonNullFieldTypeToPythonType(field))
+	}
+	return mapNonNullFieldTypeToPythonType(field)
+}
+
+func mapNonNullFieldTypeToPythonType(field parser.GameDataField) string {
+	switch {
+	case field.Type == parser.Vector:
+		return fmt.Sprintf("List[%s]", mapFieldTypeToPythonType(*field.SubType, true))
+	case field.Type > 0:
+		if field.TypeName == "" {
+			return "Any"
+		}
+		return field.TypeName
+	}
+
+	switch field.Type {
+	case parser.Integer, parser.UnsignedInteger:
+		return "int"
+	case parser.Boolean:
+		return "bool"
+	case parser.String, parser.I18n:
+		return "str"
+	case parser.Number:
+		return "float"
+	default:
+		return "Any"
+	}
+}
+
+func toPythonIdentifier(name string) string {
+	if pythonKeywords[name] {
+		return name + "_"
+	}
+	return name
+}
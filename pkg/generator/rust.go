@@ -0,0 +1,130 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/brequet/dofus-data-file-parser/pkg/parser"
+)
+
+var rustKeywords = map[string]bool{
+	"as": true, "async": true, "await": true, "break": true, "const": true,
+	"continue": true, "dyn": true, "else": true, "enum": true, "extern": true,
+	"false": true, "fn": true, "for": true, "if": true, "impl": true, "in": true,
+	"let": true, "loop": true, "match": true, "mod": true, "move": true,
+	"mut": true, "pub": true, "ref": true, "return": true, "static": true,
+	"struct": true, "trait": true, "true": true, "type": true, "unsafe": true,
+	"use": true, "where": true, "while": true, "abstract": true, "become": true,
+	"box": true, "do": true, "final": true, "macro": true, "override": true,
+	"priv": true, "try": true, "typeof": true, "unsized": true, "virtual": true,
+	"yield": true,
+}
+
+// GenerateRustFromClasses generates serde structs, meant to be written to a
+// module of a folder whose mod.rs is generated by GenerateRustModFromModules.
+// Subclasses hold the fields of their parent too.
+func GenerateRustFromClasses(classes []parser.Class, templates ...Templates) ([]byte, error) {
+	data := TemplateData{Classes: make([]TemplateClass, 0, len(classes))}
+	for _, class := range classes {
+		templateClass := TemplateClass{Name: class.PackageClass, Class: class}
+		rustNames := map[string]bool{}
+		for _, field := range class.Fields {
+			rustName := uniqueRustFieldName(field.Name, rustNames)
+			templateField := TemplateField{
+				Name:      rustName,
+				FieldName: field.Name,
+				Type:      mapFieldTypeToRustType(field, false),
+			}
+			if strings.TrimPrefix(rustName, "r#") != field.Name {
+				templateField.Tag = fmt.Sprintf("#[serde(rename = %q)]", field.Name)
+			}
+			templateClass.Fields = append(templateClass.Fields, templateField)
+		}
+		data.Classes = append(data.Classes, templateClass)
+	}
+
+	return getTemplates(templates).execute("rust.tmpl", data)
+}
+
+// GenerateRustModFromModules generates the mod.rs of the generated modules,
+// re-exporting their structs.
+func GenerateRustModFromModules(moduleNames []string) []byte {
+	moduleNames = append([]string(nil), moduleNames...)
+	sort.Strings(moduleNames)
+
+	var fileContent bytes.Buffer
+	for _, moduleName := range moduleNames {
+		fileContent.WriteString(fmt.Sprintf("pub mod %s;\n", moduleName))
+	}
+	fileContent.WriteString("\n")
+	for _, moduleName := range moduleNames {
+		fileContent.WriteString(fmt.Sprintf("pub use %s::*;\n", moduleName))
+	}
+
+	return fileContent.Bytes()
+}
+
+func mapFieldTypeToRustType(field parser.GameDataField, vectorElement bool) string {
+	if isNullable(field, vectorElement) {
+		return "Option<" + mapNonNullFieldTypeToRustType(field) + ">"
+	}
+	return mapNonNullFieldTypeToRustType(field)
+}
+
+func mapNonNullFieldTypeToRustType(field parser.GameDataField) string {
+	switch {
+	case field.Type == parser.Vector:
+		return "Vec<" + mapFieldTypeToRustType(*field.SubType, true) + ">"
+	case field.Type > 0:
+		if field.TypeName == "" {
+			return "serde_json::Value"
+		}
+		// boxed for classes to be able to hold themselves
+		return "Box<" + field.TypeName + ">"
+	}
+
+	switch field.Type {
+	case parser.Integer, parser.I18n:
+		return "i32"
+	case parser.UnsignedInteger:
+		return "u32"
+	case parser.Boolean:
+		return "bool"
+	case parser.String:
+		return "String"
+	case parser.Number:
+		return "f64"
+	default:
+		return "serde_json::Value"
+	}
+}
+
+// uniqueRustFieldName returns the snake case name of a field, as a raw
+// identifier for keywords, suffixed when the struct already has a field of
+// that name, and adds it to the names of the struct.
+func uniqueRustFieldName(fieldName string, rustNames map[string]bool) string {
+	rustName := strings.Join(strings.FieldsFunc(toSnakeCase(fieldName), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+	}), "_")
+	if rustName == "" || !isIdentifier(rustName) {
+		rustName = "field_" + rustName
+	}
+
+	uniqueName := rustName
+	for i := 2; rustNames[uniqueName]; i++ {
+		uniqueName = fmt.Sprintf("%s_%d", rustName, i)
+	}
+	rustNames[uniqueName] = true
+
+	switch {
+	case uniqueName == "self" || uniqueName == "super" || uniqueName == "crate":
+		return uniqueName + "_"
+	case rustKeywords[uniqueName]:
+		return "r#" + uniqueName
+	default:
+		return uniqueName
+	}
+}
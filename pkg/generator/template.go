@@ -0,0 +1,119 @@
+package generator
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/brequet/dofus-data-file-parser/pkg/parser"
+)
+
+//go:embed templates/*.tmpl
+var defaultTemplates embed.FS
+
+// Templates are the text/templates class files are generated with, one per
+// language: go.tmpl, ts.tmpl, python.tmpl, kotlin.tmpl... The templates of Dir
+// replace the default ones, which are used for the files missing from it or
+// when it is empty.
+//
+// Templates are executed with a TemplateData. Besides the text/template
+// builtins, they may call join, lower, upper, trimPrefix and trimSuffix from
+// the strings package.
+type Templates struct {
+	Dir string
+}
+
+// TemplateData is the data a template generates a file from.
+type TemplateData struct {
+	// Header holds the lines of the file header, Go only.
+	Header []string
+	// BuildTags is the build constraint of the file, Go only.
+	BuildTags string
+	// PackageName is the package of the file, for Go and Kotlin, or its
+	// namespace in C#.
+	PackageName string
+	Classes     []TemplateClass
+}
+
+// TemplateClass is a class as generated in a language.
+type TemplateClass struct {
+	Name string
+	// Parent is the name of the parent class, embedded in Go structs, whose
+	// fields are then left out of Fields.
+	Parent string
+	Fields []TemplateField
+	// Class is the class definition read from the D2O file.
+	Class parser.Class
+}
+
+// TemplateField is a field as generated in a language.
+type TemplateField struct {
+	// Name is the identifier of the field, which may differ from the name of
+	// the D2O field (FieldName) to be valid.
+	Name      string
+	FieldName string
+	Type      string
+	// Tag is the struct tag of the field in Go, its alias for Pydantic and
+	// its attribute in Rust and C#.
+	Tag string
+	// Default is the initial value of the property, C# only.
+	Default string
+	// Comments are the lines of the comment of the field, Go only.
+	Comments []string
+}
+
+var templateFuncs = template.FuncMap{
+	"join":       strings.Join,
+	"lower":      strings.ToLower,
+	"upper":      strings.ToUpper,
+	"trimPrefix": strings.TrimPrefix,
+	"trimSuffix": strings.TrimSuffix,
+}
+
+// getTemplates returns the templates given to a generation function, if any.
+func getTemplates(templates []Templates) Templates {
+	if len(templates) == 0 {
+		return Templates{}
+	}
+	return templates[0]
+}
+
+// execute generates a file with the template of the given name.
+func (t Templates) execute(name string, data TemplateData) ([]byte, error) {
+	content, err := t.read(name)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl, err := template.New(name).Funcs(templateFuncs).Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing template %s: %w", name, err)
+	}
+
+	var fileContent bytes.Buffer
+	err = tmpl.Execute(&fileContent, data)
+	if err != nil {
+		return nil, fmt.Errorf("error executing template %s: %w", name, err)
+	}
+	return fileContent.Bytes(), nil
+}
+
+func (t Templates) read(name string) ([]byte, error) {
+	if t.Dir != "" {
+		content, err := os.ReadFile(filepath.Join(t.Dir, name))
+		if err == nil {
+			return content, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("error reading template %s: %w", name, err)
+		}
+	}
+
+	return defaultTemplates.ReadFile("templates/" + name)
+}
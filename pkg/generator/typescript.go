@@ -0,0 +1,57 @@
+package generator
+
+import (
+	"github.com/brequet/dofus-data-file-parser/pkg/parser"
+)
+
+// GenerateTypeScriptFromClasses generates global interface declarations, meant
+// to be written to a .d.ts file.
+func GenerateTypeScriptFromClasses(classes []parser.Class, templates ...Templates) ([]byte, error) {
+	data := TemplateData{Classes: make([]TemplateClass, 0, len(classes))}
+	for _, class := range classes {
+		templateClass := TemplateClass{Name: class.PackageClass, Class: class}
+		for _, field := range class.Fields {
+			templateClass.Fields = append(templateClass.Fields, TemplateField{
+				Name:      field.Name,
+				FieldName: field.Name,
+				Type:      mapFieldTypeToTypeScriptType(field, false),
+			})
+		}
+		data.Classes = append(data.Classes, templateClass)
+	}
+
+	return getTemplates(templates).execute("ts.tmpl", data)
+}
+
+func mapFieldTypeToTypeScriptType(field parser.GameDataField, vectorElement bool) string {
+	if !isNullable(field, vectorElement) {
+		return mapNonNullFieldTypeToTypeScriptType(field)
+	}
+	if vectorElement {
+		return "(" + mapNonNullFieldTypeToTypeScriptType(field) + " | null)"
+	}
+	return mapNonNullFieldTypeToTypeScriptType(field) + " | null"
+}
+
+func mapNonNullFieldTypeToTypeScriptType(field parser.GameDataField) string {
+	switch {
+	case field.Type == parser.Vector:
+		return mapFieldTypeToTypeScriptType(*field.SubType, true) + "[]"
+	case field.Type > 0:
+		if field.TypeName == "" {
+			return "unknown"
+		}
+		return field.TypeName
+	}
+
+	switch field.Type {
+	case parser.Integer, parser.UnsignedInteger, parser.Number, parser.I18n:
+		return "number"
+	case parser.Boolean:
+		return "boolean"
+	case parser.String:
+		return "string"
+	default:
+		return "unknown"
+	}
+}
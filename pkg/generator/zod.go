@@ -0,0 +1,67 @@
+package generator
+
+import (
+	"strconv"
+
+	"github.com/brequet/dofus-data-file-parser/pkg/parser"
+)
+
+// GenerateZodFromClasses generates Zod schemas validating the objects of the
+// JSON export, in a single module as schemas of different packages reference
+// each other. Schemas are lazy for classes to be able to reference each
+// other in any order, and keep the extra keys of the export, such as
+// ClassType_.
+func GenerateZodFromClasses(classes []parser.Class, templates ...Templates) ([]byte, error) {
+	data := TemplateData{Classes: make([]TemplateClass, 0, len(classes))}
+	for _, class := range classes {
+		templateClass := TemplateClass{Name: class.PackageClass, Class: class}
+		for _, field := range class.Fields {
+			name := field.Name
+			if !isIdentifier(name) {
+				name = strconv.Quote(name)
+			}
+			templateClass.Fields = append(templateClass.Fields, TemplateField{
+				Name:      name,
+				FieldName: field.Name,
+				Type:      mapFieldTypeToZodSchema(field, false),
+			})
+		}
+		data.Classes = append(data.Classes, templateClass)
+	}
+
+	return getTemplates(templates).execute("zod.tmpl", data)
+}
+
+func mapFieldTypeToZodSchema(field parser.GameDataField, vectorElement bool) string {
+	if isNullable(field, vectorElement) {
+		return mapNonNullFieldTypeToZodSchema(field) + ".nullable()"
+	}
+	return mapNonNullFieldTypeToZodSchema(field)
+}
+
+func mapNonNullFieldTypeToZodSchema(field parser.GameDataField) string {
+	switch {
+	case field.Type == parser.Vector:
+		return "z.array(" + mapFieldTypeToZodSchema(*field.SubType, true) + ")"
+	case field.Type > 0:
+		if field.TypeName == "" {
+			return "z.unknown()"
+		}
+		return field.TypeName + "Schema"
+	}
+
+	switch field.Type {
+	case parser.Integer, parser.I18n:
+		return "z.number().int()"
+	case parser.UnsignedInteger:
+		return "z.number().int().nonnegative()"
+	case parser.Boolean:
+		return "z.boolean()"
+	case parser.String:
+		return "z.string()"
+	case parser.Number:
+		return "z.number()"
+	default:
+		return "z.unknown()"
+	}
+}
@@ -0,0 +1,72 @@
+package maprender
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/brequet/dofus-data-file-parser/pkg/parser"
+)
+
+// GfxSource returns the encoded image, PNG or JPEG, of a gfx id of
+// elements.ele, false when there is none.
+type GfxSource interface {
+	Gfx(gfxId int) ([]byte, bool)
+}
+
+// ArchiveGfx reads the images of the world gfx archives (content/gfx/world),
+// whose entries are named after their gfx id, e.g. png/12345.png.
+type ArchiveGfx struct {
+	entries map[int]archiveEntry
+}
+
+type archiveEntry struct {
+	archive parser.D2pArchive
+	entry   parser.D2pEntry
+}
+
+// LoadArchiveGfx indexes the entries of the .d2p archives of a folder tree.
+func LoadArchiveGfx(folderPath string, opts ...parser.ParseOptions) (ArchiveGfx, error) {
+	gfx := ArchiveGfx{entries: map[int]archiveEntry{}}
+	err := filepath.WalkDir(folderPath, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || filepath.Ext(filePath) != ".d2p" {
+			return nil
+		}
+
+		archive, err := parser.ProcessD2pFile(filePath, opts...)
+		if err != nil {
+			return fmt.Errorf("error reading %s: %w", filePath, err)
+		}
+		for _, d2pEntry := range archive.Entries {
+			name := filepath.Base(filepath.FromSlash(d2pEntry.Name))
+			gfxId, err := strconv.Atoi(strings.TrimSuffix(name, filepath.Ext(name)))
+			if err != nil {
+				continue
+			}
+			gfx.entries[gfxId] = archiveEntry{archive: archive, entry: d2pEntry}
+		}
+		return nil
+	})
+	if err != nil {
+		return ArchiveGfx{}, err
+	}
+	return gfx, nil
+}
+
+func (g ArchiveGfx) Gfx(gfxId int) ([]byte, bool) {
+	entry, ok := g.entries[gfxId]
+	if !ok {
+		return nil, false
+	}
+	return entry.archive.Read(entry.entry), true
+}
+
+// Len returns the number of images indexed.
+func (g ArchiveGfx) Len() int {
+	return len(g.entries)
+}
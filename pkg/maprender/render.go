@@ -0,0 +1,235 @@
+// Package maprender draws Dofus 2 maps from their DLM data, the graphical
+// elements of elements.ele and the images of the world gfx archives.
+package maprender
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/jpeg" // JPEG gfx
+	_ "image/png"
+	"slices"
+
+	"github.com/brequet/dofus-data-file-parser/pkg/parser"
+)
+
+// Map geometry, see AtouinConstants.as: the 560 cells are diamonds laid out
+// on 40 rows of 14, odd rows being shifted by half a cell.
+const (
+	MapWidth          = 14
+	MapRows           = 40
+	CellWidth         = 86
+	CellHalfWidth     = 43
+	CellHeight        = 43
+	CellHalfHeight    = 21.5
+	ImageWidth        = 1276
+	ImageHeight       = 876
+	altitudePixelUnit = 10
+)
+
+// Options selects what is drawn.
+type Options struct {
+	// Layers are the ids of the layers drawn (0 for the ground, 1 for the
+	// ground decorations, 2 for the objects), all when empty.
+	Layers []int
+	// Grid outlines the cells.
+	Grid bool
+	// Walkable fills the cells which cannot be walked on in red.
+	Walkable bool
+	// LineOfSight fills the cells blocking the line of sight in blue.
+	LineOfSight bool
+}
+
+var (
+	gridColor        = color.NRGBA{R: 255, G: 255, B: 255, A: 96}
+	nonWalkableColor = color.NRGBA{R: 220, G: 30, B: 30, A: 110}
+	losColor         = color.NRGBA{R: 30, G: 60, B: 220, A: 110}
+)
+
+// Renderer draws maps, decoding each image once. It is not safe for
+// concurrent use.
+//
+// Elements are drawn at their cell with their offset, origin, altitude and
+// symmetry; the hue and shadow of map elements, the blend modes and the
+// animated entities and particles are not rendered.
+type Renderer struct {
+	elements parser.Elements
+	gfx      GfxSource
+
+	images     map[int]image.Image // gfx id -> decoded image, nil when missing
+	flipped    map[int]image.Image
+	missingGfx map[int]bool
+}
+
+// NewRenderer returns a renderer drawing the elements of elements.ele with the
+// images of gfx.
+func NewRenderer(elements parser.Elements, gfx GfxSource) *Renderer {
+	return &Renderer{
+		elements:   elements,
+		gfx:        gfx,
+		images:     map[int]image.Image{},
+		flipped:    map[int]image.Image{},
+		missingGfx: map[int]bool{},
+	}
+}
+
+// MissingGfx returns the gfx ids of the elements which could not be drawn
+// since the first render, for lack of an image or of a valid one.
+func (r *Renderer) MissingGfx() []int {
+	ids := make([]int, 0, len(r.missingGfx))
+	for id := range r.missingGfx {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// Render draws a map on its background color.
+func (r *Renderer) Render(dlmMap parser.DlmMap, options Options) (*image.RGBA, error) {
+	if len(dlmMap.Cells) != MapWidth*MapRows {
+		return nil, fmt.Errorf("map %d has %d cells instead of %d", dlmMap.Id, len(dlmMap.Cells), MapWidth*MapRows)
+	}
+
+	canvas := image.NewRGBA(image.Rect(0, 0, ImageWidth, ImageHeight))
+	background := dlmMap.BackgroundColor
+	draw.Draw(canvas, canvas.Bounds(), image.NewUniform(color.RGBA{R: uint8(background.Red), G: uint8(background.Green), B: uint8(background.Blue), A: 255}), image.Point{}, draw.Src)
+
+	for _, layer := range dlmMap.Layers {
+		if len(options.Layers) > 0 && !slices.Contains(options.Layers, layer.LayerId) {
+			continue
+		}
+		for _, cell := range layer.Cells {
+			for _, mapElement := range cell.Elements {
+				if mapElement.ElementType == parser.GraphicalElementType {
+					r.drawElement(canvas, cell.CellId, mapElement)
+				}
+			}
+		}
+	}
+
+	for cellId, cell := range dlmMap.Cells {
+		if options.Walkable && !cell.Mov {
+			fillCell(canvas, cellId, nonWalkableColor)
+		}
+		if options.LineOfSight && !cell.Los {
+			fillCell(canvas, cellId, losColor)
+		}
+		if options.Grid {
+			outlineCell(canvas, cellId, gridColor)
+		}
+	}
+
+	return canvas, nil
+}
+
+func (r *Renderer) drawElement(canvas *image.RGBA, cellId int, mapElement parser.MapElement) {
+	element, ok := r.elements.Elements[mapElement.ElementId]
+	if !ok {
+		return
+	}
+	switch element.ElementType {
+	case parser.NormalElementType, parser.AnimatedElementType, parser.BlendedElementType:
+	default:
+		return // bounding boxes are invisible, entities and particles animated
+	}
+
+	img := r.image(element.GfxId, element.HorizontalSymmetry)
+	if img == nil {
+		return
+	}
+
+	cellX, cellY := cellPosition(cellId)
+	x := int(cellX + CellHalfWidth + mapElement.PixelOffsetX)
+	y := int(cellY + CellHalfHeight + mapElement.PixelOffsetY - float64(mapElement.Altitude*altitudePixelUnit))
+	originX := element.OriginX
+	if element.HorizontalSymmetry {
+		originX = img.Bounds().Dx() - element.OriginX
+	}
+	topLeft := image.Pt(x-originX, y-element.OriginY)
+
+	bounds := img.Bounds()
+	draw.Draw(canvas, bounds.Sub(bounds.Min).Add(topLeft), img, bounds.Min, draw.Over)
+}
+
+// image returns the decoded image of a gfx id, mirrored when flipped, nil
+// when it is missing.
+func (r *Renderer) image(gfxId int, flipped bool) image.Image {
+	img, ok := r.images[gfxId]
+	if !ok {
+		data, found := r.gfx.Gfx(gfxId)
+		if found {
+			img, _, _ = image.Decode(bytes.NewReader(data))
+		}
+		r.images[gfxId] = img
+	}
+	if img == nil {
+		r.missingGfx[gfxId] = true
+		return nil
+	}
+	if !flipped {
+		return img
+	}
+
+	flippedImg, ok := r.flipped[gfxId]
+	if !ok {
+		flippedImg = mirror(img)
+		r.flipped[gfxId] = flippedImg
+	}
+	return flippedImg
+}
+
+func mirror(img image.Image) image.Image {
+	bounds := img.Bounds()
+	mirrored := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	for y := 0; y < bounds.Dy(); y++ {
+		for x := 0; x < bounds.Dx(); x++ {
+			mirrored.Set(bounds.Dx()-1-x, y, img.At(bounds.Min.X+x, bounds.Min.Y+y))
+		}
+	}
+	return mirrored
+}
+
+// cellPosition returns the top left corner of the bounding box of a cell.
+func cellPosition(cellId int) (float64, float64) {
+	row, column := cellId/MapWidth, cellId%MapWidth
+	x := float64(column * CellWidth)
+	if row%2 == 1 {
+		x += CellHalfWidth
+	}
+	return x, float64(row) * CellHalfHeight
+}
+
+// cellSpan returns the horizontal extent of the diamond of a cell on the line
+// dy pixels below the top of its bounding box.
+func cellSpan(cellX float64, dy int) (int, int) {
+	distance := float64(dy) + 0.5 - CellHalfHeight
+	if distance < 0 {
+		distance = -distance
+	}
+	halfWidth := (CellHalfHeight - distance) * CellHalfWidth / CellHalfHeight
+	center := cellX + CellHalfWidth
+	return int(center - halfWidth), int(center + halfWidth)
+}
+
+func fillCell(canvas *image.RGBA, cellId int, c color.Color) {
+	cellX, cellY := cellPosition(cellId)
+	uniform := image.NewUniform(c)
+	for dy := 0; dy < CellHeight; dy++ {
+		left, right := cellSpan(cellX, dy)
+		y := int(cellY) + dy
+		draw.Draw(canvas, image.Rect(left, y, right, y+1), uniform, image.Point{}, draw.Over)
+	}
+}
+
+func outlineCell(canvas *image.RGBA, cellId int, c color.Color) {
+	cellX, cellY := cellPosition(cellId)
+	uniform := image.NewUniform(c)
+	for dy := 0; dy < CellHeight; dy++ {
+		left, right := cellSpan(cellX, dy)
+		y := int(cellY) + dy
+		draw.Draw(canvas, image.Rect(left, y, left+1, y+1), uniform, image.Point{}, draw.Over)
+		draw.Draw(canvas, image.Rect(right-1, y, right, y+1), uniform, image.Point{}, draw.Over)
+	}
+}
@@ -0,0 +1,32 @@
+package parser_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/brequet/dofus-data-file-parser/pkg/parser"
+)
+
+func BenchmarkParseD2o(b *testing.B) {
+	data := buildD2o(10000)
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	for range b.N {
+		_, err := parser.ParseD2o(bytes.NewReader(data))
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParseD2i(b *testing.B) {
+	data := buildD2i(10000)
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	for range b.N {
+		_, err := parser.ParseD2i(data)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package parser
+
+import (
+	"maps"
+	"slices"
+)
+
+// Clone returns a deep copy of the data, sharing no maps or slices with it.
+func Clone(data D2oData) D2oData {
+	classes := make(map[int]Class, len(data.Classes))
+	for id, class := range data.Classes {
+		classes[id] = cloneClass(class)
+	}
+
+	objects := make([]Object, 0, len(data.Objects))
+	for _, object := range data.Objects {
+		objects = append(objects, cloneValue(object))
+	}
+
+	var searchIndex SearchIndex
+	if data.SearchIndex != nil {
+		searchIndex = make(SearchIndex, len(data.SearchIndex))
+		for key, ids := range data.SearchIndex {
+			searchIndex[key] = append([]int(nil), ids...)
+		}
+	}
+
+	return D2oData{
+		Classes:         classes,
+		Objects:         objects,
+		SearchIndex:     searchIndex,
+		Warnings:        slices.Clone(data.Warnings),
+		objectPositions: maps.Clone(data.objectPositions),
+	}
+}
+
+func cloneClass(class Class) Class {
+	fields := make([]GameDataField, 0, len(class.Fields))
+	for _, field := range class.Fields {
+		fields = append(fields, cloneField(field))
+	}
+
+	clone := class
+	clone.Fields = fields
+	return clone
+}
+
+func cloneField(field GameDataField) GameDataField {
+	clone := field
+	if field.AllowedTypeIDs != nil {
+		clone.AllowedTypeIDs = append([]int(nil), field.AllowedTypeIDs...)
+	}
+	if field.SubType != nil {
+		subType := cloneField(*field.SubType)
+		clone.SubType = &subType
+	}
+	return clone
+}
+
+func cloneValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		clone := make(map[string]any, len(v))
+		for key, item := range v {
+			clone[key] = cloneValue(item)
+		}
+		return clone
+	case []any:
+		clone := make([]any, 0, len(v))
+		for _, item := range v {
+			clone = append(clone, cloneValue(item))
+		}
+		return clone
+	default:
+		return v
+	}
+}
@@ -0,0 +1,50 @@
+package parser_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/brequet/dofus-data-file-parser/pkg/parser"
+)
+
+func TestCloneModifiedCopy(t *testing.T) {
+	parse := func() parser.D2oData {
+		data, err := parser.ParseD2o(bytes.NewReader(buildD2o(3)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		data.SearchIndex = parser.SearchIndex{"name:item 1": {1}}
+		return data
+	}
+	original := parse()
+
+	clone := parser.Clone(original)
+	if !reflect.DeepEqual(clone, original) {
+		t.Fatalf("clone differs from the original")
+	}
+
+	object := clone.Objects[0].(map[string]any)
+	object["name"] = "modified"
+	object["added"] = true
+	object["effect"].(map[string]any)["value"] = -1
+	recipeIds := object["recipeIds"].([]any)
+	recipeIds[0] = -1
+	object["recipeIds"] = append(recipeIds, -2)
+	clone.Objects[1] = nil
+	clone.Objects = append(clone.Objects, map[string]any{})
+
+	class := clone.Classes[1]
+	class.Fields[0].Name = "modified"
+	class.Fields[4].SubType.Type = parser.String
+	class.Fields = append(class.Fields, parser.GameDataField{Name: "added"})
+	clone.Classes[1] = class
+	delete(clone.Classes, 2)
+
+	clone.SearchIndex["name:item 1"][0] = -1
+	clone.SearchIndex["added"] = []int{1}
+
+	if want := parse(); !reflect.DeepEqual(original, want) {
+		t.Errorf("modifying the clone modified the original:\ngot  %+v\nwant %+v", original, want)
+	}
+}
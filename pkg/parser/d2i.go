@@ -2,40 +2,117 @@ package parser
 
 import (
 	"fmt"
-	"log/slog"
-	"os"
 )
 
 type Translations map[int]string
 
-func ProcessD2iFile(d2iFilePath string) (Translations, error) {
-	// See I18nFileAccessor.as
+type D2iData struct {
+	Texts      map[int]Text      `json:"texts"`
+	NamedTexts map[string]string `json:"namedTexts"` // UI texts, e.g. "ui.common.ok"
+}
+
+type Text struct {
+	Text              string `json:"text"`
+	UndiacriticalText string `json:"undiacriticalText,omitempty"` // only set when it differs from Text
+}
+
+func ProcessD2iFile(d2iFilePath string, opts ...ParseOptions) (Translations, error) {
 	translations := map[int]string{}
-	slog.Debug("processing D2I file", "file", d2iFilePath)
 
-	fileContentBytes, err := os.ReadFile(d2iFilePath)
+	data, err := ProcessD2iFileData(d2iFilePath, opts...)
 	if err != nil {
-		return translations, fmt.Errorf("error reading file: %w", err)
+		return translations, err
 	}
 
-	dataInput := NewDataInput(fileContentBytes)
+	for id, text := range data.Texts {
+		translations[id] = text.Text
+	}
+
+	return translations, nil
+}
+
+// ProcessD2iFileData parses a D2I file, keeping the undiacritical variant of
+// the texts and the named texts.
+func ProcessD2iFileData(d2iFilePath string, opts ...ParseOptions) (D2iData, error) {
+	getParseOptions(opts).logger().Debug("processing D2I file", "file", d2iFilePath)
+
+	fileContentBytes, unmap, err := mapFile(d2iFilePath)
+	if err != nil {
+		return D2iData{Texts: map[int]Text{}, NamedTexts: map[string]string{}}, fmt.Errorf("error reading file: %w", err)
+	}
+	defer unmap()
+
+	return ParseD2i(fileContentBytes, opts...)
+}
+
+// ParseD2i parses D2I content, for data which does not come from a file.
+func ParseD2i(data []byte, opts ...ParseOptions) (D2iData, error) {
+	// See I18nFileAccessor.as
+	texts := map[int]Text{}
+	namedTexts := map[string]string{}
+
+	options := getParseOptions(opts)
+	options.progress().BytesRead(int64(len(data)))
+	dataInput := newDataInput(data, options.logger())
 
 	indexesPointer := dataInput.ReadInt()
 	dataInput.SetPointer(indexesPointer)
 
 	indexLen := dataInput.ReadInt()
 	endIndexPointer := dataInput.IndexPointer + indexLen
-	for dataInput.IndexPointer < endIndexPointer {
+	for dataInput.IndexPointer < endIndexPointer && dataInput.Err() == nil {
 		id := dataInput.ReadInt()
 		diacriticExists := dataInput.ReadBoolean()
-		translations[id] = readString(dataInput, dataInput.ReadInt())
+		text := Text{
+			Text: readString(dataInput, dataInput.ReadInt()),
+		}
 		if diacriticExists {
-			// skip
-			dataInput.ReadInt()
+			text.UndiacriticalText = readString(dataInput, dataInput.ReadInt())
 		}
+		texts[id] = text
 	}
 
-	return translations, nil
+	namedIndexLen := dataInput.ReadInt()
+	endNamedIndexPointer := dataInput.IndexPointer + namedIndexLen
+	for dataInput.IndexPointer < endNamedIndexPointer && dataInput.Err() == nil {
+		key := dataInput.ReadUTF()
+		namedTexts[key] = readString(dataInput, dataInput.ReadInt())
+	}
+
+	if err := dataInput.Err(); err != nil {
+		return D2iData{Texts: texts, NamedTexts: namedTexts}, fmt.Errorf("error reading indexes: %w", err)
+	}
+
+	return D2iData{Texts: texts, NamedTexts: namedTexts}, nil
+}
+
+// UndiacriticalTranslations returns the undiacritical variant of each text,
+// falling back to the text itself when it has none.
+func (d D2iData) UndiacriticalTranslations() Translations {
+	translations := map[int]string{}
+	for id, text := range d.Texts {
+		if text.UndiacriticalText != "" {
+			translations[id] = text.UndiacriticalText
+		} else {
+			translations[id] = text.Text
+		}
+	}
+	return translations
+}
+
+// MergeTranslations merges the translations of several locales into texts
+// keyed by id then by locale.
+func MergeTranslations(translationsByLocale map[string]Translations) map[int]map[string]string {
+	merged := map[int]map[string]string{}
+	for locale, translations := range translationsByLocale {
+		for id, text := range translations {
+			if merged[id] == nil {
+				merged[id] = map[string]string{}
+			}
+			merged[id][locale] = text
+		}
+	}
+	return merged
 }
 
 func readString(dataInput *DataInput, location int) string {
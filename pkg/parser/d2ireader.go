@@ -0,0 +1,168 @@
+package parser
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+)
+
+// I18nReader gives access to the texts of a D2I file while only holding its
+// index in memory, each text being read from the file when requested. It is
+// safe for concurrent use when its source is, as files are.
+type I18nReader struct {
+	r      io.ReaderAt
+	closer io.Closer // set when the reader opened the file
+
+	texts      map[int]i18nTextPointers
+	namedTexts map[string]int // key -> pointer
+}
+
+type i18nTextPointers struct {
+	text          int
+	undiacritical int // -1 when the text has no undiacritical variant
+}
+
+// NewI18nReader opens a D2I file and reads its index. The reader must be
+// closed once done with.
+func NewI18nReader(d2iFilePath string, opts ...ParseOptions) (*I18nReader, error) {
+	getParseOptions(opts).logger().Debug("opening D2I file", "file", d2iFilePath)
+
+	file, err := os.Open(d2iFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("error opening file: %w", err)
+	}
+
+	reader, err := NewI18nReaderFrom(file, opts...)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	reader.closer = file
+	return reader, nil
+}
+
+// NewI18nReaderFrom is like NewI18nReader but reads the D2I content from r,
+// which must stay readable as long as the reader is used.
+func NewI18nReaderFrom(r io.ReaderAt, opts ...ParseOptions) (*I18nReader, error) {
+	// See I18nFileAccessor.as
+	options := getParseOptions(opts)
+
+	header := make([]byte, 4)
+	_, err := r.ReadAt(header, 0)
+	if err != nil {
+		return nil, fmt.Errorf("%w: reading indexes pointer: %w", ErrTruncatedData, err)
+	}
+	indexesPointer := int64(int32(binary.BigEndian.Uint32(header)))
+	if indexesPointer < 4 {
+		return nil, fmt.Errorf("%w: indexes pointer %d", ErrInvalidHeader, indexesPointer)
+	}
+
+	// the indexes are at the end of the file, texts are read from r on demand
+	indexes, err := io.ReadAll(io.NewSectionReader(r, indexesPointer, math.MaxInt64-indexesPointer))
+	if err != nil {
+		return nil, fmt.Errorf("error reading indexes: %w", err)
+	}
+	options.progress().BytesRead(int64(len(indexes)))
+
+	dataInput := newDataInput(indexes, options.logger())
+	texts := map[int]i18nTextPointers{}
+	indexLen := dataInput.ReadInt()
+	endIndexPointer := dataInput.IndexPointer + indexLen
+	for dataInput.IndexPointer < endIndexPointer && dataInput.Err() == nil {
+		id := dataInput.ReadInt()
+		diacriticExists := dataInput.ReadBoolean()
+		pointers := i18nTextPointers{text: dataInput.ReadInt(), undiacritical: -1}
+		if diacriticExists {
+			pointers.undiacritical = dataInput.ReadInt()
+		}
+		texts[id] = pointers
+	}
+
+	namedTexts := map[string]int{}
+	namedIndexLen := dataInput.ReadInt()
+	endNamedIndexPointer := dataInput.IndexPointer + namedIndexLen
+	for dataInput.IndexPointer < endNamedIndexPointer && dataInput.Err() == nil {
+		key := dataInput.ReadUTF()
+		namedTexts[key] = dataInput.ReadInt()
+	}
+
+	if err := dataInput.Err(); err != nil {
+		return nil, fmt.Errorf("error reading indexes: %w", err)
+	}
+
+	return &I18nReader{
+		r:          r,
+		texts:      texts,
+		namedTexts: namedTexts,
+	}, nil
+}
+
+// Close closes the file opened by NewI18nReader. It does nothing for readers
+// created with NewI18nReaderFrom.
+func (r *I18nReader) Close() error {
+	if r.closer == nil {
+		return nil
+	}
+	return r.closer.Close()
+}
+
+// TextIds returns the sorted ids of the texts.
+func (r *I18nReader) TextIds() []int {
+	ids := make([]int, 0, len(r.texts))
+	for id := range r.texts {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// GetText reads the text with the given id.
+func (r *I18nReader) GetText(id int) (string, error) {
+	pointers, ok := r.texts[id]
+	if !ok {
+		return "", fmt.Errorf("text not found: %d", id)
+	}
+	return r.readUTFAt(int64(pointers.text))
+}
+
+// GetUndiacriticalText reads the undiacritical variant of the text with the
+// given id, falling back to the text itself when it has none.
+func (r *I18nReader) GetUndiacriticalText(id int) (string, error) {
+	pointers, ok := r.texts[id]
+	if !ok {
+		return "", fmt.Errorf("text not found: %d", id)
+	}
+	if pointers.undiacritical < 0 {
+		return r.readUTFAt(int64(pointers.text))
+	}
+	return r.readUTFAt(int64(pointers.undiacritical))
+}
+
+// GetNamedText reads the UI text with the given key, e.g. "ui.common.ok".
+func (r *I18nReader) GetNamedText(key string) (string, error) {
+	pointer, ok := r.namedTexts[key]
+	if !ok {
+		return "", fmt.Errorf("named text not found: %s", key)
+	}
+	return r.readUTFAt(int64(pointer))
+}
+
+// readUTFAt reads a modified UTF-8 string prefixed by its unsigned short
+// length.
+func (r *I18nReader) readUTFAt(pointer int64) (string, error) {
+	length := make([]byte, 2)
+	_, err := r.r.ReadAt(length, pointer)
+	if err != nil {
+		return "", fmt.Errorf("%w: reading string length at %#x: %w", ErrTruncatedData, pointer, err)
+	}
+
+	text := make([]byte, binary.BigEndian.Uint16(length))
+	_, err = r.r.ReadAt(text, pointer+2)
+	if err != nil {
+		return "", fmt.Errorf("%w: reading string at %#x: %w", ErrTruncatedData, pointer, err)
+	}
+	return decodeModifiedUTF8(text), nil
+}
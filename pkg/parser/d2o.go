@@ -1,32 +1,107 @@
 package parser
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"io"
 	"log/slog"
 	"math"
-	"os"
+	"slices"
 	"sort"
 )
 
 type D2oData struct {
-	Classes map[int]Class `json:"classes"`
-	Objects []Object      `json:"objects"`
+	Classes     map[int]Class `json:"classes"`
+	Objects     []Object      `json:"objects"`
+	SearchIndex SearchIndex   `json:"-"`
+	// Warnings lists the objects which could not be decoded and are missing
+	// from Objects, out of strict mode.
+	Warnings []ObjectWarning `json:"-"`
+
+	objectPositions map[int]int // object id -> position in Objects
+}
+
+// ObjectWarning is an object of a D2O file which could not be decoded.
+type ObjectWarning struct {
+	ObjectId int
+	// Offset is the position of the object in the file.
+	Offset int
+	Err    error
+}
+
+func (w ObjectWarning) String() string {
+	return fmt.Sprintf("object %d at %#x: %v", w.ObjectId, w.Offset, w.Err)
+}
+
+// ObjectCount returns the number of objects in the data.
+func (d D2oData) ObjectCount() int {
+	return len(d.Objects)
+}
+
+// ClassCount returns the number of classes defined in the data.
+func (d D2oData) ClassCount() int {
+	return len(d.Classes)
+}
+
+// PackageCount returns the number of distinct packages the classes belong to.
+func (d D2oData) PackageCount() int {
+	packages := map[string]struct{}{}
+	for _, class := range d.Classes {
+		packages[class.PackageName] = struct{}{}
+	}
+	return len(packages)
+}
+
+// UniqueClassTypes returns the sorted list of class names appearing in objects.
+func (d D2oData) UniqueClassTypes() []string {
+	classTypes := map[string]struct{}{}
+	for _, object := range d.Objects {
+		if objectMap, ok := object.(map[string]any); ok {
+			if classType, ok := objectMap["ClassType_"].(string); ok {
+				classTypes[classType] = struct{}{}
+			}
+		}
+	}
+
+	uniqueClassTypes := make([]string, 0, len(classTypes))
+	for classType := range classTypes {
+		uniqueClassTypes = append(uniqueClassTypes, classType)
+	}
+	sort.Strings(uniqueClassTypes)
+	return uniqueClassTypes
+}
+
+// ObjectByID returns the object having the given id in the index table.
+func (d D2oData) ObjectByID(id int) (Object, bool) {
+	position, ok := d.objectPositions[id]
+	if !ok {
+		return nil, false
+	}
+	return d.Objects[position], true
+}
+
+// ObjectIDs returns the ids of the objects, in the order of Objects.
+func (d D2oData) ObjectIDs() []int {
+	return getKeysSortedByValue(d.objectPositions)
 }
 
 type Class struct {
 	PackageName  string          `json:"packageName"`
 	PackageClass string          `json:"packageClass"`
 	Fields       []GameDataField `json:"fields"`
+	Parent       string          `json:"parent,omitempty"`           // detected parent class name
+	ParentFields int             `json:"parentFieldCount,omitempty"` // number of leading fields inherited from the parent
 }
 
 type Object = any
 
 type GameDataField struct {
-	Name    string         `json:"name"`
-	Type    FieldType      `json:"type"`
-	SubType *GameDataField `json:"subtype,omitempty"`
+	Name           string         `json:"name"`
+	Type           FieldType      `json:"type"`
+	TypeName       string         `json:"typeName,omitempty"`       // referenced class name, for custom types
+	AllowedTypeIDs []int          `json:"allowedTypeIds,omitempty"` // allowed class ids, for vectors of custom types
+	SubType        *GameDataField `json:"subtype,omitempty"`
 }
 
 type FieldType int
@@ -42,6 +117,24 @@ const (
 	Vector          FieldType = -99
 )
 
+// nullClassIdentifier is the class id written in place of a null object.
+const nullClassIdentifier = -1431655766
+
+// maxNestingDepth is the maximum depth of the vectors and objects nested in an
+// object, far beyond the one of the game data, so that corrupt data cannot
+// overflow the stack.
+const maxNestingDepth = 64
+
+// isBasic tells whether the type is not a vector nor a custom object.
+func (f FieldType) isBasic() bool {
+	switch f {
+	case Integer, Boolean, String, Number, I18n, UnsignedInteger:
+		return true
+	default:
+		return false
+	}
+}
+
 func (f FieldType) String() string {
 	switch f {
 	case Integer:
@@ -67,80 +160,86 @@ func (f FieldType) MarshalJSON() ([]byte, error) {
 	return json.Marshal(f.String())
 }
 
-func ProcessD2oFile(d2oFilePath string) (D2oData, error) {
-	// See GameDataFileAccessor.as
-	slog.Debug("processing D2O file", "file", d2oFilePath)
+func ProcessD2oFile(d2oFilePath string, opts ...ParseOptions) (D2oData, error) {
+	return ProcessD2oFileContext(context.Background(), d2oFilePath, opts...)
+}
 
-	fileContentBytes, err := os.ReadFile(d2oFilePath)
+// ProcessD2oFileContext is like ProcessD2oFile but stops decoding objects
+// when ctx is done, returning the error of ctx.
+func ProcessD2oFileContext(ctx context.Context, d2oFilePath string, opts ...ParseOptions) (D2oData, error) {
+	reader, err := NewD2oReader(d2oFilePath, opts...)
 	if err != nil {
-		return D2oData{}, fmt.Errorf("error reading file: %w", err)
+		return D2oData{}, err
 	}
+	defer reader.Close()
 
-	dataInput := NewDataInput(fileContentBytes)
-	header := string(dataInput.Read(3))
-	if header != "D2O" {
-		return D2oData{}, fmt.Errorf("invalid header: %s", header)
-	}
-
-	indexesPointer := dataInput.ReadInt()
-	dataInput.SetPointer(indexesPointer)
-	slog.Debug("indexes pointer", "pointer", indexesPointer)
+	return readAllObjects(ctx, reader)
+}
 
-	indexTable := make(map[int]int)
-	indexesLength := dataInput.ReadInt() / 8
-	slog.Debug("indexes length", "length", indexesLength)
-	for i := 0; i < indexesLength; i++ {
-		key := dataInput.ReadInt()
-		pointer := dataInput.ReadInt()
-		indexTable[key] = pointer
+// ParseD2o parses D2O content read from r, for data which does not come from
+// a file.
+func ParseD2o(r io.ReaderAt, opts ...ParseOptions) (D2oData, error) {
+	reader, err := NewD2oReaderFrom(r, opts...)
+	if err != nil {
+		return D2oData{}, err
 	}
 
-	classTable := make(map[int]Class)
-	classCount := dataInput.ReadInt()
-	slog.Debug("class count", "count", classCount)
-	for i := 0; i < classCount; i++ {
-		classIdentifier := dataInput.ReadInt()
-		class := readClassDefinition(dataInput)
-		classTable[classIdentifier] = class
+	return readAllObjects(context.Background(), reader)
+}
+
+func readAllObjects(ctx context.Context, reader *D2oReader) (D2oData, error) {
+	objectIds := reader.sortedObjectIds()
+	reader.dataInput.logger.Debug("index values", "count", len(objectIds))
+	decodedObjects, err := reader.decodeObjects(ctx, objectIds)
+	if err != nil {
+		return D2oData{}, err
 	}
 
-	objects := make([]Object, 0)
-	indexValues := getSortedValues(indexTable)
-	slog.Debug("index values", "count", len(indexValues))
-	for _, index := range indexValues {
-		dataInput.SetPointer(index)
-		slog.Debug("reading object", "index", dataInput.OffsetStr())
-		classId := dataInput.ReadInt()
-		object := readObject(dataInput, classTable, classTable[classId])
-		objects = append(objects, object)
+	objects := make([]Object, 0, len(objectIds))
+	var warnings []ObjectWarning
+	objectPositions := make(map[int]int, len(objectIds))
+	for i, objectId := range objectIds {
+		if decodedObjects[i].warning != nil {
+			warnings = append(warnings, *decodedObjects[i].warning)
+			continue
+		}
+		objectPositions[objectId] = len(objects)
+		objects = append(objects, decodedObjects[i].object)
 	}
 
 	return D2oData{
-		Classes: classTable,
-		Objects: objects,
+		Classes:         reader.classTable,
+		Objects:         objects,
+		SearchIndex:     reader.searchIndex,
+		Warnings:        warnings,
+		objectPositions: objectPositions,
 	}, nil
 }
 
-func readClassDefinition(dataInput *DataInput) Class {
+func readClassDefinition(dataInput *DataInput) (Class, error) {
 	className := dataInput.ReadUTF()
 	packageName := dataInput.ReadUTF()
 
-	slog.Debug("reading class", "package", packageName, "class", className)
+	dataInput.logger.Debug("reading class", "package", packageName, "class", className)
 
 	fields := make([]GameDataField, 0)
 	fieldsCount := dataInput.ReadInt()
-	for i := 0; i < fieldsCount; i++ {
-		fields = append(fields, readField(dataInput))
+	for i := 0; i < fieldsCount && dataInput.Err() == nil; i++ {
+		field, err := readField(dataInput, 0)
+		if err != nil {
+			return Class{}, fmt.Errorf("error reading field %d of class %s: %w", i, className, err)
+		}
+		fields = append(fields, field)
 	}
 
 	return Class{
 		PackageName:  packageName,
 		PackageClass: className,
 		Fields:       fields,
-	}
+	}, nil
 }
 
-func readField(dataInput *DataInput) GameDataField {
+func readField(dataInput *DataInput, depth int) (GameDataField, error) {
 	fieldName := dataInput.ReadUTF()
 	var fieldType FieldType
 	var subType *GameDataField
@@ -149,15 +248,23 @@ func readField(dataInput *DataInput) GameDataField {
 	switch FieldType(fieldTypeId) {
 	case Vector:
 		fieldType = Vector
-		subTypeObj := readField(dataInput)
+		if depth >= maxNestingDepth {
+			return GameDataField{}, fmt.Errorf("%w: vector field %s", ErrNestingTooDeep, fieldName)
+		}
+		subTypeObj, err := readField(dataInput, depth+1)
+		if err != nil {
+			return GameDataField{}, fmt.Errorf("error reading subtype of %s: %w", fieldName, err)
+		}
 		subType = &subTypeObj
 	default:
-		if fieldTypeId < 0 { // GameDataTypeEnum cases
-			fieldType = FieldType(fieldTypeId)
-		} else if fieldTypeId > 0 { // Custom Object cases
-			fieldType = FieldType(fieldTypeId)
-		} else {
-			log.Fatal("unknown type ", dataInput.IndexPointer, fieldTypeId)
+		// GameDataTypeEnum cases, or custom objects for positive ids
+		fieldType = FieldType(fieldTypeId)
+		if fieldTypeId <= 0 && !fieldType.isBasic() && dataInput.Err() == nil {
+			// the objects of the class cannot be decoded, but the other ones can
+			err := dataInput.anomaly(&ErrUnknownFieldType{Field: fieldName, TypeID: fieldTypeId, Offset: dataInput.IndexPointer})
+			if err != nil {
+				return GameDataField{}, err
+			}
 		}
 	}
 
@@ -165,18 +272,122 @@ func readField(dataInput *DataInput) GameDataField {
 		Name:    fieldName,
 		Type:    fieldType,
 		SubType: subType,
+	}, nil
+}
+
+// resolveCustomTypeNames sets the TypeName of every custom type field (and
+// vector subtype) to the name of the class it references, and the
+// AllowedTypeIDs of every vector of custom types.
+func resolveCustomTypeNames(classTable map[int]Class) {
+	for _, class := range classTable {
+		for i := range class.Fields {
+			resolveFieldTypeName(&class.Fields[i], classTable)
+		}
 	}
+	resolveParentClasses(classTable)
 }
 
-func readObject(dataInput *DataInput, classeTable map[int]Class, class Class) Object {
-	object := map[string]any{}
+// resolveParentClasses sets the parent of the classes extending a class
+// referenced by a field, which are the polymorphic ones (e.g. the effects of
+// an item). When several of those classes are a field prefix of a class, the
+// parent is the one with the most fields.
+func resolveParentClasses(classTable map[int]Class) {
+	referencedClassIds := map[int]bool{}
+	for _, class := range classTable {
+		for _, field := range class.Fields {
+			for f := &field; f != nil; f = f.SubType {
+				if f.Type > 0 {
+					referencedClassIds[int(f.Type)] = true
+				}
+			}
+		}
+	}
+
+	for id, class := range classTable {
+		parentId := -1
+		for candidateId := range referencedClassIds {
+			candidate, ok := classTable[candidateId]
+			if !ok || candidateId == id || len(candidate.Fields) >= len(class.Fields) || !hasFieldPrefix(class.Fields, candidate.Fields) {
+				continue
+			}
+			if parentId == -1 || len(candidate.Fields) > len(classTable[parentId].Fields) || (len(candidate.Fields) == len(classTable[parentId].Fields) && candidateId < parentId) {
+				parentId = candidateId
+			}
+		}
+
+		if parentId != -1 {
+			class.Parent = classTable[parentId].PackageClass
+			class.ParentFields = len(classTable[parentId].Fields)
+			classTable[id] = class
+		}
+	}
+}
+
+func resolveFieldTypeName(field *GameDataField, classTable map[int]Class) {
+	if field.SubType != nil {
+		resolveFieldTypeName(field.SubType, classTable)
+	}
+	if field.Type > 0 {
+		if class, ok := classTable[int(field.Type)]; ok {
+			field.TypeName = class.PackageClass
+		}
+	}
+	if field.Type == Vector && field.SubType.Type > 0 {
+		field.AllowedTypeIDs = getAllowedTypeIds(classTable, int(field.SubType.Type))
+	}
+}
+
+// getAllowedTypeIds returns the given class id along with the ids of the
+// classes extending it. Inherited fields are serialized first, so a subclass is
+// a class whose field list starts with all the fields of its parent.
+func getAllowedTypeIds(classTable map[int]Class, classId int) []int {
+	parent, ok := classTable[classId]
+	if !ok {
+		return nil
+	}
+
+	allowedTypeIds := make([]int, 0)
+	for id, class := range classTable {
+		if id == classId || hasFieldPrefix(class.Fields, parent.Fields) {
+			allowedTypeIds = append(allowedTypeIds, id)
+		}
+	}
+	sort.Ints(allowedTypeIds)
+	return allowedTypeIds
+}
+
+func hasFieldPrefix(fields, prefix []GameDataField) bool {
+	if len(prefix) == 0 || len(fields) < len(prefix) {
+		return false
+	}
+	for i := range prefix {
+		if fields[i].Name != prefix[i].Name || fields[i].Type != prefix[i].Type {
+			return false
+		}
+	}
+	return true
+}
+
+func readObject(dataInput *DataInput, classeTable map[int]Class, class Class, depth int) (Object, error) {
+	if depth > maxNestingDepth {
+		return nil, fmt.Errorf("%w: object of class %s at %s", ErrNestingTooDeep, class.PackageClass, dataInput.OffsetStr())
+	}
+	object := make(map[string]any, len(class.Fields)+1)
 	object["ClassType_"] = class.PackageClass
 
-	slog.Debug("reading object", "class", fmt.Sprintf("%s.%s", class.PackageName, class.PackageClass), "field count", len(class.Fields), "offset", dataInput.OffsetStr())
+	if dataInput.debug {
+		dataInput.logger.Debug("reading object", "class", fmt.Sprintf("%s.%s", class.PackageName, class.PackageClass), "field count", len(class.Fields), "offset", dataInput.OffsetStr())
+	}
 	for _, field := range class.Fields {
+		if dataInput.Err() != nil {
+			// the remaining fields cannot be read either
+			break
+		}
 		fieldObject := interface{}(nil)
 		fieldType := field.Type
-		slog.Debug("reading field", "name", field.Name, "type", fieldType, "offset", dataInput.OffsetStr())
+		if dataInput.debug {
+			dataInput.logger.Debug("reading field", "name", field.Name, "type", fieldType, "offset", dataInput.OffsetStr())
+		}
 		switch fieldType {
 		case Integer:
 			fieldObject = dataInput.ReadInt()
@@ -196,27 +407,58 @@ func readObject(dataInput *DataInput, classeTable map[int]Class, class Class) Ob
 		case UnsignedInteger:
 			fieldObject = dataInput.ReadUint()
 		case Vector:
-			fieldObject = readVector(dataInput, classeTable, field)
+			vector, err := readVector(dataInput, classeTable, field, depth+1)
+			if err != nil {
+				return nil, fmt.Errorf("error reading field %s: %w", field.Name, err)
+			}
+			fieldObject = vector
 		default:
+			if fieldType <= 0 {
+				return nil, &ErrUnknownFieldType{Field: field.Name, TypeID: int(fieldType), Offset: dataInput.IndexPointer}
+			}
 			classId := dataInput.ReadInt()
-			if _, ok := classeTable[classId]; !ok {
+			if classId == nullClassIdentifier {
+				break
+			}
+			if _, ok := classeTable[classId]; !ok && dataInput.Err() == nil {
+				err := dataInput.anomaly(fmt.Errorf("unknown class id %d for field %s at %s, read as class id %d", classId, field.Name, dataInput.OffsetStr(), field.Type))
+				if err != nil {
+					return nil, err
+				}
 				classId = int(field.Type)
 			}
-			fieldObject = readObject(dataInput, classeTable, classeTable[classId])
+			fieldClass, ok := classeTable[classId]
+			if !ok {
+				return nil, fmt.Errorf("unknown class id %d for field %s at %s", classId, field.Name, dataInput.OffsetStr())
+			}
+			fieldObject, err := readObject(dataInput, classeTable, fieldClass, depth+1)
+			if err != nil {
+				return nil, fmt.Errorf("error reading field %s: %w", field.Name, err)
+			}
+			object[field.Name] = fieldObject
+			continue
 		}
 		object[field.Name] = fieldObject
 	}
 
-	return object
+	return object, nil
 }
 
-func readVector(dataInput *DataInput, classeTable map[int]Class, field GameDataField) Object {
-	vector := []any{}
-
+func readVector(dataInput *DataInput, classeTable map[int]Class, field GameDataField, depth int) (Object, error) {
+	if depth > maxNestingDepth {
+		return nil, fmt.Errorf("%w: vector at %s", ErrNestingTooDeep, dataInput.OffsetStr())
+	}
 	vectorLength := dataInput.ReadInt()
-	slog.Debug("reading vector", "size", vectorLength, slog.Group("field", "name", field.Name, "type", field.Type), "offset", dataInput.OffsetStr())
-	for i := 0; i < vectorLength; i++ {
-		// slog.Debug("reading vector element", "index", i, "type", field.SubType.Type, "offset", dataInput.OffsetStr())
+	// every element takes at least a byte, even a null object
+	if vectorLength < 0 || vectorLength > dataInput.Remaining() {
+		return nil, fmt.Errorf("%w: vector of %d elements with %d bytes left at %s", ErrTruncatedData, vectorLength, dataInput.Remaining(), dataInput.OffsetStr())
+	}
+	if dataInput.debug {
+		dataInput.logger.Debug("reading vector", "size", vectorLength, slog.Group("field", "name", field.Name, "type", field.Type), "offset", dataInput.OffsetStr())
+	}
+	vector := make([]any, 0, vectorLength)
+	for i := 0; i < vectorLength && dataInput.Err() == nil; i++ {
+		// dataInput.logger.Debug("reading vector element", "index", i, "type", field.SubType.Type, "offset", dataInput.OffsetStr())
 		switch field.SubType.Type {
 		case Integer:
 			vector = append(vector, dataInput.ReadInt())
@@ -231,25 +473,51 @@ func readVector(dataInput *DataInput, classeTable map[int]Class, field GameDataF
 		case UnsignedInteger:
 			vector = append(vector, dataInput.ReadUint())
 		case Vector:
-			vector = append(vector, readVector(dataInput, classeTable, *field.SubType))
+			subVector, err := readVector(dataInput, classeTable, *field.SubType, depth+1)
+			if err != nil {
+				return nil, fmt.Errorf("error reading element %d: %w", i, err)
+			}
+			vector = append(vector, subVector)
 		default:
+			if field.SubType.Type <= 0 {
+				return nil, &ErrUnknownFieldType{Field: field.SubType.Name, TypeID: int(field.SubType.Type), Offset: dataInput.IndexPointer}
+			}
 			classId := dataInput.ReadInt()
-			if _, ok := classeTable[classId]; ok {
-				vector = append(vector, readObject(dataInput, classeTable, classeTable[classId]))
-			} else {
+			if classId == nullClassIdentifier {
 				vector = append(vector, nil)
+				continue
+			}
+			if len(field.AllowedTypeIDs) > 0 && !slices.Contains(field.AllowedTypeIDs, classId) && dataInput.Err() == nil {
+				err := dataInput.anomaly(fmt.Errorf("class id %d of element %d of field %s not in the allowed ones %v at %s", classId, i, field.Name, field.AllowedTypeIDs, dataInput.OffsetStr()))
+				if err != nil {
+					return nil, err
+				}
 			}
+			elementClass, ok := classeTable[classId]
+			if !ok {
+				return nil, fmt.Errorf("unknown class id %d for element %d at %s", classId, i, dataInput.OffsetStr())
+			}
+			element, err := readObject(dataInput, classeTable, elementClass, depth+1)
+			if err != nil {
+				return nil, fmt.Errorf("error reading element %d: %w", i, err)
+			}
+			vector = append(vector, element)
 		}
 	}
 
-	return vector
+	return vector, nil
 }
 
-func getSortedValues(m map[int]int) []int {
-	values := make([]int, 0, len(m))
-	for _, value := range m {
-		values = append(values, value)
+func getKeysSortedByValue(m map[int]int) []int {
+	keys := make([]int, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
 	}
-	sort.Ints(values)
-	return values
+	sort.Slice(keys, func(i, j int) bool {
+		if m[keys[i]] != m[keys[j]] {
+			return m[keys[i]] < m[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
 }
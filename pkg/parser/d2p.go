@@ -0,0 +1,127 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+type D2pArchive struct {
+	Properties []D2pProperty `json:"properties"`
+	Entries    []D2pEntry    `json:"entries"`
+
+	data []byte
+}
+
+type D2pProperty struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type D2pEntry struct {
+	Name   string `json:"name"`
+	Offset int    `json:"offset"`
+	Length int    `json:"length"`
+}
+
+func ProcessD2pFile(d2pFilePath string, opts ...ParseOptions) (D2pArchive, error) {
+	// See PakProtocol2.as
+	logger := getParseOptions(opts).logger()
+	logger.Debug("processing D2P file", "file", d2pFilePath)
+
+	fileContentBytes, err := os.ReadFile(d2pFilePath)
+	if err != nil {
+		return D2pArchive{}, fmt.Errorf("error reading file: %w", err)
+	}
+
+	if len(fileContentBytes) < 2+24 {
+		return D2pArchive{}, fmt.Errorf("%w: file of %d bytes", ErrTruncatedData, len(fileContentBytes))
+	}
+
+	dataInput := newDataInput(fileContentBytes, logger)
+	vMax := dataInput.ReadUnsignedByte()
+	vMin := dataInput.ReadUnsignedByte()
+	if vMax != 2 || vMin != 1 {
+		return D2pArchive{}, fmt.Errorf("%w: unsupported version %d.%d", ErrInvalidHeader, vMax, vMin)
+	}
+
+	dataInput.SetPointer(dataInput.Length - 24)
+	dataOffset := dataInput.ReadInt()
+	_ = dataInput.ReadInt() // data count
+	indexOffset := dataInput.ReadInt()
+	indexCount := dataInput.ReadInt()
+	propertiesOffset := dataInput.ReadInt()
+	propertiesCount := dataInput.ReadInt()
+	logger.Debug("d2p header", "data offset", dataOffset, "index count", indexCount, "properties count", propertiesCount)
+
+	dataInput.SetPointer(propertiesOffset)
+	properties := make([]D2pProperty, 0)
+	for i := 0; i < propertiesCount && dataInput.Err() == nil; i++ {
+		properties = append(properties, D2pProperty{
+			Key:   dataInput.ReadUTF(),
+			Value: dataInput.ReadUTF(),
+		})
+	}
+
+	dataInput.SetPointer(indexOffset)
+	entries := make([]D2pEntry, 0)
+	for i := 0; i < indexCount && dataInput.Err() == nil; i++ {
+		entry := D2pEntry{
+			Name:   dataInput.ReadUTF(),
+			Offset: dataInput.ReadInt() + dataOffset,
+			Length: dataInput.ReadInt(),
+		}
+		if entry.Offset < 0 || entry.Length < 0 || entry.Offset+entry.Length > dataInput.Length {
+			return D2pArchive{}, fmt.Errorf("%w: entry %s out of bounds", ErrTruncatedData, entry.Name)
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := dataInput.Err(); err != nil {
+		return D2pArchive{}, fmt.Errorf("error reading index: %w", err)
+	}
+
+	return D2pArchive{
+		Properties: properties,
+		Entries:    entries,
+		data:       fileContentBytes,
+	}, nil
+}
+
+// Links returns the archives linked to this one, relative to its folder.
+func (a D2pArchive) Links() []string {
+	links := make([]string, 0)
+	for _, property := range a.Properties {
+		if property.Key == "link" {
+			links = append(links, property.Value)
+		}
+	}
+	return links
+}
+
+// Read returns the content of an entry.
+func (a D2pArchive) Read(entry D2pEntry) []byte {
+	return a.data[entry.Offset : entry.Offset+entry.Length]
+}
+
+// ExtractAll writes every entry under the output folder, keeping their paths.
+func (a D2pArchive) ExtractAll(outputFolderPath string) error {
+	for _, entry := range a.Entries {
+		if !filepath.IsLocal(filepath.FromSlash(entry.Name)) {
+			return fmt.Errorf("invalid entry path: %s", entry.Name)
+		}
+		entryPath := filepath.Join(outputFolderPath, filepath.FromSlash(entry.Name))
+
+		err := os.MkdirAll(filepath.Dir(entryPath), 0755)
+		if err != nil {
+			return fmt.Errorf("error creating folder: %w", err)
+		}
+
+		err = os.WriteFile(entryPath, a.Read(entry), 0644)
+		if err != nil {
+			return fmt.Errorf("error writing file: %w", err)
+		}
+	}
+
+	return nil
+}
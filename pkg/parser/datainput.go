@@ -1,27 +1,81 @@
 package parser
 
 import (
+	"context"
 	"encoding/binary"
 	"fmt"
+	"log/slog"
 	"math"
 )
 
+// DataInput reads big endian values from a buffer. Reading out of bounds does
+// not panic: it records an error, returned by Err, and yields zero values
+// until ClearErr is called.
 type DataInput struct {
 	Data         []byte
 	IndexPointer int
 	Length       int
+
+	err    error
+	logger *slog.Logger
+	// debug tells whether the logger is enabled for debug logs, for the ones
+	// of the decoding of each object and field to cost nothing otherwise.
+	debug  bool
+	strict bool
 }
 
 func NewDataInput(data []byte) *DataInput {
+	return newDataInput(data, discardLogger)
+}
+
+func newDataInput(data []byte, logger *slog.Logger) *DataInput {
 	return &DataInput{
 		Data:         data,
 		IndexPointer: 0,
 		Length:       len(data),
+		logger:       logger,
+		debug:        logger.Enabled(context.Background(), slog.LevelDebug),
+	}
+}
+
+// view returns a DataInput reading the same data from its own position, to
+// read it from another goroutine.
+func (di *DataInput) view() *DataInput {
+	view := newDataInput(di.Data, di.logger)
+	view.strict = di.strict
+	return view
+}
+
+// Err returns the first error encountered while reading.
+func (di *DataInput) Err() error {
+	return di.err
+}
+
+func (di *DataInput) ClearErr() {
+	di.err = nil
+}
+
+// anomaly reports data which can be read but is not as expected: it returns
+// err in strict mode, and logs it as a warning otherwise.
+func (di *DataInput) anomaly(err error) error {
+	if di.strict {
+		return err
 	}
+	di.logger.Warn("ignoring anomaly", "error", err)
+	return nil
+}
+
+// Remaining returns the number of bytes left to read.
+func (di *DataInput) Remaining() int {
+	return max(len(di.Data)-di.IndexPointer, 0)
 }
 
 func (di *DataInput) Read(n int) []byte {
-	if di.IndexPointer+n > len(di.Data) {
+	if di.err != nil {
+		return nil
+	}
+	if n < 0 || di.IndexPointer < 0 || di.IndexPointer+n > len(di.Data) {
+		di.err = fmt.Errorf("%w: reading %d bytes at %s", ErrTruncatedData, n, di.OffsetStr())
 		return nil
 	}
 	data := di.Data[di.IndexPointer : di.IndexPointer+n]
@@ -30,33 +84,100 @@ func (di *DataInput) Read(n int) []byte {
 }
 
 func (di *DataInput) ReadInt() int {
-	return int(int32(binary.BigEndian.Uint32(di.Read(4))))
+	data := di.Read(4)
+	if data == nil {
+		return 0
+	}
+	return int(int32(binary.BigEndian.Uint32(data)))
 }
 
 func (di *DataInput) ReadUint() uint {
-	return uint(binary.BigEndian.Uint32(di.Read(4)))
+	data := di.Read(4)
+	if data == nil {
+		return 0
+	}
+	return uint(binary.BigEndian.Uint32(data))
+}
+
+func (di *DataInput) ReadLong() int64 {
+	data := di.Read(8)
+	if data == nil {
+		return 0
+	}
+	return int64(binary.BigEndian.Uint64(data))
+}
+
+func (di *DataInput) ReadShort() int16 {
+	return int16(di.ReadUnsignedShort())
 }
 
 func (di *DataInput) ReadUnsignedShort() uint16 {
-	return binary.BigEndian.Uint16(di.Read(2))
+	data := di.Read(2)
+	if data == nil {
+		return 0
+	}
+	return binary.BigEndian.Uint16(data)
 }
 
+// ReadUTF reads a modified UTF-8 string prefixed by its unsigned short length.
 func (di *DataInput) ReadUTF() string {
 	lon := int(di.ReadUnsignedShort())
-	return string(di.Read(lon))
+	return decodeModifiedUTF8(di.Read(lon))
+}
+
+// ReadNullTerminatedString reads bytes up to the next 0x00 byte (consumed but
+// not returned), or up to the end of the data if there is none.
+func (di *DataInput) ReadNullTerminatedString() string {
+	if di.err != nil {
+		return ""
+	}
+	if di.IndexPointer < 0 || di.IndexPointer > len(di.Data) {
+		di.err = fmt.Errorf("%w: reading string at %s", ErrTruncatedData, di.OffsetStr())
+		return ""
+	}
+	start := di.IndexPointer
+	for di.IndexPointer < len(di.Data) && di.Data[di.IndexPointer] != 0 {
+		di.IndexPointer++
+	}
+	str := string(di.Data[start:di.IndexPointer])
+	if di.IndexPointer < len(di.Data) {
+		di.IndexPointer++
+	}
+	return str
 }
 
 func (di *DataInput) ReadBoolean() bool {
 	ans := di.Read(1)
-	return ans[0] == 1
+	return ans != nil && ans[0] == 1
+}
+
+func (di *DataInput) ReadFloat() float32 {
+	data := di.Read(4)
+	if data == nil {
+		return 0
+	}
+	return math.Float32frombits(binary.BigEndian.Uint32(data))
 }
 
 func (di *DataInput) ReadDouble() float64 {
-	return math.Float64frombits(binary.BigEndian.Uint64(di.Read(8)))
+	data := di.Read(8)
+	if data == nil {
+		return 0
+	}
+	return math.Float64frombits(binary.BigEndian.Uint64(data))
+}
+
+// ReadSignedByte reads a signed byte, ReadByte in AS3.
+func (di *DataInput) ReadSignedByte() int8 {
+	return int8(di.ReadUnsignedByte())
 }
 
 func (di *DataInput) ReadUnsignedByte() uint8 {
-	return di.Read(1)[0]
+	data := di.Read(1)
+	if data == nil {
+		return 0
+	}
+	return data[0]
 }
 
 func (di *DataInput) ReadVarInt() int {
@@ -68,13 +189,58 @@ func (di *DataInput) ReadVarInt() int {
 			return ans
 		}
 	}
-	panic("Too much data")
+	if di.err == nil {
+		di.err = fmt.Errorf("too much data for var int at %s", di.OffsetStr())
+	}
+	return 0
 }
 
 func (di *DataInput) ReadVarUhInt() int {
 	return di.ReadVarInt()
 }
 
+// ReadVarShort reads a signed 16 bits integer encoded on 1 to 3 bytes of 7
+// bits.
+func (di *DataInput) ReadVarShort() int16 {
+	return int16(di.readVar(16, "short"))
+}
+
+// ReadVarUhShort reads an unsigned 16 bits integer encoded on 1 to 3 bytes of
+// 7 bits.
+func (di *DataInput) ReadVarUhShort() uint16 {
+	return uint16(di.readVar(16, "short"))
+}
+
+// ReadVarLong reads a signed 64 bits integer encoded on 1 to 10 bytes of 7
+// bits.
+func (di *DataInput) ReadVarLong() int64 {
+	return int64(di.readVar(64, "long"))
+}
+
+// ReadVarUhLong reads an unsigned 64 bits integer encoded on 1 to 10 bytes of
+// 7 bits.
+func (di *DataInput) ReadVarUhLong() uint64 {
+	return di.readVar(64, "long")
+}
+
+// readVar reads an integer of the given size encoded on bytes of 7 bits, the
+// least significant first, whose most significant bit tells whether another
+// byte follows.
+func (di *DataInput) readVar(bits int, typeName string) uint64 {
+	var ans uint64
+	for i := 0; i < bits; i += 7 {
+		b := di.ReadUnsignedByte()
+		ans |= uint64(b&0b01111111) << i
+		if b&0b10000000 == 0 {
+			return ans
+		}
+	}
+	if di.err == nil {
+		di.err = fmt.Errorf("too much data for var %s at %s", typeName, di.OffsetStr())
+	}
+	return 0
+}
+
 func (di *DataInput) AreBytesAvailable() bool {
 	return di.IndexPointer < len(di.Data)
 }
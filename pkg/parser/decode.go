@@ -0,0 +1,170 @@
+package parser
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ClassTypes creates the values objects are decoded into from their class
+// name, such as the runtime.ClassRegistry of the generated types.
+type ClassTypes interface {
+	New(className string) (any, bool)
+}
+
+// DecodeOptions configures DecodeInto, which takes them as an optional last
+// argument.
+type DecodeOptions struct {
+	// Classes creates the values of the fields of a non-empty interface type,
+	// such as the <Class>Type interfaces of the generated types, from the
+	// "ClassType_" entry of their object. Without it, such fields can only be
+	// assigned values which implement them already.
+	Classes ClassTypes
+}
+
+// DecodeInto fills the struct pointed to by target from a parsed object. Struct
+// fields are matched on their json tag name, or on their name ignoring case.
+func DecodeInto(obj Object, target any, opts ...DecodeOptions) error {
+	targetValue := reflect.ValueOf(target)
+	if targetValue.Kind() != reflect.Pointer || targetValue.IsNil() {
+		return fmt.Errorf("target must be a non-nil pointer, got %T", target)
+	}
+
+	var decoder decoder
+	if len(opts) > 0 {
+		decoder.classes = opts[0].Classes
+	}
+	return decoder.decodeValue(obj, targetValue.Elem(), "")
+}
+
+type decoder struct {
+	classes ClassTypes
+}
+
+func (d decoder) decodeValue(value any, target reflect.Value, path string) error {
+	if value == nil {
+		target.Set(reflect.Zero(target.Type()))
+		return nil
+	}
+
+	switch target.Kind() {
+	case reflect.Pointer:
+		element := reflect.New(target.Type().Elem())
+		err := d.decodeValue(value, element.Elem(), path)
+		if err != nil {
+			return err
+		}
+		target.Set(element)
+		return nil
+	case reflect.Interface:
+		if objectMap, ok := value.(map[string]any); ok && d.classes != nil && target.NumMethod() > 0 {
+			return d.decodeClass(objectMap, target, path)
+		}
+		valueOf := reflect.ValueOf(value)
+		if !valueOf.Type().AssignableTo(target.Type()) {
+			return fmt.Errorf("%s: cannot assign %T to %s", path, value, target.Type())
+		}
+		target.Set(valueOf)
+		return nil
+	case reflect.Struct:
+		objectMap, ok := value.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%s: expected object, got %T", path, value)
+		}
+		return d.decodeStruct(objectMap, target, path)
+	case reflect.Slice:
+		vector, ok := value.([]any)
+		if !ok {
+			return fmt.Errorf("%s: expected vector, got %T", path, value)
+		}
+		slice := reflect.MakeSlice(target.Type(), len(vector), len(vector))
+		for i, item := range vector {
+			err := d.decodeValue(item, slice.Index(i), fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return err
+			}
+		}
+		target.Set(slice)
+		return nil
+	default:
+		valueOf := reflect.ValueOf(value)
+		if (valueOf.Kind() == reflect.String) != (target.Kind() == reflect.String) || !valueOf.CanConvert(target.Type()) {
+			return fmt.Errorf("%s: cannot convert %T to %s", path, value, target.Type())
+		}
+		target.Set(valueOf.Convert(target.Type()))
+		return nil
+	}
+}
+
+// decodeClass decodes an object into the value created for its class, which
+// must implement the interface of the target.
+func (d decoder) decodeClass(objectMap map[string]any, target reflect.Value, path string) error {
+	className, _ := objectMap["ClassType_"].(string)
+	instance, ok := d.classes.New(className)
+	if !ok {
+		return fmt.Errorf("%s: class %q not registered", path, className)
+	}
+	instanceValue := reflect.ValueOf(instance)
+	if instanceValue.Kind() != reflect.Pointer || !instanceValue.Type().AssignableTo(target.Type()) {
+		return fmt.Errorf("%s: %T of class %s does not implement %s", path, instance, className, target.Type())
+	}
+
+	err := d.decodeValue(objectMap, instanceValue.Elem(), path)
+	if err != nil {
+		return err
+	}
+	target.Set(instanceValue)
+	return nil
+}
+
+func (d decoder) decodeStruct(objectMap map[string]any, target reflect.Value, path string) error {
+	targetType := target.Type()
+	for i := 0; i < targetType.NumField(); i++ {
+		structField := targetType.Field(i)
+		if !structField.IsExported() {
+			continue
+		}
+
+		tag, _, _ := strings.Cut(structField.Tag.Get("json"), ",")
+		if structField.Anonymous && structField.Type.Kind() == reflect.Struct && tag == "" {
+			// embedded parent class, whose fields are those of the object
+			err := d.decodeStruct(objectMap, target.Field(i), path)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		name := structField.Name
+		if tag != "" {
+			if tag == "-" {
+				continue
+			}
+			name = tag
+		}
+
+		value, ok := objectMap[name]
+		if !ok {
+			value, ok = findKeyIgnoringCase(objectMap, name)
+		}
+		if !ok {
+			continue
+		}
+
+		err := d.decodeValue(value, target.Field(i), path+"."+name)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func findKeyIgnoringCase(objectMap map[string]any, name string) (any, bool) {
+	for key, value := range objectMap {
+		if strings.EqualFold(key, name) {
+			return value, true
+		}
+	}
+	return nil, false
+}
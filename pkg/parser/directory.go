@@ -0,0 +1,82 @@
+package parser
+
+import (
+	"fmt"
+	"iter"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// ParseD2oDirectory lazily parses the .d2o files of a directory in alphabetical
+// order, yielding one result per file.
+func ParseD2oDirectory(dir string, opts ...ParseOptions) iter.Seq2[D2oData, error] {
+	return func(yield func(D2oData, error) bool) {
+		files, err := os.ReadDir(dir)
+		if err != nil {
+			yield(D2oData{}, fmt.Errorf("error reading directory: %w", err))
+			return
+		}
+
+		filePaths := make([]string, 0, len(files))
+		for _, file := range files {
+			if !file.IsDir() && filepath.Ext(file.Name()) == ".d2o" {
+				filePaths = append(filePaths, filepath.Join(dir, file.Name()))
+			}
+		}
+
+		progress := getParseOptions(opts).progress()
+		progress.FilesDiscovered(len(filePaths))
+		for _, filePath := range filePaths {
+			data, err := ProcessD2oFile(filePath, opts...)
+			progress.FileParsed(filePath)
+			if !yield(data, err) {
+				return
+			}
+		}
+	}
+}
+
+// D2iFile is the content of a i18n_<locale>.d2i file.
+type D2iFile struct {
+	Locale string
+	Data   D2iData
+}
+
+// ParseD2iDirectory lazily parses the i18n_<locale>.d2i files of a directory in
+// alphabetical order, yielding one result per file. Only the files of the
+// given locales are parsed, or all of them when locales is empty.
+func ParseD2iDirectory(dir string, locales []string, opts ...ParseOptions) iter.Seq2[D2iFile, error] {
+	return func(yield func(D2iFile, error) bool) {
+		files, err := os.ReadDir(dir)
+		if err != nil {
+			yield(D2iFile{}, fmt.Errorf("error reading directory: %w", err))
+			return
+		}
+
+		d2iFiles := make([]D2iFile, 0, len(files)) // without their data yet
+		for _, file := range files {
+			locale, ok := strings.CutPrefix(strings.TrimSuffix(file.Name(), ".d2i"), "i18n_")
+			if file.IsDir() || filepath.Ext(file.Name()) != ".d2i" || !ok {
+				continue
+			}
+			if len(locales) > 0 && !slices.Contains(locales, locale) {
+				continue
+			}
+			d2iFiles = append(d2iFiles, D2iFile{Locale: locale})
+		}
+
+		progress := getParseOptions(opts).progress()
+		progress.FilesDiscovered(len(d2iFiles))
+		for _, d2iFile := range d2iFiles {
+			filePath := filepath.Join(dir, "i18n_"+d2iFile.Locale+".d2i")
+			data, err := ProcessD2iFileData(filePath, opts...)
+			progress.FileParsed(filePath)
+			d2iFile.Data = data
+			if !yield(d2iFile, err) {
+				return
+			}
+		}
+	}
+}
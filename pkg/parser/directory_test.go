@@ -0,0 +1,48 @@
+package parser_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/brequet/dofus-data-file-parser/pkg/parser"
+)
+
+// countingProgress counts the files discovered and parsed.
+type countingProgress struct {
+	discovered, parsed int
+}
+
+func (p *countingProgress) FilesDiscovered(count int) { p.discovered += count }
+func (p *countingProgress) FileParsed(string)         { p.parsed++ }
+func (p *countingProgress) BytesRead(int64)           {}
+func (p *countingProgress) ObjectsDecoded(int)        {}
+
+func TestParseD2iDirectory(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"i18n_de.d2i", "i18n_en.d2i", "i18n_fr.d2i", "notes.d2i"} {
+		if err := os.WriteFile(filepath.Join(dir, name), buildD2i(3), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	progress := &countingProgress{}
+	var locales []string
+	for file, err := range parser.ParseD2iDirectory(dir, []string{"en", "fr"}, parser.ParseOptions{Progress: progress}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(file.Data.Texts) != 3 {
+			t.Errorf("locale %s: %d texts, want 3", file.Locale, len(file.Data.Texts))
+		}
+		locales = append(locales, file.Locale)
+	}
+
+	if want := []string{"en", "fr"}; !reflect.DeepEqual(locales, want) {
+		t.Errorf("locales %v, want %v", locales, want)
+	}
+	if progress.discovered != 2 || progress.parsed != 2 {
+		t.Errorf("%d files discovered and %d parsed, want 2", progress.discovered, progress.parsed)
+	}
+}
@@ -0,0 +1,426 @@
+package parser
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"os"
+)
+
+// DefaultDlmKey is the key used to decrypt the map data of encrypted DLM files.
+const DefaultDlmKey = "649ae451ca33ec53bbcbcc33becf15f4"
+
+const dlmCellsCount = 560
+
+// DLM element types
+const (
+	GraphicalElementType = 2
+	SoundElementType     = 33
+)
+
+type DlmMap struct {
+	Version                int       `json:"version"`
+	Id                     uint      `json:"id"`
+	Encrypted              bool      `json:"encrypted"`
+	EncryptionVersion      int       `json:"encryptionVersion"`
+	RelativeId             uint      `json:"relativeId"`
+	MapType                int       `json:"mapType"`
+	SubareaId              int       `json:"subareaId"`
+	TopNeighbourId         int       `json:"topNeighbourId"`
+	BottomNeighbourId      int       `json:"bottomNeighbourId"`
+	LeftNeighbourId        int       `json:"leftNeighbourId"`
+	RightNeighbourId       int       `json:"rightNeighbourId"`
+	ShadowBonusOnEntities  int       `json:"shadowBonusOnEntities"`
+	BackgroundColor        DlmColor  `json:"backgroundColor"`
+	GridColor              DlmColor  `json:"gridColor"`
+	ZoomScale              float64   `json:"zoomScale"`
+	ZoomOffsetX            int       `json:"zoomOffsetX"`
+	ZoomOffsetY            int       `json:"zoomOffsetY"`
+	TacticalModeTemplateId int       `json:"tacticalModeTemplateId"`
+	UseLowPassFilter       bool      `json:"useLowPassFilter"`
+	UseReverb              bool      `json:"useReverb"`
+	PresetId               int       `json:"presetId"`
+	BackgroundFixtures     []Fixture `json:"backgroundFixtures"`
+	ForegroundFixtures     []Fixture `json:"foregroundFixtures"`
+	GroundCRC              int       `json:"groundCRC"`
+	Layers                 []Layer   `json:"layers"`
+	Cells                  []Cell    `json:"cells"`
+}
+
+type DlmColor struct {
+	Alpha int `json:"alpha"`
+	Red   int `json:"red"`
+	Green int `json:"green"`
+	Blue  int `json:"blue"`
+}
+
+type Fixture struct {
+	FixtureId       int `json:"fixtureId"`
+	OffsetX         int `json:"offsetX"`
+	OffsetY         int `json:"offsetY"`
+	Rotation        int `json:"rotation"`
+	XScale          int `json:"xScale"`
+	YScale          int `json:"yScale"`
+	RedMultiplier   int `json:"redMultiplier"`
+	GreenMultiplier int `json:"greenMultiplier"`
+	BlueMultiplier  int `json:"blueMultiplier"`
+	Alpha           int `json:"alpha"`
+}
+
+type Layer struct {
+	LayerId int         `json:"layerId"`
+	Cells   []LayerCell `json:"cells"`
+}
+
+type LayerCell struct {
+	CellId   int          `json:"cellId"`
+	Elements []MapElement `json:"elements"`
+}
+
+// MapElement is either a graphical element or a sound element, depending on
+// its ElementType.
+type MapElement struct {
+	ElementType int `json:"elementType"`
+
+	// graphical element
+	ElementId    uint    `json:"elementId,omitempty"`
+	Hue          [3]int  `json:"hue"`
+	Shadow       [3]int  `json:"shadow"`
+	OffsetX      float64 `json:"offsetX,omitempty"`
+	OffsetY      float64 `json:"offsetY,omitempty"`
+	PixelOffsetX float64 `json:"pixelOffsetX,omitempty"`
+	PixelOffsetY float64 `json:"pixelOffsetY,omitempty"`
+	Altitude     int     `json:"altitude,omitempty"`
+	Identifier   uint    `json:"identifier,omitempty"`
+
+	// sound element
+	SoundId              int `json:"soundId,omitempty"`
+	BaseVolume           int `json:"baseVolume,omitempty"`
+	FullVolumeDistance   int `json:"fullVolumeDistance,omitempty"`
+	NullVolumeDistance   int `json:"nullVolumeDistance,omitempty"`
+	MinDelayBetweenLoops int `json:"minDelayBetweenLoops,omitempty"`
+	MaxDelayBetweenLoops int `json:"maxDelayBetweenLoops,omitempty"`
+}
+
+type Cell struct {
+	Floor                  int  `json:"floor"`
+	Mov                    bool `json:"mov"`
+	Los                    bool `json:"los"`
+	NonWalkableDuringFight bool `json:"nonWalkableDuringFight"`
+	NonWalkableDuringRP    bool `json:"nonWalkableDuringRP"`
+	Blue                   bool `json:"blue"`
+	Red                    bool `json:"red"`
+	Visible                bool `json:"visible"`
+	FarmCell               bool `json:"farmCell"`
+	HavenbagCell           bool `json:"havenbagCell"`
+	Speed                  int  `json:"speed"`
+	MapChangeData          int  `json:"mapChangeData"`
+	MoveZone               int  `json:"moveZone"`
+	LinkedZone             int  `json:"linkedZone"`
+	Arrow                  int  `json:"arrow"`
+}
+
+func ProcessDlmFile(dlmFilePath string, opts ...ParseOptions) (DlmMap, error) {
+	getParseOptions(opts).logger().Debug("processing DLM file", "file", dlmFilePath)
+
+	fileContentBytes, err := os.ReadFile(dlmFilePath)
+	if err != nil {
+		return DlmMap{}, fmt.Errorf("error reading file: %w", err)
+	}
+
+	return ParseDlm(fileContentBytes, DefaultDlmKey, opts...)
+}
+
+// ParseDlm decodes a DLM map, compressed or not, decrypting its data with the
+// given key when needed.
+func ParseDlm(data []byte, key string, opts ...ParseOptions) (DlmMap, error) {
+	// See Map.as
+	var err error
+	if len(data) > 0 && data[0] != 'M' {
+		data, err = uncompress(data)
+		if err != nil {
+			return DlmMap{}, fmt.Errorf("error uncompressing map: %w", err)
+		}
+	}
+
+	logger := getParseOptions(opts).logger()
+	dataInput := newDataInput(data, logger)
+	header := dataInput.ReadUnsignedByte()
+	if header != 'M' {
+		return DlmMap{}, fmt.Errorf("%w: %d", ErrInvalidHeader, header)
+	}
+
+	dlmMap := DlmMap{}
+	dlmMap.Version = int(dataInput.ReadUnsignedByte())
+	dlmMap.Id = dataInput.ReadUint()
+	logger.Debug("reading map", "id", dlmMap.Id, "version", dlmMap.Version)
+
+	if dlmMap.Version >= 7 {
+		dlmMap.Encrypted = dataInput.ReadBoolean()
+		dlmMap.EncryptionVersion = int(dataInput.ReadUnsignedByte())
+		dataLength := dataInput.ReadInt()
+		if dlmMap.Encrypted {
+			if key == "" {
+				return DlmMap{}, fmt.Errorf("map %d is encrypted and no key is given", dlmMap.Id)
+			}
+			encryptedData := dataInput.Read(dataLength)
+			if encryptedData == nil {
+				return DlmMap{}, fmt.Errorf("%w: encrypted data of %d bytes", ErrTruncatedData, dataLength)
+			}
+			dataInput = newDataInput(decrypt(encryptedData, key), logger)
+		}
+	}
+
+	dlmMap.RelativeId = dataInput.ReadUint()
+	dlmMap.MapType = readByte(dataInput)
+	dlmMap.SubareaId = dataInput.ReadInt()
+	dlmMap.TopNeighbourId = dataInput.ReadInt()
+	dlmMap.BottomNeighbourId = dataInput.ReadInt()
+	dlmMap.LeftNeighbourId = dataInput.ReadInt()
+	dlmMap.RightNeighbourId = dataInput.ReadInt()
+	dlmMap.ShadowBonusOnEntities = dataInput.ReadInt()
+
+	if dlmMap.Version >= 9 {
+		dlmMap.BackgroundColor = toDlmColor(dataInput.ReadUint())
+		dlmMap.GridColor = toDlmColor(dataInput.ReadUint())
+	} else if dlmMap.Version >= 3 {
+		dlmMap.BackgroundColor = DlmColor{
+			Red:   readByte(dataInput),
+			Green: readByte(dataInput),
+			Blue:  readByte(dataInput),
+		}
+	}
+
+	dlmMap.ZoomScale = 1
+	if dlmMap.Version >= 4 {
+		dlmMap.ZoomScale = float64(dataInput.ReadUnsignedShort()) / 100
+		dlmMap.ZoomOffsetX = readShort(dataInput)
+		dlmMap.ZoomOffsetY = readShort(dataInput)
+		if dlmMap.ZoomScale < 1 {
+			dlmMap.ZoomScale = 1
+			dlmMap.ZoomOffsetX = 0
+			dlmMap.ZoomOffsetY = 0
+		}
+	}
+
+	if dlmMap.Version > 10 {
+		dlmMap.TacticalModeTemplateId = dataInput.ReadInt()
+	}
+
+	dlmMap.UseLowPassFilter = dataInput.ReadBoolean()
+	dlmMap.UseReverb = dataInput.ReadBoolean()
+	dlmMap.PresetId = -1
+	if dlmMap.UseReverb {
+		dlmMap.PresetId = dataInput.ReadInt()
+	}
+
+	dlmMap.BackgroundFixtures = readFixtures(dataInput)
+	dlmMap.ForegroundFixtures = readFixtures(dataInput)
+
+	dataInput.ReadInt() // unused
+	dlmMap.GroundCRC = dataInput.ReadInt()
+
+	layersCount := readByte(dataInput)
+	dlmMap.Layers = make([]Layer, 0, max(layersCount, 0))
+	for i := 0; i < layersCount && dataInput.Err() == nil; i++ {
+		layer, err := readLayer(dataInput, dlmMap.Version)
+		if err != nil {
+			return DlmMap{}, fmt.Errorf("error reading layer %d: %w", i, err)
+		}
+		dlmMap.Layers = append(dlmMap.Layers, layer)
+	}
+
+	dlmMap.Cells = make([]Cell, 0, dlmCellsCount)
+	for i := 0; i < dlmCellsCount; i++ {
+		dlmMap.Cells = append(dlmMap.Cells, readCell(dataInput, dlmMap.Version))
+	}
+
+	if err := dataInput.Err(); err != nil {
+		return DlmMap{}, err
+	}
+
+	return dlmMap, nil
+}
+
+func uncompress(data []byte) ([]byte, error) {
+	reader, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	return io.ReadAll(reader)
+}
+
+func decrypt(data []byte, key string) []byte {
+	decrypted := make([]byte, len(data))
+	for i := range data {
+		decrypted[i] = data[i] ^ key[i%len(key)]
+	}
+	return decrypted
+}
+
+func toDlmColor(color uint) DlmColor {
+	return DlmColor{
+		Alpha: int(color>>24) & 0xFF,
+		Red:   int(color>>16) & 0xFF,
+		Green: int(color>>8) & 0xFF,
+		Blue:  int(color) & 0xFF,
+	}
+}
+
+func readFixtures(dataInput *DataInput) []Fixture {
+	// See Fixture.as
+	fixturesCount := readByte(dataInput)
+	fixtures := make([]Fixture, 0, max(fixturesCount, 0))
+	for i := 0; i < fixturesCount && dataInput.Err() == nil; i++ {
+		fixtures = append(fixtures, Fixture{
+			FixtureId:       dataInput.ReadInt(),
+			OffsetX:         readShort(dataInput),
+			OffsetY:         readShort(dataInput),
+			Rotation:        readShort(dataInput),
+			XScale:          readShort(dataInput),
+			YScale:          readShort(dataInput),
+			RedMultiplier:   readByte(dataInput),
+			GreenMultiplier: readByte(dataInput),
+			BlueMultiplier:  readByte(dataInput),
+			Alpha:           int(dataInput.ReadUnsignedByte()),
+		})
+	}
+	return fixtures
+}
+
+func readLayer(dataInput *DataInput, mapVersion int) (Layer, error) {
+	// See Layer.as
+	layer := Layer{}
+	if mapVersion >= 9 {
+		layer.LayerId = readByte(dataInput)
+	} else {
+		layer.LayerId = dataInput.ReadInt()
+	}
+
+	cellsCount := readShort(dataInput)
+	layer.Cells = make([]LayerCell, 0, max(cellsCount, 0))
+	for i := 0; i < cellsCount && dataInput.Err() == nil; i++ {
+		cell := LayerCell{
+			CellId: readShort(dataInput),
+		}
+
+		elementsCount := readShort(dataInput)
+		cell.Elements = make([]MapElement, 0, max(elementsCount, 0))
+		for j := 0; j < elementsCount && dataInput.Err() == nil; j++ {
+			element, err := readMapElement(dataInput, mapVersion)
+			if err != nil {
+				return Layer{}, err
+			}
+			cell.Elements = append(cell.Elements, element)
+		}
+		layer.Cells = append(layer.Cells, cell)
+	}
+
+	return layer, nil
+}
+
+func readMapElement(dataInput *DataInput, mapVersion int) (MapElement, error) {
+	// See BasicElement.as, GraphicalElement.as and SoundElement.as
+	element := MapElement{
+		ElementType: readByte(dataInput),
+	}
+
+	switch element.ElementType {
+	case GraphicalElementType:
+		element.ElementId = dataInput.ReadUint()
+		element.Hue = [3]int{readByte(dataInput), readByte(dataInput), readByte(dataInput)}
+		element.Shadow = [3]int{readByte(dataInput), readByte(dataInput), readByte(dataInput)}
+		if mapVersion <= 4 {
+			element.OffsetX = float64(readByte(dataInput))
+			element.OffsetY = float64(readByte(dataInput))
+			element.PixelOffsetX = element.OffsetX * 43
+			element.PixelOffsetY = element.OffsetY * 21.5
+		} else {
+			element.PixelOffsetX = float64(readShort(dataInput))
+			element.PixelOffsetY = float64(readShort(dataInput))
+			element.OffsetX = element.PixelOffsetX / 43
+			element.OffsetY = element.PixelOffsetY / 21.5
+		}
+		element.Altitude = readByte(dataInput)
+		element.Identifier = dataInput.ReadUint()
+	case SoundElementType:
+		element.SoundId = dataInput.ReadInt()
+		element.BaseVolume = readShort(dataInput)
+		element.FullVolumeDistance = dataInput.ReadInt()
+		element.NullVolumeDistance = dataInput.ReadInt()
+		element.MinDelayBetweenLoops = readShort(dataInput)
+		element.MaxDelayBetweenLoops = readShort(dataInput)
+	default:
+		if err := dataInput.Err(); err != nil {
+			return MapElement{}, err
+		}
+		return MapElement{}, fmt.Errorf("unknown element type %d at %s", element.ElementType, dataInput.OffsetStr())
+	}
+
+	return element, nil
+}
+
+func readCell(dataInput *DataInput, mapVersion int) Cell {
+	// See CellData.as
+	cell := Cell{
+		Floor: readByte(dataInput) * 10,
+	}
+	if cell.Floor == -1280 {
+		return cell
+	}
+
+	if mapVersion >= 9 {
+		bits := readShort(dataInput)
+		cell.Mov = bits&1 == 0
+		cell.NonWalkableDuringFight = bits&2 != 0
+		cell.NonWalkableDuringRP = bits&4 != 0
+		cell.Los = bits&8 == 0
+		cell.Blue = bits&16 != 0
+		cell.Red = bits&32 != 0
+		cell.Visible = bits&64 != 0
+		cell.FarmCell = bits&128 != 0
+		if mapVersion >= 10 {
+			cell.HavenbagCell = bits&256 != 0
+		}
+	} else {
+		bits := dataInput.ReadUnsignedByte()
+		cell.Mov = bits&1 != 0
+		cell.Los = bits&2 != 0
+		cell.NonWalkableDuringFight = bits&4 != 0
+		cell.Red = bits&8 != 0
+		cell.Blue = bits&16 != 0
+		cell.FarmCell = bits&32 != 0
+		cell.Visible = bits&64 != 0
+		cell.NonWalkableDuringRP = bits&128 != 0
+	}
+
+	cell.Speed = readByte(dataInput)
+	cell.MapChangeData = int(dataInput.ReadUnsignedByte())
+
+	if mapVersion > 5 {
+		cell.MoveZone = int(dataInput.ReadUnsignedByte())
+	}
+
+	hasLinkedZoneRP := cell.Mov && !cell.FarmCell
+	hasLinkedZoneFight := cell.Mov && !cell.NonWalkableDuringFight && !cell.FarmCell && !cell.HavenbagCell
+	if mapVersion > 10 && (hasLinkedZoneRP || hasLinkedZoneFight) {
+		cell.LinkedZone = int(dataInput.ReadUnsignedByte())
+	}
+
+	if mapVersion > 7 && mapVersion < 9 {
+		cell.Arrow = 15 & readByte(dataInput)
+	}
+
+	return cell
+}
+
+func readByte(dataInput *DataInput) int {
+	return int(int8(dataInput.ReadUnsignedByte()))
+}
+
+func readShort(dataInput *DataInput) int {
+	return int(int16(dataInput.ReadUnsignedShort()))
+}
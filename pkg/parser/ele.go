@@ -0,0 +1,182 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+)
+
+// Graphical element types of elements.ele
+const (
+	NormalElementType      = 0
+	BoundingBoxElementType = 1
+	AnimatedElementType    = 2
+	EntityElementType      = 3
+	ParticlesElementType   = 4
+	BlendedElementType     = 5
+)
+
+// Elements is the content of elements.ele, describing the graphical elements
+// the map cells refer to by MapElement.ElementId.
+type Elements struct {
+	Version  int                       `json:"version"`
+	Elements map[uint]GraphicalElement `json:"elements"`
+	JpgGfx   []int                     `json:"jpgGfx"` // gfx ids of the JPG images, the others being PNG
+}
+
+// GraphicalElement is the graphic of a map element, whose fields depend on its
+// ElementType.
+type GraphicalElement struct {
+	Id          uint `json:"id"`
+	ElementType int  `json:"elementType"`
+
+	// normal, bounding box, animated and blended elements
+	GfxId              int  `json:"gfxId,omitempty"`
+	Height             int  `json:"height,omitempty"`
+	HorizontalSymmetry bool `json:"horizontalSymmetry,omitempty"`
+	OriginX            int  `json:"originX,omitempty"`
+	OriginY            int  `json:"originY,omitempty"`
+	SizeX              int  `json:"sizeX,omitempty"`
+	SizeY              int  `json:"sizeY,omitempty"`
+
+	// animated and entity elements
+	MinDelay int `json:"minDelay,omitempty"`
+	MaxDelay int `json:"maxDelay,omitempty"`
+
+	// entity element
+	EntityLook          string `json:"entityLook,omitempty"`
+	PlayAnimation       bool   `json:"playAnimation,omitempty"`
+	PlayAnimationStatic bool   `json:"playAnimationStatic,omitempty"`
+
+	// particles element
+	ScriptId int `json:"scriptId,omitempty"`
+
+	// blended element
+	BlendMode string `json:"blendMode,omitempty"`
+}
+
+// ProcessEleFile reads elements.ele, found next to the map archives in
+// content/maps.
+func ProcessEleFile(eleFilePath string, opts ...ParseOptions) (Elements, error) {
+	getParseOptions(opts).logger().Debug("processing ELE file", "file", eleFilePath)
+
+	fileContentBytes, err := os.ReadFile(eleFilePath)
+	if err != nil {
+		return Elements{}, fmt.Errorf("error reading file: %w", err)
+	}
+
+	return ParseEle(fileContentBytes, opts...)
+}
+
+// ParseEle decodes the graphical elements file, compressed or not.
+func ParseEle(data []byte, opts ...ParseOptions) (Elements, error) {
+	// See Elements.as and GraphicalElementFactory.as
+	var err error
+	if len(data) > 0 && data[0] != 'E' {
+		data, err = uncompress(data)
+		if err != nil {
+			return Elements{}, fmt.Errorf("error uncompressing elements: %w", err)
+		}
+	}
+
+	options := getParseOptions(opts)
+	logger := options.logger()
+	dataInput := newDataInput(data, logger)
+	dataInput.strict = options.Strict
+	header := dataInput.ReadUnsignedByte()
+	if header != 'E' {
+		return Elements{}, fmt.Errorf("%w: %d", ErrInvalidHeader, header)
+	}
+
+	elements := Elements{Version: readByte(dataInput)}
+	elementsCount := int(dataInput.ReadUint())
+	logger.Debug("reading elements", "version", elements.Version, "count", elementsCount)
+
+	elements.Elements = make(map[uint]GraphicalElement, min(max(elementsCount, 0), dataInput.Remaining()))
+	for i := 0; i < elementsCount && dataInput.Err() == nil; i++ {
+		// since version 9, elements are prefixed by their size, for the game
+		// to only read them when needed
+		elementEnd := -1
+		if elements.Version >= 9 {
+			elementSize := int(dataInput.ReadUnsignedShort())
+			elementEnd = dataInput.IndexPointer + elementSize
+		}
+
+		element, err := readGraphicalElement(dataInput, elements.Version)
+		if err != nil {
+			return Elements{}, err
+		}
+		elements.Elements[element.Id] = element
+
+		if elementEnd >= 0 && dataInput.IndexPointer != elementEnd {
+			err = dataInput.anomaly(fmt.Errorf("element %d ends at %#x instead of %#x", element.Id, dataInput.IndexPointer, elementEnd))
+			if err != nil {
+				return Elements{}, err
+			}
+			dataInput.SetPointer(elementEnd)
+		}
+	}
+
+	elements.JpgGfx = make([]int, 0)
+	if elements.Version >= 8 {
+		gfxCount := dataInput.ReadInt()
+		for i := 0; i < gfxCount && dataInput.Err() == nil; i++ {
+			elements.JpgGfx = append(elements.JpgGfx, dataInput.ReadInt())
+		}
+	}
+
+	if err := dataInput.Err(); err != nil {
+		return Elements{}, err
+	}
+
+	return elements, nil
+}
+
+func readGraphicalElement(dataInput *DataInput, version int) (GraphicalElement, error) {
+	element := GraphicalElement{
+		Id:          dataInput.ReadUint(),
+		ElementType: readByte(dataInput),
+	}
+
+	switch element.ElementType {
+	case NormalElementType, BoundingBoxElementType:
+		readNormalElement(dataInput, &element)
+	case AnimatedElementType:
+		readNormalElement(dataInput, &element)
+		if version == 4 {
+			element.MinDelay = dataInput.ReadInt()
+			element.MaxDelay = dataInput.ReadInt()
+		}
+	case EntityElementType:
+		element.EntityLook = string(dataInput.Read(dataInput.ReadInt()))
+		element.HorizontalSymmetry = dataInput.ReadBoolean()
+		if version >= 7 {
+			element.PlayAnimation = dataInput.ReadBoolean()
+		}
+		if version >= 6 {
+			element.PlayAnimationStatic = dataInput.ReadBoolean()
+		}
+		if version >= 5 {
+			element.MinDelay = dataInput.ReadInt()
+			element.MaxDelay = dataInput.ReadInt()
+		}
+	case ParticlesElementType:
+		element.ScriptId = readShort(dataInput)
+	case BlendedElementType:
+		readNormalElement(dataInput, &element)
+		element.BlendMode = string(dataInput.Read(dataInput.ReadInt()))
+	default:
+		return GraphicalElement{}, fmt.Errorf("unknown type %d for element %d at %s", element.ElementType, element.Id, dataInput.OffsetStr())
+	}
+
+	return element, nil
+}
+
+func readNormalElement(dataInput *DataInput, element *GraphicalElement) {
+	element.GfxId = dataInput.ReadInt()
+	element.Height = readByte(dataInput)
+	element.HorizontalSymmetry = dataInput.ReadBoolean()
+	element.OriginX = readShort(dataInput)
+	element.OriginY = readShort(dataInput)
+	element.SizeX = readShort(dataInput)
+	element.SizeY = readShort(dataInput)
+}
@@ -0,0 +1,30 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrInvalidHeader is returned when a file does not start with the header
+	// of its format, usually because it is not a file of this format.
+	ErrInvalidHeader = errors.New("invalid header")
+	// ErrTruncatedData is returned when reading past the end of the data of a
+	// corrupt or truncated file.
+	ErrTruncatedData = errors.New("unexpected end of data")
+	// ErrNestingTooDeep is returned when the vectors or objects of a corrupt
+	// file are nested deeper than any game data.
+	ErrNestingTooDeep = errors.New("data nested too deeply")
+)
+
+// ErrUnknownFieldType is returned when a D2O class field has a type id which
+// is not supported by the parser.
+type ErrUnknownFieldType struct {
+	Field  string
+	TypeID int
+	Offset int
+}
+
+func (e *ErrUnknownFieldType) Error() string {
+	return fmt.Sprintf("unknown type %d for field %s at %#x (%d)", e.TypeID, e.Field, e.Offset, e.Offset)
+}
@@ -0,0 +1,506 @@
+package parser_test
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/brequet/dofus-data-file-parser/pkg/parser"
+	"github.com/brequet/dofus-data-file-parser/pkg/writer"
+)
+
+// buildD2o builds a D2O file of objectCount Item objects, with a field of
+// each basic type, a vector and a nested Effect object. The objects are
+// written from the highest id, for the file order not to be the id order.
+func buildD2o(objectCount int) []byte {
+	const headerLength = 7 // "D2O" and the indexes pointer
+
+	objects := writer.NewDataOutput()
+	indexes := writer.NewDataOutput()
+	for id := objectCount; id >= 1; id-- {
+		indexes.WriteInt(id)
+		indexes.WriteInt(headerLength + objects.Len())
+
+		objects.WriteInt(1) // class id
+		objects.WriteInt(id)
+		objects.WriteUTF(fmt.Sprintf("item %d", id))
+		objects.WriteBoolean(id%2 == 0)
+		objects.WriteDouble(float64(id) / 2)
+		objects.WriteInt(3)
+		for i := range 3 {
+			objects.WriteInt(id + i)
+		}
+		objects.WriteInt(2) // class id of the effect
+		objects.WriteInt(id * 10)
+	}
+
+	file := writer.NewDataOutput()
+	file.Write([]byte("D2O"))
+	file.WriteInt(headerLength + objects.Len())
+	file.Write(objects.Bytes())
+	file.WriteInt(indexes.Len())
+	file.Write(indexes.Bytes())
+
+	file.WriteInt(2) // classes
+	file.WriteInt(1)
+	file.WriteUTF("Item")
+	file.WriteUTF("com.ankamagames.dofus.datacenter.items")
+	file.WriteInt(6)
+	writeField(file, "id", int(parser.Integer))
+	writeField(file, "name", int(parser.String))
+	writeField(file, "usable", int(parser.Boolean))
+	writeField(file, "weight", int(parser.Number))
+	writeField(file, "recipeIds", int(parser.Vector))
+	writeField(file, "", int(parser.Integer))
+	writeField(file, "effect", 2)
+	file.WriteInt(2)
+	file.WriteUTF("Effect")
+	file.WriteUTF("com.ankamagames.dofus.datacenter.effects")
+	file.WriteInt(1)
+	writeField(file, "value", int(parser.Integer))
+
+	if err := file.Err(); err != nil {
+		panic(err)
+	}
+	return file.Bytes()
+}
+
+func writeField(output *writer.DataOutput, name string, fieldType int) {
+	output.WriteUTF(name)
+	output.WriteInt(fieldType)
+}
+
+// buildD2i builds a D2I file of textCount texts, every other one with an
+// undiacritical variant, and of a named text.
+func buildD2i(textCount int) []byte {
+	const headerLength = 4 // the indexes pointer
+
+	data := writer.NewDataOutput()
+	indexes := writer.NewDataOutput()
+	for id := 1; id <= textCount; id++ {
+		diacriticExists := id%2 == 0
+		indexes.WriteInt(id)
+		indexes.WriteBoolean(diacriticExists)
+		indexes.WriteInt(headerLength + data.Len())
+		data.WriteUTF(fmt.Sprintf("Épée n°%d", id))
+		if diacriticExists {
+			indexes.WriteInt(headerLength + data.Len())
+			data.WriteUTF(fmt.Sprintf("Epee n°%d", id))
+		}
+	}
+
+	namedIndexes := writer.NewDataOutput()
+	namedIndexes.WriteUTF("ui.common.ok")
+	namedIndexes.WriteInt(headerLength + data.Len())
+	data.WriteUTF("OK")
+
+	file := writer.NewDataOutput()
+	file.WriteInt(headerLength + data.Len())
+	file.Write(data.Bytes())
+	file.WriteInt(indexes.Len())
+	file.Write(indexes.Bytes())
+	file.WriteInt(namedIndexes.Len())
+	file.Write(namedIndexes.Bytes())
+	file.WriteInt(textCount * 4)
+	for id := 1; id <= textCount; id++ {
+		file.WriteInt(id)
+	}
+
+	if err := file.Err(); err != nil {
+		panic(err)
+	}
+	return file.Bytes()
+}
+
+// buildDlm builds an uncompressed DLM map of a version from 9, encrypted with
+// parser.DefaultDlmKey or not, with a graphical and a sound element.
+func buildDlm(version int, encrypted bool) []byte {
+	content := writer.NewDataOutput()
+	content.WriteUint(0) // relative id
+	content.WriteSignedByte(0)
+	content.WriteInt(42) // subarea
+	for range 4 {
+		content.WriteInt(-1) // neighbours
+	}
+	content.WriteInt(0) // shadow bonus
+	content.WriteUint(0xFF000000)
+	content.WriteUint(0xFF101010)
+	content.WriteUnsignedShort(100)
+	content.WriteShort(0)
+	content.WriteShort(0)
+	if version > 10 {
+		content.WriteInt(0) // tactical mode template
+	}
+	content.WriteBoolean(false)
+	content.WriteBoolean(false)
+	content.WriteSignedByte(0) // background fixtures
+	content.WriteSignedByte(0) // foreground fixtures
+	content.WriteInt(0)
+	content.WriteInt(0) // ground crc
+
+	content.WriteSignedByte(1) // layers
+	content.WriteSignedByte(0)
+	content.WriteShort(1) // cells
+	content.WriteShort(300)
+	content.WriteShort(2) // elements
+	content.WriteSignedByte(2)
+	content.WriteUint(1234)
+	content.Write(make([]byte, 6)) // hue and shadow
+	content.WriteShort(10)
+	content.WriteShort(-5)
+	content.WriteSignedByte(0)
+	content.WriteUint(1)
+	content.WriteSignedByte(33)
+	content.WriteInt(77)
+	content.WriteShort(100)
+	content.WriteInt(1)
+	content.WriteInt(10)
+	content.WriteShort(0)
+	content.WriteShort(1000)
+
+	for cellId := range 560 {
+		content.WriteSignedByte(0) // floor
+		var bits int16
+		if cellId%7 == 0 {
+			bits |= 1 // not walkable
+		}
+		content.WriteShort(bits)
+		content.WriteSignedByte(0)   // speed
+		content.WriteUnsignedByte(0) // map change data
+		content.WriteUnsignedByte(0) // move zone
+		if version > 10 && bits&1 == 0 {
+			content.WriteUnsignedByte(0) // linked zone
+		}
+	}
+
+	data := content.Bytes()
+	if encrypted {
+		key := parser.DefaultDlmKey
+		for i := range data {
+			data[i] ^= key[i%len(key)]
+		}
+	}
+
+	file := writer.NewDataOutput()
+	file.WriteUnsignedByte('M')
+	file.WriteUnsignedByte(uint8(version))
+	file.WriteUint(153880322)
+	file.WriteBoolean(encrypted)
+	file.WriteUnsignedByte(1)
+	file.WriteInt(len(data))
+	file.Write(data)
+
+	if err := file.Err(); err != nil {
+		panic(err)
+	}
+	return file.Bytes()
+}
+
+// buildEle builds an uncompressed elements file of version 9, with an element
+// of each type.
+func buildEle() []byte {
+	elements := []*writer.DataOutput{}
+	for elementType := parser.NormalElementType; elementType <= parser.BlendedElementType; elementType++ {
+		element := writer.NewDataOutput()
+		element.WriteUint(uint(100 + elementType))
+		element.WriteSignedByte(int8(elementType))
+		switch elementType {
+		case parser.EntityElementType:
+			element.WriteInt(len("{1}"))
+			element.Write([]byte("{1}"))
+			element.WriteBoolean(false)
+			element.WriteBoolean(true)
+			element.WriteBoolean(false)
+			element.WriteInt(10)
+			element.WriteInt(20)
+		case parser.ParticlesElementType:
+			element.WriteShort(7)
+		default:
+			element.WriteInt(5000 + elementType)
+			element.WriteSignedByte(1)
+			element.WriteBoolean(false)
+			element.WriteShort(-43)
+			element.WriteShort(-21)
+			element.WriteShort(86)
+			element.WriteShort(43)
+			if elementType == parser.BlendedElementType {
+				element.WriteInt(len("add"))
+				element.Write([]byte("add"))
+			}
+		}
+		elements = append(elements, element)
+	}
+
+	file := writer.NewDataOutput()
+	file.WriteUnsignedByte('E')
+	file.WriteSignedByte(9)
+	file.WriteUint(uint(len(elements)))
+	for _, element := range elements {
+		file.WriteUnsignedShort(uint16(element.Len()))
+		file.Write(element.Bytes())
+	}
+	file.WriteInt(1) // jpg gfx
+	file.WriteInt(5000)
+
+	if err := file.Err(); err != nil {
+		panic(err)
+	}
+	return file.Bytes()
+}
+
+// buildSwl builds a SWF library of two classes, holding an empty
+// uncompressed SWF movie.
+func buildSwl() []byte {
+	swf := writer.NewDataOutput()
+	swf.Write([]byte("FWS"))
+	swf.WriteUnsignedByte(9)
+	// little endian file length, frame size (5 bits of size then 4 values of
+	// 1 bit), frame rate and frame count
+	swf.Write([]byte{17, 0, 0, 0, 0x08, 0x00, 0, 24, 3, 0, 0, 0, 0, 0, 0, 0, 0})
+
+	file := writer.NewDataOutput()
+	file.WriteUnsignedByte('L')
+	file.WriteUnsignedByte(1)
+	file.WriteUint(24)
+	file.WriteInt(2)
+	file.WriteUTF("Sprite_1")
+	file.WriteUTF("Sprite_2")
+	file.Write(swf.Bytes())
+
+	if err := file.Err(); err != nil {
+		panic(err)
+	}
+	return file.Bytes()
+}
+
+// unityBundleEntry is an entry of a bundle built by buildUnityBundle, whose
+// offset and length are those of its content unless set.
+type unityBundleEntry struct {
+	name           string
+	content        []byte
+	offset, length int64
+}
+
+// buildUnityBundle builds a UnityFS bundle of format version 7, whose blocks
+// info and single block are uncompressed.
+func buildUnityBundle(entries ...unityBundleEntry) []byte {
+	block := writer.NewDataOutput()
+	blocksInfo := writer.NewDataOutput()
+	blocksInfo.Write(make([]byte, 16)) // hash
+	for _, entry := range entries {
+		block.Write(entry.content)
+	}
+	blocksInfo.WriteInt(1)
+	blocksInfo.WriteUint(uint(block.Len()))
+	blocksInfo.WriteUint(uint(block.Len()))
+	blocksInfo.WriteUnsignedShort(0)
+	blocksInfo.WriteInt(len(entries))
+	var offset int64
+	for _, entry := range entries {
+		if entry.offset == 0 && entry.length == 0 {
+			entry.offset, entry.length = offset, int64(len(entry.content))
+		}
+		offset += int64(len(entry.content))
+		blocksInfo.WriteLong(entry.offset)
+		blocksInfo.WriteLong(entry.length)
+		blocksInfo.WriteInt(4)
+		blocksInfo.Write(append([]byte(entry.name), 0))
+	}
+
+	file := writer.NewDataOutput()
+	file.Write([]byte("UnityFS\x00"))
+	file.WriteInt(7)
+	file.Write([]byte("5.x.x\x002022.3.15f1\x00"))
+	file.WriteLong(0) // file size
+	file.WriteInt(blocksInfo.Len())
+	file.WriteInt(blocksInfo.Len())
+	file.WriteInt(0) // flags
+	file.Write(make([]byte, (16-file.Len()%16)%16))
+	file.Write(blocksInfo.Bytes())
+	file.Write(block.Bytes())
+
+	if err := file.Err(); err != nil {
+		panic(err)
+	}
+	return file.Bytes()
+}
+
+// unityNode is a node of a type tree built by buildUnitySerializedFile, whose
+// type and name are common strings when they start with "common:" followed by
+// their offset.
+type unityNode struct {
+	typ, name string
+	level     int
+	align     bool
+}
+
+// unityObject is an object of a serialized file built by
+// buildUnitySerializedFile, of the type of the given index.
+type unityObject struct {
+	pathID    int64
+	typeIndex int
+	data      []byte
+}
+
+type unityType struct {
+	classID int
+	nodes   []unityNode
+}
+
+// buildUnitySerializedFile builds a little endian serialized file of version
+// 22, with type trees.
+func buildUnitySerializedFile(types []unityType, objects []unityObject, externals []string) []byte {
+	le := binary.LittleEndian
+	metadata := []byte("2022.3.15f1\x00")
+	metadata = le.AppendUint32(metadata, 19) // platform
+	metadata = append(metadata, 1)           // type trees
+	metadata = le.AppendUint32(metadata, uint32(len(types)))
+	for _, typ := range types {
+		metadata = le.AppendUint32(metadata, uint32(typ.classID))
+		metadata = append(metadata, 0)          // stripped
+		metadata = le.AppendUint16(metadata, 0) // script type index
+		if typ.classID == 114 {
+			metadata = append(metadata, make([]byte, 16)...) // script id
+		}
+		metadata = append(metadata, make([]byte, 16)...) // old type hash
+
+		var records, stringBuffer []byte
+		stringOffset := func(str string) uint32 {
+			if offset, ok := strings.CutPrefix(str, "common:"); ok {
+				common, _ := strconv.Atoi(offset)
+				return 0x80000000 | uint32(common)
+			}
+			offset := uint32(len(stringBuffer))
+			stringBuffer = append(stringBuffer, str+"\x00"...)
+			return offset
+		}
+		for i, node := range typ.nodes {
+			metaFlag := uint32(0)
+			if node.align {
+				metaFlag = 0x4000
+			}
+			records = le.AppendUint16(records, 1)
+			records = append(records, byte(node.level), 0)
+			records = le.AppendUint32(records, stringOffset(node.typ))
+			records = le.AppendUint32(records, stringOffset(node.name))
+			records = le.AppendUint32(records, 0xFFFFFFFF) // byte size
+			records = le.AppendUint32(records, uint32(i))
+			records = le.AppendUint32(records, metaFlag)
+			records = le.AppendUint64(records, 0) // referenced type hash
+		}
+		metadata = le.AppendUint32(metadata, uint32(len(typ.nodes)))
+		metadata = le.AppendUint32(metadata, uint32(len(stringBuffer)))
+		metadata = append(metadata, records...)
+		metadata = append(metadata, stringBuffer...)
+		metadata = le.AppendUint32(metadata, 0) // type dependencies
+	}
+
+	const headerLength = 48
+	var data []byte
+	metadata = le.AppendUint32(metadata, uint32(len(objects)))
+	for _, object := range objects {
+		metadata = append(metadata, make([]byte, (4-(headerLength+len(metadata))%4)%4)...)
+		metadata = le.AppendUint64(metadata, uint64(object.pathID))
+		metadata = le.AppendUint64(metadata, uint64(len(data)))
+		metadata = le.AppendUint32(metadata, uint32(len(object.data)))
+		metadata = le.AppendUint32(metadata, uint32(object.typeIndex))
+		data = append(data, object.data...)
+		data = append(data, make([]byte, (8-len(data)%8)%8)...)
+	}
+	metadata = le.AppendUint32(metadata, 0) // scripts
+	metadata = le.AppendUint32(metadata, uint32(len(externals)))
+	for _, external := range externals {
+		metadata = append(metadata, 0)
+		metadata = append(metadata, make([]byte, 16)...) // guid
+		metadata = le.AppendUint32(metadata, 0)
+		metadata = append(metadata, external+"\x00"...)
+	}
+
+	dataOffset := (headerLength + len(metadata) + 15) / 16 * 16
+	be := binary.BigEndian
+	file := be.AppendUint32(nil, 0) // metadata size
+	file = be.AppendUint32(file, 0) // file size
+	file = be.AppendUint32(file, 22)
+	file = be.AppendUint32(file, 0)
+	file = append(file, 0, 0, 0, 0) // little endian
+	file = be.AppendUint32(file, uint32(len(metadata)))
+	file = be.AppendUint64(file, uint64(dataOffset+len(data)))
+	file = be.AppendUint64(file, uint64(dataOffset))
+	file = be.AppendUint64(file, 0)
+	file = append(file, metadata...)
+	file = append(file, make([]byte, dataOffset-len(file))...)
+	return append(file, data...)
+}
+
+// The common string offsets of m_Name, string and int.
+const (
+	unityCommonName   = "common:427"
+	unityCommonString = "common:840"
+	unityCommonInt    = "common:222"
+)
+
+// unityScriptsFile is a serialized file holding the MonoScript of path id 5
+// of the class Items.
+func unityScriptsFile() []byte {
+	le := binary.LittleEndian
+	script := le.AppendUint32(nil, 5)
+	script = append(script, "items\x00\x00\x00"...)
+	script = le.AppendUint32(script, 5)
+	script = append(script, "Items\x00\x00\x00"...)
+
+	monoScript := unityType{classID: 115, nodes: []unityNode{
+		{typ: "MonoScript", name: "Base"},
+		{typ: unityCommonString, name: unityCommonName, level: 1},
+		{typ: unityCommonString, name: "m_ClassName", level: 1},
+		{typ: "Array", name: "Array", level: 2, align: true},
+		{typ: unityCommonInt, name: "size", level: 3},
+		{typ: "char", name: "data", level: 3},
+	}}
+	return buildUnitySerializedFile([]unityType{monoScript}, []unityObject{{pathID: 5, data: script}}, nil)
+}
+
+// unityItemsFile is a serialized file of count MonoBehaviours of the class of
+// the MonoScript of unityScriptsFile, with an aligned string, a vector and an
+// unaligned bool.
+func unityItemsFile(count int) []byte {
+	monoBehaviour := unityType{classID: 114, nodes: []unityNode{
+		{typ: "MonoBehaviour", name: "Base"},
+		{typ: "PPtr<GameObject>", name: "m_GameObject", level: 1},
+		{typ: unityCommonInt, name: "m_FileID", level: 2},
+		{typ: "SInt64", name: "m_PathID", level: 2},
+		{typ: "UInt8", name: "m_Enabled", level: 1, align: true},
+		{typ: "PPtr<MonoScript>", name: "m_Script", level: 1},
+		{typ: unityCommonInt, name: "m_FileID", level: 2},
+		{typ: "SInt64", name: "m_PathID", level: 2},
+		{typ: unityCommonString, name: unityCommonName, level: 1},
+		{typ: unityCommonInt, name: "id", level: 1},
+		{typ: "vector", name: "ids", level: 1},
+		{typ: "Array", name: "Array", level: 2, align: true},
+		{typ: unityCommonInt, name: "size", level: 3},
+		{typ: unityCommonInt, name: "data", level: 3},
+		{typ: "bool", name: "visible", level: 1},
+		{typ: "SInt16", name: "order", level: 1},
+	}}
+
+	le := binary.LittleEndian
+	var objects []unityObject
+	for id := 1; id <= count; id++ {
+		data := make([]byte, 12)        // m_GameObject
+		data = append(data, 1, 0, 0, 0) // m_Enabled
+		data = le.AppendUint32(data, 1) // m_Script, in the first external
+		data = le.AppendUint64(data, 5)
+		name := fmt.Sprintf("item %d", id)
+		data = le.AppendUint32(data, uint32(len(name)))
+		data = append(data, name...)
+		data = append(data, make([]byte, (4-len(name)%4)%4)...)
+		data = le.AppendUint32(data, uint32(id))
+		data = le.AppendUint32(data, 2)
+		data = le.AppendUint32(data, uint32(id*10))
+		data = le.AppendUint32(data, uint32(id*10+1))
+		data = append(data, byte(id%2))
+		data = le.AppendUint16(data, uint16(id))
+		objects = append(objects, unityObject{pathID: int64(100 + id), data: data})
+	}
+	return buildUnitySerializedFile([]unityType{monoBehaviour}, objects, []string{"archive:/CAB-scripts/CAB-scripts"})
+}
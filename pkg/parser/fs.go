@@ -0,0 +1,99 @@
+package parser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"slices"
+	"strings"
+)
+
+// Data is the content of a Dofus data folder.
+type Data struct {
+	Modules      map[string]D2oData // module name (e.g. "Items") -> objects of common/<module>.d2o
+	Translations map[string]D2iData // locale -> texts of i18n/i18n_<locale>.d2i
+}
+
+// ParseData parses the D2O files of the common folder and the D2I files of
+// the i18n folder of a data folder, see ParseDataFS.
+func ParseData(dataFolderPath string, opts ...ParseOptions) (Data, error) {
+	return ParseDataFS(os.DirFS(dataFolderPath), opts...)
+}
+
+// ParseDataFS is like ParseData but reads the data folder from fsys, its
+// root, to parse data which is not on the local filesystem: embedded
+// fixtures, zip archives (zip.Reader), remote filesystems... The common folder
+// is required, the i18n folder is not.
+func ParseDataFS(fsys fs.FS, opts ...ParseOptions) (Data, error) {
+	data := Data{Modules: map[string]D2oData{}, Translations: map[string]D2iData{}}
+
+	d2oFileNames, err := fsFileNames(fsys, "common", ".d2o")
+	if err != nil {
+		return Data{}, fmt.Errorf("error reading common folder: %w", err)
+	}
+	d2iFileNames, err := fsFileNames(fsys, "i18n", ".d2i")
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return Data{}, fmt.Errorf("error reading i18n folder: %w", err)
+	}
+	d2iFileNames = slices.DeleteFunc(d2iFileNames, func(fileName string) bool {
+		return !strings.HasPrefix(fileName, "i18n_")
+	})
+
+	progress := getParseOptions(opts).progress()
+	progress.FilesDiscovered(len(d2oFileNames) + len(d2iFileNames))
+	for _, fileName := range d2oFileNames {
+		filePath := path.Join("common", fileName)
+		content, err := fs.ReadFile(fsys, filePath)
+		if err != nil {
+			return Data{}, fmt.Errorf("error reading %s: %w", filePath, err)
+		}
+		// the content is decoded in place, as ParseD2o would copy it
+		reader, err := newD2oReader(content, getParseOptions(opts))
+		if err != nil {
+			return Data{}, fmt.Errorf("error parsing %s: %w", filePath, err)
+		}
+		d2oData, err := readAllObjects(context.Background(), reader)
+		if err != nil {
+			return Data{}, fmt.Errorf("error parsing %s: %w", filePath, err)
+		}
+		data.Modules[strings.TrimSuffix(fileName, ".d2o")] = d2oData
+		progress.FileParsed(filePath)
+	}
+
+	for _, fileName := range d2iFileNames {
+		locale := strings.TrimPrefix(strings.TrimSuffix(fileName, ".d2i"), "i18n_")
+		filePath := path.Join("i18n", fileName)
+		content, err := fs.ReadFile(fsys, filePath)
+		if err != nil {
+			return Data{}, fmt.Errorf("error reading %s: %w", filePath, err)
+		}
+		d2iData, err := ParseD2i(content, opts...)
+		if err != nil {
+			return Data{}, fmt.Errorf("error parsing %s: %w", filePath, err)
+		}
+		data.Translations[locale] = d2iData
+		progress.FileParsed(filePath)
+	}
+
+	return data, nil
+}
+
+// fsFileNames returns the names of the files of a folder of fsys having the
+// given extension, in alphabetical order.
+func fsFileNames(fsys fs.FS, folderPath, extension string) ([]string, error) {
+	entries, err := fs.ReadDir(fsys, folderPath)
+	if err != nil {
+		return nil, err
+	}
+
+	fileNames := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() && path.Ext(entry.Name()) == extension {
+			fileNames = append(fileNames, entry.Name())
+		}
+	}
+	return fileNames, nil
+}
@@ -0,0 +1,128 @@
+package parser_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/brequet/dofus-data-file-parser/pkg/parser"
+)
+
+// The fuzz targets only check that no input makes the parsers panic, malformed
+// files being reported as errors.
+
+func FuzzParseD2o(f *testing.F) {
+	f.Add(buildD2o(3))
+	f.Add(buildD2o(0))
+	f.Add([]byte("D2O"))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		for _, strict := range []bool{false, true} {
+			reader, err := parser.NewD2oReaderFrom(bytes.NewReader(data), parser.ParseOptions{Strict: strict})
+			if err != nil {
+				continue
+			}
+			_, _ = reader.ReadAll()
+			for range reader.Objects() {
+			}
+		}
+	})
+}
+
+func FuzzParseD2i(f *testing.F) {
+	f.Add(buildD2i(3))
+	f.Add(buildD2i(0))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		_, _ = parser.ParseD2i(data)
+	})
+}
+
+func FuzzParseDlm(f *testing.F) {
+	f.Add(buildDlm(9, false))
+	f.Add(buildDlm(11, true))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		_, _ = parser.ParseDlm(data, parser.DefaultDlmKey)
+	})
+}
+
+func FuzzParseEle(f *testing.F) {
+	f.Add(buildEle())
+	f.Fuzz(func(t *testing.T, data []byte) {
+		for _, strict := range []bool{false, true} {
+			_, _ = parser.ParseEle(data, parser.ParseOptions{Strict: strict})
+		}
+	})
+}
+
+func FuzzParseSwl(f *testing.F) {
+	f.Add(buildSwl())
+	f.Fuzz(func(t *testing.T, data []byte) {
+		_, _ = parser.ParseSwl(data)
+	})
+}
+
+func FuzzParseUnityBundle(f *testing.F) {
+	f.Add(buildUnityBundle(unityBundleEntry{name: "CAB-items", content: []byte("serialized file")}))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		bundle, err := parser.ParseUnityBundle(data)
+		if err != nil {
+			return
+		}
+		for _, entry := range bundle.Entries {
+			bundle.Read(entry)
+		}
+	})
+}
+
+func FuzzParseUnitySerializedFile(f *testing.F) {
+	f.Add(unityItemsFile(2))
+	f.Add(unityScriptsFile())
+	f.Fuzz(func(t *testing.T, data []byte) {
+		file, err := parser.ParseUnitySerializedFile(data)
+		if err != nil {
+			return
+		}
+		for _, object := range file.Objects {
+			_, _ = file.ReadObject(object)
+		}
+	})
+}
+
+// TestSeeds checks that the seeds of the fuzz targets are valid files, for the
+// fuzzing to start from the paths of well-formed data.
+func TestSeeds(t *testing.T) {
+	d2o, err := parser.ParseD2o(bytes.NewReader(buildD2o(3)), parser.ParseOptions{Strict: true})
+	if err != nil || len(d2o.Objects) != 3 {
+		t.Errorf("ParseD2o: %d objects, error %v", len(d2o.Objects), err)
+	}
+
+	d2i, err := parser.ParseD2i(buildD2i(3))
+	if err != nil || len(d2i.Texts) != 3 || d2i.NamedTexts["ui.common.ok"] != "OK" || d2i.Texts[2].UndiacriticalText != "Epee n°2" {
+		t.Errorf("ParseD2i: %+v, error %v", d2i, err)
+	}
+
+	for _, encrypted := range []bool{false, true} {
+		dlm, err := parser.ParseDlm(buildDlm(11, encrypted), parser.DefaultDlmKey)
+		if err != nil || len(dlm.Cells) != 560 || len(dlm.Layers) != 1 || len(dlm.Layers[0].Cells[0].Elements) != 2 {
+			t.Errorf("ParseDlm (encrypted %t): error %v", encrypted, err)
+		}
+	}
+
+	ele, err := parser.ParseEle(buildEle(), parser.ParseOptions{Strict: true})
+	if err != nil || len(ele.Elements) != 6 {
+		t.Errorf("ParseEle: %d elements, error %v", len(ele.Elements), err)
+	}
+
+	swl, err := parser.ParseSwl(buildSwl())
+	if err != nil || len(swl.Classes) != 2 || swl.FrameCount != 3 {
+		t.Errorf("ParseSwl: %+v, error %v", swl, err)
+	}
+
+	bundle, err := parser.ParseUnityBundle(buildUnityBundle(unityBundleEntry{name: "CAB-items", content: []byte("serialized file")}))
+	if err != nil || len(bundle.Entries) != 1 || string(bundle.Read(bundle.Entries[0])) != "serialized file" {
+		t.Errorf("ParseUnityBundle: %+v, error %v", bundle, err)
+	}
+
+	serializedFile, err := parser.ParseUnitySerializedFile(unityItemsFile(2))
+	if err != nil || len(serializedFile.Objects) != 2 {
+		t.Errorf("ParseUnitySerializedFile: %+v, error %v", serializedFile, err)
+	}
+}
@@ -0,0 +1,238 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+type JSONWriteOptions struct {
+	Pretty            bool
+	Indent            string // defaults to two spaces
+	StripClassType    bool
+	OmitEmptyVectors  bool
+	EmbedTranslations Translations // adds a "<field>Text" entry next to each I18n field
+	IncludeObjectIds  bool         // adds an "ObjectId_" entry holding the id of the index table
+	Enrichers         []Enricher   // add entries to the objects, nested ones included
+}
+
+// Enricher returns the entries to add to an object when exporting it, after
+// its fields, or nil to leave it as is.
+type Enricher func(object Object) map[string]any
+
+// WriteJSON writes the classes and objects as a JSON document.
+func (d D2oData) WriteJSON(w io.Writer, opts JSONWriteOptions) error {
+	output := d
+	output.Objects = d.orderedObjects(opts)
+
+	var jsonStr []byte
+	var err error
+	if opts.Pretty {
+		indent := opts.Indent
+		if indent == "" {
+			indent = "  "
+		}
+		jsonStr, err = json.MarshalIndent(output, "", indent)
+	} else {
+		jsonStr, err = json.Marshal(output)
+	}
+	if err != nil {
+		return fmt.Errorf("error marshalling json: %w", err)
+	}
+
+	_, err = w.Write(jsonStr)
+	if err != nil {
+		return fmt.Errorf("error writing json: %w", err)
+	}
+
+	return nil
+}
+
+// orderedObjects returns the objects transformed according to opts, as
+// orderedObject values marshalling their fields in class definition order.
+func (d D2oData) orderedObjects(opts JSONWriteOptions) []Object {
+	transformer := newObjectTransformer(d.Classes, opts)
+	objectIds := d.objectIdsByPosition()
+
+	objects := make([]Object, 0, len(d.Objects))
+	for position, object := range d.Objects {
+		id, hasId := objectIds[position]
+		objects = append(objects, transformer.transform(object, id, hasId))
+	}
+	return objects
+}
+
+// objectIdsByPosition returns the ids of the objects by their position in
+// Objects.
+func (d D2oData) objectIdsByPosition() map[int]int {
+	objectIds := make(map[int]int, len(d.objectPositions))
+	for id, position := range d.objectPositions {
+		objectIds[position] = id
+	}
+	return objectIds
+}
+
+type objectTransformer struct {
+	classesByName map[string]Class
+	opts          JSONWriteOptions
+}
+
+func newObjectTransformer(classes map[int]Class, opts JSONWriteOptions) objectTransformer {
+	classesByName := map[string]Class{}
+	for _, class := range classes {
+		classesByName[class.PackageClass] = class
+	}
+	return objectTransformer{classesByName: classesByName, opts: opts}
+}
+
+// transform transforms a top level object, adding its id when hasId is set.
+func (t objectTransformer) transform(object Object, id int, hasId bool) Object {
+	transformed := transformObject(object, t.classesByName, t.opts)
+	if ordered, ok := transformed.(*orderedObject); ok && hasId && t.opts.IncludeObjectIds {
+		ordered.fields = append([]orderedField{{"ObjectId_", id}}, ordered.fields...)
+	}
+	return transformed
+}
+
+func transformObject(object Object, classesByName map[string]Class, opts JSONWriteOptions) Object {
+	objectMap, ok := object.(map[string]any)
+	if !ok {
+		return object
+	}
+
+	className, _ := objectMap["ClassType_"].(string)
+	class := classesByName[className]
+
+	transformed := &orderedObject{fields: make([]orderedField, 0, len(objectMap)+1)}
+	if classType, ok := objectMap["ClassType_"]; ok && !opts.StripClassType {
+		transformed.set("ClassType_", classType)
+	}
+
+	knownFields := map[string]bool{"ClassType_": true}
+	for _, field := range class.Fields {
+		knownFields[field.Name] = true
+		value, ok := objectMap[field.Name]
+		if !ok {
+			continue
+		}
+
+		if opts.OmitEmptyVectors && field.Type == Vector {
+			if vector, ok := value.([]any); ok && len(vector) == 0 {
+				continue
+			}
+		}
+
+		transformed.set(field.Name, transformValue(value, field, classesByName, opts))
+		if opts.EmbedTranslations != nil && isI18nField(field) {
+			transformed.set(field.Name+"Text", translateValue(value, opts.EmbedTranslations))
+		}
+	}
+
+	// entries not described by the class, e.g. when the class is unknown
+	extraKeys := make([]string, 0)
+	for key := range objectMap {
+		if !knownFields[key] {
+			extraKeys = append(extraKeys, key)
+		}
+	}
+	sort.Strings(extraKeys)
+	for _, key := range extraKeys {
+		transformed.set(key, objectMap[key])
+	}
+
+	for _, enrich := range opts.Enrichers {
+		entries := enrich(objectMap)
+		keys := make([]string, 0, len(entries))
+		for key := range entries {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			transformed.set(key, entries[key])
+		}
+	}
+
+	return transformed
+}
+
+func transformValue(value any, field GameDataField, classesByName map[string]Class, opts JSONWriteOptions) any {
+	switch v := value.(type) {
+	case map[string]any:
+		return transformObject(v, classesByName, opts)
+	case []any:
+		if field.SubType == nil {
+			return v
+		}
+		vector := make([]any, 0, len(v))
+		for _, item := range v {
+			vector = append(vector, transformValue(item, *field.SubType, classesByName, opts))
+		}
+		return vector
+	default:
+		return v
+	}
+}
+
+func isI18nField(field GameDataField) bool {
+	if field.Type == Vector {
+		return isI18nField(*field.SubType)
+	}
+	return field.Type == I18n
+}
+
+func translateValue(value any, translations Translations) any {
+	switch v := value.(type) {
+	case int:
+		return translations[v]
+	case []any:
+		texts := make([]any, 0, len(v))
+		for _, item := range v {
+			texts = append(texts, translateValue(item, translations))
+		}
+		return texts
+	default:
+		return nil
+	}
+}
+
+// orderedObject is an object whose fields are marshalled in insertion order,
+// unlike maps whose keys encoding/json sorts.
+type orderedObject struct {
+	fields []orderedField
+}
+
+type orderedField struct {
+	key   string
+	value any
+}
+
+func (o *orderedObject) set(key string, value any) {
+	o.fields = append(o.fields, orderedField{key, value})
+}
+
+func (o *orderedObject) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteByte('{')
+	for i, field := range o.fields {
+		if i > 0 {
+			buffer.WriteByte(',')
+		}
+
+		key, err := json.Marshal(field.key)
+		if err != nil {
+			return nil, err
+		}
+		buffer.Write(key)
+		buffer.WriteByte(':')
+
+		value, err := json.Marshal(field.value)
+		if err != nil {
+			return nil, fmt.Errorf("error marshalling field %s: %w", field.key, err)
+		}
+		buffer.Write(value)
+	}
+	buffer.WriteByte('}')
+	return buffer.Bytes(), nil
+}
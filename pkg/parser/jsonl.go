@@ -0,0 +1,60 @@
+package parser
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ToJSONL writes the objects as JSON Lines, one object per line, with their
+// fields in class definition order.
+func (d D2oData) ToJSONL(w io.Writer) error {
+	return d.WriteJSONL(w, JSONWriteOptions{})
+}
+
+// WriteJSONL is like ToJSONL but transforms the objects as WriteJSON does.
+// Pretty and Indent are ignored, each object being written on a single line.
+// The objects are transformed one at a time, as they are written.
+func (d D2oData) WriteJSONL(w io.Writer, opts JSONWriteOptions) error {
+	var objectIds map[int]int // only needed to write the ids
+	if opts.IncludeObjectIds {
+		objectIds = d.objectIdsByPosition()
+	}
+
+	encoder := json.NewEncoder(w)
+	transformer := newObjectTransformer(d.Classes, opts)
+	for position, object := range d.Objects {
+		id, hasId := objectIds[position]
+		err := encoder.Encode(transformer.transform(object, id, hasId))
+		if err != nil {
+			return fmt.Errorf("error encoding object %d: %w", position, err)
+		}
+	}
+
+	return nil
+}
+
+// FromJSONL reads objects from a JSON Lines stream. The class table is not part
+// of the stream, so the returned data has no classes.
+func FromJSONL(r io.Reader) (D2oData, error) {
+	objects := make([]Object, 0)
+
+	decoder := json.NewDecoder(r)
+	for {
+		var object map[string]any
+		err := decoder.Decode(&object)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return D2oData{}, fmt.Errorf("error decoding object %d: %w", len(objects), err)
+		}
+		objects = append(objects, object)
+	}
+
+	return D2oData{
+		Classes: map[int]Class{},
+		Objects: objects,
+	}, nil
+}
@@ -0,0 +1,70 @@
+package parser
+
+import "fmt"
+
+// decodeLZ4Block decompresses an LZ4 block, without the frame format, into a
+// buffer of the given decompressed size.
+func decodeLZ4Block(data []byte, size int) ([]byte, error) {
+	// the size comes from the file: the preallocation is bounded by the
+	// highest compression ratio of LZ4
+	output := make([]byte, 0, min(size, 255*len(data)))
+	for position := 0; position < len(data); {
+		token := data[position]
+		position++
+
+		literalLength, n := readLZ4Length(data[position:], int(token>>4))
+		if n < 0 {
+			return nil, fmt.Errorf("%w: lz4 literal length at %#x", ErrTruncatedData, position)
+		}
+		position += n
+		if position+literalLength > len(data) || len(output)+literalLength > size {
+			return nil, fmt.Errorf("%w: lz4 literals of %d bytes at %#x", ErrTruncatedData, literalLength, position)
+		}
+		output = append(output, data[position:position+literalLength]...)
+		position += literalLength
+		if position == len(data) {
+			break // the last sequence has no match
+		}
+
+		if position+2 > len(data) {
+			return nil, fmt.Errorf("%w: lz4 match offset at %#x", ErrTruncatedData, position)
+		}
+		offset := int(data[position]) | int(data[position+1])<<8
+		position += 2
+		matchLength, n := readLZ4Length(data[position:], int(token&0x0F))
+		if n < 0 {
+			return nil, fmt.Errorf("%w: lz4 match length at %#x", ErrTruncatedData, position)
+		}
+		position += n
+		matchLength += 4
+		if offset == 0 || offset > len(output) || len(output)+matchLength > size {
+			return nil, fmt.Errorf("%w: lz4 match of %d bytes at offset %d", ErrTruncatedData, matchLength, offset)
+		}
+		// byte by byte, as the match may overlap the bytes it copies
+		start := len(output) - offset
+		for i := 0; i < matchLength; i++ {
+			output = append(output, output[start+i])
+		}
+	}
+
+	if len(output) != size {
+		return nil, fmt.Errorf("%w: lz4 block of %d bytes instead of %d", ErrTruncatedData, len(output), size)
+	}
+	return output, nil
+}
+
+// readLZ4Length reads the extra bytes of a length whose 4 bits of the token
+// are all set, returning the length and the number of bytes read, -1 when
+// truncated.
+func readLZ4Length(data []byte, length int) (int, int) {
+	if length != 0x0F {
+		return length, 0
+	}
+	for n := 0; n < len(data); n++ {
+		length += int(data[n])
+		if data[n] != 0xFF {
+			return length, n + 1
+		}
+	}
+	return 0, -1
+}
@@ -0,0 +1,12 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd) || nommap
+
+package parser
+
+import "os"
+
+// mapFile reads the content of a file, on the platforms where it is not mapped
+// in memory or when building with the nommap tag.
+func mapFile(filePath string) ([]byte, func() error, error) {
+	data, err := os.ReadFile(filePath)
+	return data, noUnmap, err
+}
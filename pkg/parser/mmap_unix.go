@@ -0,0 +1,41 @@
+//go:build (linux || darwin || freebsd || netbsd || openbsd) && !nommap
+
+package parser
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"syscall"
+)
+
+// mapFile maps the content of a file in memory, read only, so that large
+// modules are paged in from the file as they are read instead of being copied
+// to the heap. The returned function unmaps the content, which must not be
+// used afterwards. Decoded values never reference it, strings being copied.
+func mapFile(filePath string) ([]byte, func() error, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, nil, err
+	}
+	if info.Size() == 0 {
+		return []byte{}, noUnmap, nil
+	}
+	if info.Size() > math.MaxInt {
+		return nil, nil, fmt.Errorf("file too large: %d bytes", info.Size())
+	}
+
+	data, err := syscall.Mmap(int(file.Fd()), 0, int(info.Size()), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		// file systems like some network ones do not support it
+		data, err = os.ReadFile(filePath)
+		return data, noUnmap, err
+	}
+	return data, func() error { return syscall.Munmap(data) }, nil
+}
@@ -0,0 +1,79 @@
+package parser
+
+import (
+	"context"
+	"log/slog"
+)
+
+// ParseOptions configures the parsing functions, which take them as an
+// optional last argument.
+type ParseOptions struct {
+	// Logger receives the parsing logs, which are discarded when nil.
+	Logger *slog.Logger
+	// Progress is notified of the progress of the parsing, when not nil.
+	Progress Progress
+	// Strict fails the parsing on any anomaly of the data: unknown field
+	// types, objects not ending where the next one starts, index pointers or
+	// search table entries out of bounds... By default, anomalies are logged
+	// as warnings and the objects which cannot be decoded are skipped.
+	Strict bool
+	// Workers is the number of goroutines decoding the objects of a D2O file
+	// at once, each reading its own view of the file content, which speeds up
+	// the biggest modules. Objects are decoded one at a time when it is 0 or 1.
+	Workers int
+}
+
+// Progress receives the progress of the parsing, to give feedback on long
+// runs. Its methods may be called from several goroutines at once.
+type Progress interface {
+	// FilesDiscovered is called with the number of files found in a
+	// directory, before they are parsed.
+	FilesDiscovered(count int)
+	// FileParsed is called once a file of a directory is parsed.
+	FileParsed(path string)
+	// BytesRead is called with the size of each file read.
+	BytesRead(count int64)
+	// ObjectsDecoded is called with the number of objects decoded.
+	ObjectsDecoded(count int)
+}
+
+type noProgress struct{}
+
+func (noProgress) FilesDiscovered(int) {}
+func (noProgress) FileParsed(string)   {}
+func (noProgress) BytesRead(int64)     {}
+func (noProgress) ObjectsDecoded(int)  {}
+
+var discardLogger = slog.New(discardHandler{})
+
+// noUnmap is the unmap function of file contents which are not mapped.
+func noUnmap() error { return nil }
+
+// getParseOptions returns the options given to a parsing function, if any.
+func getParseOptions(opts []ParseOptions) ParseOptions {
+	if len(opts) == 0 {
+		return ParseOptions{}
+	}
+	return opts[0]
+}
+
+func (o ParseOptions) logger() *slog.Logger {
+	if o.Logger == nil {
+		return discardLogger
+	}
+	return o.Logger
+}
+
+func (o ParseOptions) progress() Progress {
+	if o.Progress == nil {
+		return noProgress{}
+	}
+	return o.Progress
+}
+
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (h discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return h }
+func (h discardHandler) WithGroup(string) slog.Handler           { return h }
@@ -0,0 +1,73 @@
+package parser
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+type decodedObject struct {
+	object  Object
+	warning *ObjectWarning // set when the object is skipped
+}
+
+// decodeObjects decodes the objects with the given ids, in the same order. The
+// objects being independent, they are decoded from up to r.workers goroutines,
+// each reading its own view of the file content.
+func (r *D2oReader) decodeObjects(ctx context.Context, objectIds []int) ([]decodedObject, error) {
+	decodedObjects := make([]decodedObject, len(objectIds))
+	decode := func(dataInput *DataInput, i int) error {
+		object, warning, err := r.readObjectOrSkip(dataInput, objectIds[i])
+		if err != nil {
+			return err
+		}
+		if warning == nil {
+			r.progress.ObjectsDecoded(1)
+		}
+		decodedObjects[i] = decodedObject{object: object, warning: warning}
+		return nil
+	}
+
+	workers := min(max(r.workers, 1), len(objectIds))
+	if workers <= 1 {
+		for i := range objectIds {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			if err := decode(r.dataInput, i); err != nil {
+				return nil, err
+			}
+		}
+		return decodedObjects, nil
+	}
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	var next atomic.Int64
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			dataInput := r.dataInput.view()
+			for ctx.Err() == nil {
+				i := int(next.Add(1)) - 1
+				if i >= len(objectIds) {
+					return
+				}
+				if err := decode(dataInput, i); err != nil {
+					cancel(err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	// the error of a worker, or of the context given by the caller
+	if err := context.Cause(ctx); err != nil {
+		return nil, err
+	}
+	return decodedObjects, nil
+}
@@ -0,0 +1,317 @@
+package parser
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"iter"
+	"maps"
+	"math"
+	"slices"
+	"sort"
+	"sync"
+)
+
+// D2oReader gives access to the objects of a D2O file, decoding them on
+// demand. It is not safe for concurrent use, even though ReadAll may decode
+// the objects from several goroutines (see ParseOptions.Workers).
+type D2oReader struct {
+	dataInput   *DataInput
+	indexTable  map[int]int // object id -> pointer
+	classTable  map[int]Class
+	searchIndex SearchIndex
+	progress    Progress
+	// objectEnds maps the pointer of each object to the one of the next
+	// object, or of the indexes for the last one, where it should end.
+	objectEnds map[int]int
+	strict     bool
+	unmap      func() error
+	workers    int
+	objectsErr error // error which stopped the last Objects iteration
+
+	warningsMutex sync.Mutex
+	warnings      map[int]ObjectWarning // object id -> warning
+}
+
+// NewD2oReader reads the index, class and search tables of a D2O file without
+// decoding its objects. The file is mapped in memory where supported, until
+// the reader is closed.
+func NewD2oReader(d2oFilePath string, opts ...ParseOptions) (*D2oReader, error) {
+	// See GameDataFileAccessor.as
+	options := getParseOptions(opts)
+	options.logger().Debug("processing D2O file", "file", d2oFilePath)
+
+	fileContentBytes, unmap, err := mapFile(d2oFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading file: %w", err)
+	}
+
+	reader, err := newD2oReader(fileContentBytes, options)
+	if err != nil {
+		unmap()
+		return nil, err
+	}
+	reader.unmap = unmap
+	return reader, nil
+}
+
+// NewD2oReaderFrom is like NewD2oReader but reads the D2O content from r, to
+// parse data which does not come from a file (embedded data, archives...).
+func NewD2oReaderFrom(r io.ReaderAt, opts ...ParseOptions) (*D2oReader, error) {
+	data, err := readAllAt(r)
+	if err != nil {
+		return nil, fmt.Errorf("error reading data: %w", err)
+	}
+
+	return newD2oReader(data, getParseOptions(opts))
+}
+
+func newD2oReader(data []byte, options ParseOptions) (*D2oReader, error) {
+	logger := options.logger()
+	options.progress().BytesRead(int64(len(data)))
+	dataInput := newDataInput(data, logger)
+	dataInput.strict = options.Strict
+	header := string(dataInput.Read(3))
+	if header != "D2O" {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidHeader, header)
+	}
+
+	indexesPointer := dataInput.ReadInt()
+	dataInput.SetPointer(indexesPointer)
+	logger.Debug("indexes pointer", "pointer", indexesPointer)
+
+	indexTable := make(map[int]int)
+	indexesSize := dataInput.ReadInt()
+	if indexesSize%8 != 0 && dataInput.Err() == nil {
+		if err := dataInput.anomaly(fmt.Errorf("indexes size %d is not a multiple of 8", indexesSize)); err != nil {
+			return nil, err
+		}
+	}
+	indexesLength := indexesSize / 8
+	logger.Debug("indexes length", "length", indexesLength)
+	for i := 0; i < indexesLength && dataInput.Err() == nil; i++ {
+		key := dataInput.ReadInt()
+		pointer := dataInput.ReadInt()
+		if (pointer < 7 || pointer >= indexesPointer) && dataInput.Err() == nil {
+			if err := dataInput.anomaly(fmt.Errorf("pointer %#x of object %d out of the objects", pointer, key)); err != nil {
+				return nil, err
+			}
+		}
+		indexTable[key] = pointer
+	}
+
+	classTable := make(map[int]Class)
+	classCount := dataInput.ReadInt()
+	logger.Debug("class count", "count", classCount)
+	for i := 0; i < classCount && dataInput.Err() == nil; i++ {
+		classIdentifier := dataInput.ReadInt()
+		class, err := readClassDefinition(dataInput)
+		if err != nil {
+			return nil, fmt.Errorf("error reading class %d: %w", classIdentifier, err)
+		}
+		classTable[classIdentifier] = class
+	}
+	resolveCustomTypeNames(classTable)
+
+	var err error
+	searchIndex := SearchIndex{}
+	if dataInput.AreBytesAvailable() {
+		logger.Debug("reading search table", "offset", dataInput.OffsetStr())
+		searchIndex, err = readSearchTable(dataInput)
+		if err != nil {
+			return nil, fmt.Errorf("error reading search table: %w", err)
+		}
+	}
+
+	if err := dataInput.Err(); err != nil {
+		return nil, fmt.Errorf("error reading tables: %w", err)
+	}
+
+	// objects are written one after the other, followed by the indexes
+	pointers := slices.Sorted(maps.Values(indexTable))
+	pointers = slices.Compact(pointers)
+	objectEnds := make(map[int]int, len(pointers))
+	for i, pointer := range pointers {
+		if i+1 < len(pointers) {
+			objectEnds[pointer] = pointers[i+1]
+		} else {
+			objectEnds[pointer] = indexesPointer
+		}
+	}
+
+	return &D2oReader{
+		dataInput:   dataInput,
+		indexTable:  indexTable,
+		classTable:  classTable,
+		searchIndex: searchIndex,
+		progress:    options.progress(),
+		objectEnds:  objectEnds,
+		strict:      options.Strict,
+		workers:     options.Workers,
+	}, nil
+}
+
+// Close releases the file content of a reader created with NewD2oReader, after
+// which the reader must not be used anymore. It does nothing for readers
+// created with NewD2oReaderFrom.
+func (r *D2oReader) Close() error {
+	if r.unmap == nil {
+		return nil
+	}
+	unmap := r.unmap
+	r.unmap = nil
+	return unmap()
+}
+
+func (r *D2oReader) Classes() map[int]Class {
+	return r.classTable
+}
+
+func (r *D2oReader) SearchIndex() SearchIndex {
+	return r.searchIndex
+}
+
+// ObjectIds returns the ids of the objects, in file order, the ids of objects
+// sharing a pointer being sorted.
+func (r *D2oReader) ObjectIds() []int {
+	return getKeysSortedByValue(r.indexTable)
+}
+
+// sortedObjectIds returns the ids of the objects sorted by id rather than by
+// pointer, for a stable output.
+func (r *D2oReader) sortedObjectIds() []int {
+	objectIds := r.ObjectIds()
+	sort.Ints(objectIds)
+	return objectIds
+}
+
+// ReadAll decodes all the objects.
+func (r *D2oReader) ReadAll() (D2oData, error) {
+	return readAllObjects(context.Background(), r)
+}
+
+// ReadAllContext is like ReadAll but stops decoding objects when ctx is done,
+// returning the error of ctx.
+func (r *D2oReader) ReadAllContext(ctx context.Context) (D2oData, error) {
+	return readAllObjects(ctx, r)
+}
+
+// GetObjectByID decodes the object with the given id.
+func (r *D2oReader) GetObjectByID(id int) (Object, error) {
+	pointer, ok := r.indexTable[id]
+	if !ok {
+		return nil, fmt.Errorf("object not found: %d", id)
+	}
+	return r.readObjectAt(pointer)
+}
+
+// Objects lazily decodes the objects in index order, that of ObjectIds,
+// yielding each one with its id. Objects which cannot be decoded are skipped
+// with a warning (see Warnings), unless in strict mode where the iteration
+// stops, the error being returned by Err.
+func (r *D2oReader) Objects() iter.Seq2[int, Object] {
+	return func(yield func(int, Object) bool) {
+		r.objectsErr = nil
+		for _, objectId := range r.ObjectIds() {
+			object, warning, err := r.readObjectOrSkip(r.dataInput, objectId)
+			if err != nil {
+				r.objectsErr = err
+				return
+			}
+			if warning != nil {
+				continue
+			}
+			r.progress.ObjectsDecoded(1)
+
+			if !yield(objectId, object) {
+				return
+			}
+		}
+	}
+}
+
+// Err returns the error which stopped the last iteration over Objects, nil
+// when all the objects were decoded or skipped.
+func (r *D2oReader) Err() error {
+	return r.objectsErr
+}
+
+func (r *D2oReader) readObjectAt(pointer int) (Object, error) {
+	return r.readObjectWith(r.dataInput, pointer)
+}
+
+// readObjectWith decodes the object at the given pointer with dataInput, the
+// one of the reader or a view of it when decoding objects concurrently.
+func (r *D2oReader) readObjectWith(dataInput *DataInput, pointer int) (Object, error) {
+	dataInput.ClearErr()
+	dataInput.SetPointer(pointer)
+	if dataInput.debug {
+		dataInput.logger.Debug("reading object", "index", dataInput.OffsetStr())
+	}
+	classId := dataInput.ReadInt()
+	class, ok := r.classTable[classId]
+	if !ok && dataInput.Err() == nil {
+		return nil, fmt.Errorf("unknown class id %d at %#x", classId, pointer)
+	}
+	object, err := readObject(dataInput, r.classTable, class, 0)
+	if err != nil {
+		return nil, err
+	}
+	if err := dataInput.Err(); err != nil {
+		return nil, err
+	}
+	if end := r.objectEnds[pointer]; dataInput.IndexPointer != end {
+		err := dataInput.anomaly(fmt.Errorf("object at %#x ends at %s instead of %#x", pointer, dataInput.OffsetStr(), end))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return object, nil
+}
+
+// Warnings returns the objects skipped so far because they could not be
+// decoded, sorted by id.
+func (r *D2oReader) Warnings() []ObjectWarning {
+	r.warningsMutex.Lock()
+	defer r.warningsMutex.Unlock()
+
+	warnings := make([]ObjectWarning, 0, len(r.warnings))
+	for _, objectId := range slices.Sorted(maps.Keys(r.warnings)) {
+		warnings = append(warnings, r.warnings[objectId])
+	}
+	return warnings
+}
+
+// readObjectOrSkip decodes the object with the given id when iterating over
+// all the objects. An object which cannot be decoded is an error in strict
+// mode, and is skipped with a warning otherwise.
+func (r *D2oReader) readObjectOrSkip(dataInput *DataInput, objectId int) (Object, *ObjectWarning, error) {
+	pointer := r.indexTable[objectId]
+	object, err := r.readObjectWith(dataInput, pointer)
+	if err == nil {
+		return object, nil, nil
+	}
+
+	if r.strict {
+		return nil, nil, fmt.Errorf("error reading object %d: %w", objectId, err)
+	}
+	r.dataInput.logger.Warn("skipping object", "id", objectId, "offset", fmt.Sprintf("%#x", pointer), "error", err)
+	warning := ObjectWarning{ObjectId: objectId, Offset: pointer, Err: err}
+	r.warningsMutex.Lock()
+	defer r.warningsMutex.Unlock()
+	if r.warnings == nil {
+		r.warnings = map[int]ObjectWarning{}
+	}
+	r.warnings[objectId] = warning
+	return nil, &warning, nil
+}
+
+// readAllAt reads r from its start until EOF, using its size when r exposes it
+// like bytes.Reader, strings.Reader and io.SectionReader do.
+func readAllAt(r io.ReaderAt) ([]byte, error) {
+	size := int64(math.MaxInt64)
+	if sizer, ok := r.(interface{ Size() int64 }); ok {
+		size = sizer.Size()
+	}
+	return io.ReadAll(io.NewSectionReader(r, 0, size))
+}
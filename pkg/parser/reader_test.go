@@ -0,0 +1,60 @@
+package parser_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/brequet/dofus-data-file-parser/pkg/parser"
+)
+
+func TestObjectsIndexOrder(t *testing.T) {
+	reader, err := parser.NewD2oReaderFrom(bytes.NewReader(buildD2o(3)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var objectIds []int
+	for objectId, object := range reader.Objects() {
+		if object.(map[string]any)["id"] != objectId {
+			t.Errorf("object %d yielded with id %d", object.(map[string]any)["id"], objectId)
+		}
+		objectIds = append(objectIds, objectId)
+	}
+	if err := reader.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []int{3, 2, 1}; !reflect.DeepEqual(objectIds, want) {
+		t.Errorf("objects yielded in order %v, want %v", objectIds, want)
+	}
+	if want := reader.ObjectIds(); !reflect.DeepEqual(objectIds, want) {
+		t.Errorf("objects yielded in order %v, want that of ObjectIds %v", objectIds, want)
+	}
+}
+
+func TestWriteJSONLMatchesReader(t *testing.T) {
+	opts := parser.JSONWriteOptions{IncludeObjectIds: true}
+
+	d2o, err := parser.ParseD2o(bytes.NewReader(buildD2o(3)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got bytes.Buffer
+	if err := d2o.WriteJSONL(&got, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	reader, err := parser.NewD2oReaderFrom(bytes.NewReader(buildD2o(3)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want bytes.Buffer
+	if err := reader.WriteJSONL(&want, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	if got.String() != want.String() {
+		t.Errorf("D2oData.WriteJSONL:\n%s\nwant that of D2oReader.WriteJSONL:\n%s", got.String(), want.String())
+	}
+}
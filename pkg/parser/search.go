@@ -0,0 +1,127 @@
+package parser
+
+import (
+	"fmt"
+)
+
+// SearchIndex maps a search key (see SearchKey) to the ids of the matching objects.
+type SearchIndex map[string][]int
+
+type searchField struct {
+	name    string
+	pointer int
+	kind    FieldType
+	count   int
+}
+
+// SearchKey builds the SearchIndex key for a queryable field value.
+func SearchKey(fieldName string, value any) string {
+	return fmt.Sprintf("%s:%v", fieldName, value)
+}
+
+// SearchObjects returns the objects registered under the given key in the search index.
+// It is a method rather than a SearchObjects(index, key) function: the index
+// only holds object ids, which are resolved with the objects of the file.
+func (d D2oData) SearchObjects(key string) []Object {
+	objects := make([]Object, 0)
+	for _, objectId := range d.SearchIndex[key] {
+		if position, ok := d.objectPositions[objectId]; ok {
+			objects = append(objects, d.Objects[position])
+		}
+	}
+	return objects
+}
+
+// GetObjectsByField returns the objects whose queryable field has the given
+// value, looked up in the search index rather than by scanning the objects.
+// Only the fields indexed by the game (see GameDataQuery.as) can be queried.
+func (d D2oData) GetObjectsByField(fieldName string, value any) []Object {
+	return d.SearchObjects(SearchKey(fieldName, value))
+}
+
+// GetObjectsByField is like D2oData.GetObjectsByField but only decodes the
+// matching objects.
+func (r *D2oReader) GetObjectsByField(fieldName string, value any) ([]Object, error) {
+	objectIds := r.searchIndex[SearchKey(fieldName, value)]
+	objects := make([]Object, 0, len(objectIds))
+	for _, objectId := range objectIds {
+		pointer, ok := r.indexTable[objectId]
+		if !ok {
+			continue
+		}
+
+		object, err := r.readObjectAt(pointer)
+		if err != nil {
+			return nil, fmt.Errorf("error reading object %d: %w", objectId, err)
+		}
+		objects = append(objects, object)
+	}
+	return objects, nil
+}
+
+func readSearchTable(dataInput *DataInput) (SearchIndex, error) {
+	// See GameDataProcess.as
+	searchIndex := SearchIndex{}
+
+	fieldListSize := dataInput.ReadInt()
+	fieldListEnd := dataInput.IndexPointer + fieldListSize
+	indexSearchOffset := fieldListEnd + 4
+
+	fields := make([]searchField, 0)
+	for dataInput.IndexPointer < fieldListEnd && dataInput.Err() == nil {
+		fields = append(fields, searchField{
+			name:    dataInput.ReadUTF(),
+			pointer: dataInput.ReadInt() + indexSearchOffset,
+			kind:    FieldType(dataInput.ReadInt()),
+			count:   dataInput.ReadInt(),
+		})
+	}
+
+	for _, field := range fields {
+		if field.pointer < 0 || field.pointer >= dataInput.Length {
+			err := dataInput.anomaly(fmt.Errorf("pointer %#x of search field %s out of bounds", field.pointer, field.name))
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		dataInput.SetPointer(field.pointer)
+		for i := 0; i < field.count && dataInput.Err() == nil; i++ {
+			value, ok := readSearchValue(dataInput, field.kind)
+			if !ok {
+				err := dataInput.anomaly(fmt.Errorf("unsupported type %s of search field %s", field.kind, field.name))
+				if err != nil {
+					return nil, err
+				}
+				break
+			}
+
+			idsCount := dataInput.ReadInt() / 4
+			ids := make([]int, 0, max(min(idsCount, dataInput.Remaining()/4), 0))
+			for j := 0; j < idsCount && dataInput.Err() == nil; j++ {
+				ids = append(ids, dataInput.ReadInt())
+			}
+			searchIndex[SearchKey(field.name, value)] = ids
+		}
+	}
+
+	return searchIndex, nil
+}
+
+func readSearchValue(dataInput *DataInput, fieldType FieldType) (any, bool) {
+	switch fieldType {
+	case Integer, I18n:
+		return dataInput.ReadInt(), true
+	case Boolean:
+		return dataInput.ReadBoolean(), true
+	case String:
+		return dataInput.ReadUTF(), true
+	case Number:
+		return dataInput.ReadDouble(), true
+	case UnsignedInteger:
+		return dataInput.ReadUint(), true
+	default:
+		return nil, false
+	}
+}
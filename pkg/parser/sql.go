@@ -0,0 +1,61 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+type SQLDialect int
+
+const (
+	SQLite SQLDialect = iota
+	PostgreSQL
+)
+
+// SQLColumnDefinition returns the SQL column definition storing the field,
+// with the column name quoted since some field names are SQL keywords.
+// Vectors and custom types are stored as nullable JSON text. Numbers are
+// nullable too since NaN values are parsed as null.
+func (f GameDataField) SQLColumnDefinition(dialect SQLDialect) string {
+	switch f.Type {
+	case Integer, I18n:
+		return fmt.Sprintf("%s %s NOT NULL", QuoteSQLIdentifier(f.Name), dialect.integerType())
+	case UnsignedInteger:
+		return fmt.Sprintf("%s %s NOT NULL", QuoteSQLIdentifier(f.Name), dialect.unsignedIntegerType())
+	case Boolean:
+		return fmt.Sprintf("%s BOOLEAN NOT NULL", QuoteSQLIdentifier(f.Name))
+	case String:
+		return fmt.Sprintf("%s TEXT NOT NULL", QuoteSQLIdentifier(f.Name))
+	case Number:
+		return fmt.Sprintf("%s %s", QuoteSQLIdentifier(f.Name), dialect.realType())
+	default:
+		return fmt.Sprintf("%s TEXT", QuoteSQLIdentifier(f.Name))
+	}
+}
+
+// QuoteSQLIdentifier quotes a table or column name, doubling its quotes. Go
+// quoting with %q escapes them with a backslash, which SQL does not support.
+func QuoteSQLIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+func (d SQLDialect) integerType() string {
+	if d == PostgreSQL {
+		return "INT"
+	}
+	return "INTEGER"
+}
+
+func (d SQLDialect) unsignedIntegerType() string {
+	if d == PostgreSQL {
+		return "BIGINT"
+	}
+	return "INTEGER"
+}
+
+func (d SQLDialect) realType() string {
+	if d == PostgreSQL {
+		return "DOUBLE PRECISION"
+	}
+	return "REAL"
+}
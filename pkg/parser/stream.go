@@ -0,0 +1,133 @@
+package parser
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteJSON decodes the objects one at a time and writes them to w as they
+// are decoded, producing the same document as D2oData.WriteJSON without
+// holding every object in memory.
+func (r *D2oReader) WriteJSON(w io.Writer, opts JSONWriteOptions) error {
+	return r.WriteJSONContext(context.Background(), w, opts)
+}
+
+// WriteJSONContext is like WriteJSON but stops writing when ctx is done,
+// returning the error of ctx.
+func (r *D2oReader) WriteJSONContext(ctx context.Context, w io.Writer, opts JSONWriteOptions) error {
+	indent := opts.Indent
+	if indent == "" {
+		indent = "  "
+	}
+	newline, objectPrefix := "", ""
+	if opts.Pretty {
+		newline, objectPrefix = "\n", strings.Repeat(indent, 2)
+	}
+
+	classesJSON, err := marshalJSON(r.classTable, indent, indent, opts.Pretty)
+	if err != nil {
+		return fmt.Errorf("error marshalling classes: %w", err)
+	}
+
+	objectIds := r.sortedObjectIds()
+	if opts.Pretty {
+		_, err = fmt.Fprintf(w, "{\n%s\"classes\": %s,\n%s\"objects\": [", indent, classesJSON, indent)
+	} else {
+		_, err = fmt.Fprintf(w, `{"classes":%s,"objects":[`, classesJSON)
+	}
+	if err != nil {
+		return fmt.Errorf("error writing json: %w", err)
+	}
+
+	transformer := newObjectTransformer(r.classTable, opts)
+	written := 0
+	for _, objectId := range objectIds {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		object, warning, err := r.readObjectOrSkip(r.dataInput, objectId)
+		if err != nil {
+			return err
+		}
+		if warning != nil {
+			continue
+		}
+		r.progress.ObjectsDecoded(1)
+
+		objectJSON, err := marshalJSON(transformer.transform(object, objectId, true), objectPrefix, indent, opts.Pretty)
+		if err != nil {
+			return fmt.Errorf("error marshalling object %d: %w", objectId, err)
+		}
+
+		separator := ","
+		if written == 0 {
+			separator = ""
+		}
+		_, err = fmt.Fprintf(w, "%s%s%s%s", separator, newline, objectPrefix, objectJSON)
+		if err != nil {
+			return fmt.Errorf("error writing json: %w", err)
+		}
+		written++
+	}
+
+	switch {
+	case opts.Pretty && written > 0:
+		_, err = fmt.Fprintf(w, "\n%s]\n}", indent)
+	case opts.Pretty:
+		_, err = io.WriteString(w, "]\n}")
+	default:
+		_, err = io.WriteString(w, "]}")
+	}
+	if err != nil {
+		return fmt.Errorf("error writing json: %w", err)
+	}
+
+	return nil
+}
+
+// WriteJSONL decodes the objects one at a time and writes them to w as JSON
+// Lines, like D2oData.WriteJSONL.
+func (r *D2oReader) WriteJSONL(w io.Writer, opts JSONWriteOptions) error {
+	return r.WriteJSONLContext(context.Background(), w, opts)
+}
+
+// WriteJSONLContext is like WriteJSONL but stops writing when ctx is done,
+// returning the error of ctx.
+func (r *D2oReader) WriteJSONLContext(ctx context.Context, w io.Writer, opts JSONWriteOptions) error {
+	encoder := json.NewEncoder(w)
+	transformer := newObjectTransformer(r.classTable, opts)
+	for _, objectId := range r.sortedObjectIds() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		object, warning, err := r.readObjectOrSkip(r.dataInput, objectId)
+		if err != nil {
+			return err
+		}
+		if warning != nil {
+			continue
+		}
+		r.progress.ObjectsDecoded(1)
+
+		err = encoder.Encode(transformer.transform(object, objectId, true))
+		if err != nil {
+			return fmt.Errorf("error encoding object %d: %w", objectId, err)
+		}
+	}
+
+	return nil
+}
+
+// marshalJSON marshals a value nested in a pretty printed document, prefix
+// being the indentation of the line it starts on.
+func marshalJSON(value any, prefix, indent string, pretty bool) ([]byte, error) {
+	if !pretty {
+		return json.Marshal(value)
+	}
+	return json.MarshalIndent(value, prefix, indent)
+}
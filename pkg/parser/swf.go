@@ -0,0 +1,66 @@
+package parser
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// SwfHeader is the header of a SWF movie.
+type SwfHeader struct {
+	Version    int
+	FrameCount int
+}
+
+// ReadSwfHeader reads the header of a SWF movie, uncompressed (FWS) or zlib
+// compressed (CWS), and returns a reader of the tags which follow it, which
+// are only decompressed as they are read.
+func ReadSwfHeader(swf []byte) (SwfHeader, io.Reader, error) {
+	if len(swf) < 8 {
+		return SwfHeader{}, nil, fmt.Errorf("%w: swf of %d bytes", ErrTruncatedData, len(swf))
+	}
+
+	var body io.Reader = bytes.NewReader(swf[8:])
+	switch string(swf[:3]) {
+	case "FWS":
+	case "CWS":
+		reader, err := zlib.NewReader(body)
+		if err != nil {
+			return SwfHeader{}, nil, fmt.Errorf("error decompressing swf: %w", err)
+		}
+		body = reader
+	default:
+		return SwfHeader{}, nil, fmt.Errorf("%w: swf signature %q", ErrInvalidHeader, swf[:3])
+	}
+
+	// the frame size is a rectangle of 4 fields of nBits bits, nBits being
+	// its first 5 bits, followed by the frame rate and count
+	rect := make([]byte, 1, 17+4)
+	_, err := io.ReadFull(body, rect)
+	if err != nil {
+		return SwfHeader{}, nil, swfReadError(err, "frame size")
+	}
+	rectBytes := (5 + 4*int(rect[0]>>3) + 7) / 8
+	rect = rect[:rectBytes+4]
+	_, err = io.ReadFull(body, rect[1:])
+	if err != nil {
+		return SwfHeader{}, nil, swfReadError(err, "frame count")
+	}
+
+	header := SwfHeader{
+		Version:    int(swf[3]),
+		FrameCount: int(binary.LittleEndian.Uint16(rect[rectBytes+2:])),
+	}
+	return header, body, nil
+}
+
+// swfReadError reports the end of a SWF movie as truncated data.
+func swfReadError(err error, field string) error {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return fmt.Errorf("%w: swf %s", ErrTruncatedData, field)
+	}
+	return fmt.Errorf("error decompressing swf: %w", err)
+}
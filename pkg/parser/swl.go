@@ -0,0 +1,62 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+)
+
+// Swl is a SWF library of the game assets, listing the classes its SWF
+// movie exports.
+type Swl struct {
+	Version    int      `json:"version"`
+	FrameRate  uint     `json:"frameRate"`
+	FrameCount int      `json:"frameCount"` // of the SWF movie
+	Classes    []string `json:"classes"`
+
+	// Swf is the embedded SWF movie.
+	Swf []byte `json:"-"`
+}
+
+// ProcessSwlFile reads a .swl file, the libraries of the game assets.
+func ProcessSwlFile(swlFilePath string, opts ...ParseOptions) (Swl, error) {
+	getParseOptions(opts).logger().Debug("processing SWL file", "file", swlFilePath)
+
+	fileContentBytes, err := os.ReadFile(swlFilePath)
+	if err != nil {
+		return Swl{}, fmt.Errorf("error reading file: %w", err)
+	}
+
+	return ParseSwl(fileContentBytes, opts...)
+}
+
+// ParseSwl decodes a SWF library.
+func ParseSwl(data []byte, opts ...ParseOptions) (Swl, error) {
+	// See SwlLoader.as
+	dataInput := newDataInput(data, getParseOptions(opts).logger())
+	header := dataInput.ReadUnsignedByte()
+	if header != 'L' {
+		return Swl{}, fmt.Errorf("%w: %d", ErrInvalidHeader, header)
+	}
+
+	swl := Swl{
+		Version:   int(dataInput.ReadUnsignedByte()),
+		FrameRate: dataInput.ReadUint(),
+	}
+	classesCount := dataInput.ReadInt()
+	swl.Classes = make([]string, 0, min(max(classesCount, 0), dataInput.Remaining()))
+	for i := 0; i < classesCount && dataInput.Err() == nil; i++ {
+		swl.Classes = append(swl.Classes, dataInput.ReadUTF())
+	}
+	if err := dataInput.Err(); err != nil {
+		return Swl{}, err
+	}
+
+	swl.Swf = dataInput.Read(dataInput.Remaining())
+	swfHeader, _, err := ReadSwfHeader(swl.Swf)
+	if err != nil {
+		return Swl{}, fmt.Errorf("error reading swf header: %w", err)
+	}
+	swl.FrameCount = swfHeader.FrameCount
+
+	return swl, nil
+}
@@ -0,0 +1,53 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ToFlatTable extracts the objects of the given class as a table whose columns
+// are the class fields in definition order. Vector and custom type values are
+// stored as JSON strings.
+func (d D2oData) ToFlatTable(class Class) ([]string, [][]any, error) {
+	if !d.hasClass(class) {
+		return nil, nil, fmt.Errorf("class not found: %s.%s", class.PackageName, class.PackageClass)
+	}
+
+	columns := make([]string, 0, len(class.Fields))
+	for _, field := range class.Fields {
+		columns = append(columns, field.Name)
+	}
+
+	rows := make([][]any, 0)
+	for _, object := range d.Objects {
+		objectMap, ok := object.(map[string]any)
+		if !ok || objectMap["ClassType_"] != class.PackageClass {
+			continue
+		}
+
+		row := make([]any, 0, len(class.Fields))
+		for _, field := range class.Fields {
+			value := objectMap[field.Name]
+			if field.Type == Vector || field.Type > 0 {
+				jsonValue, err := json.Marshal(value)
+				if err != nil {
+					return nil, nil, fmt.Errorf("error marshalling field %s: %w", field.Name, err)
+				}
+				value = string(jsonValue)
+			}
+			row = append(row, value)
+		}
+		rows = append(rows, row)
+	}
+
+	return columns, rows, nil
+}
+
+func (d D2oData) hasClass(class Class) bool {
+	for _, c := range d.Classes {
+		if c.PackageName == class.PackageName && c.PackageClass == class.PackageClass {
+			return true
+		}
+	}
+	return false
+}
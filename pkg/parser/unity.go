@@ -0,0 +1,194 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ErrUnsupportedCompression is returned for the blocks of a Unity bundle
+// compressed with LZMA, which is not supported.
+var ErrUnsupportedCompression = errors.New("unsupported compression")
+
+// UnityBundle is an asset bundle of the Unity engine Dofus 3 is built on,
+// holding the serialized assets of the game data, which are decoded with
+// ParseUnitySerializedFile.
+type UnityBundle struct {
+	FormatVersion int                `json:"formatVersion"`
+	UnityVersion  string             `json:"unityVersion"`
+	UnityRevision string             `json:"unityRevision"`
+	Entries       []UnityBundleEntry `json:"entries"`
+
+	data []byte // decompressed blocks
+}
+
+type UnityBundleEntry struct {
+	Name   string `json:"name"`
+	Offset int    `json:"offset"`
+	Length int    `json:"length"`
+	Flags  int    `json:"flags"`
+}
+
+const (
+	unityCompressionMask     = 0x3F
+	unityCompressionNone     = 0
+	unityCompressionLZMA     = 1
+	unityCompressionLZ4      = 2
+	unityCompressionLZ4HC    = 3
+	unityBlocksInfoAtEnd     = 0x80
+	unityBlocksInfoPadding   = 0x200
+	unityBundleAlignment     = 16
+	unityBundleSignature     = "UnityFS"
+	unityBlocksInfoHashBytes = 16
+)
+
+// ProcessUnityBundleFile reads the entries of a Unity asset bundle of Dofus 3,
+// decompressing its LZ4 blocks.
+func ProcessUnityBundleFile(bundleFilePath string, opts ...ParseOptions) (UnityBundle, error) {
+	logger := getParseOptions(opts).logger()
+	logger.Debug("processing Unity bundle", "file", bundleFilePath)
+
+	fileContentBytes, err := os.ReadFile(bundleFilePath)
+	if err != nil {
+		return UnityBundle{}, fmt.Errorf("error reading file: %w", err)
+	}
+
+	return ParseUnityBundle(fileContentBytes, opts...)
+}
+
+// ParseUnityBundle reads the entries of a Unity asset bundle, decompressing its
+// LZ4 blocks.
+func ParseUnityBundle(data []byte, opts ...ParseOptions) (UnityBundle, error) {
+	logger := getParseOptions(opts).logger()
+
+	// See the UnityFS format of AssetStudio and UnityPy
+	dataInput := newDataInput(data, logger)
+	if signature := dataInput.ReadNullTerminatedString(); signature != unityBundleSignature {
+		return UnityBundle{}, fmt.Errorf("%w: signature %q", ErrInvalidHeader, signature)
+	}
+	bundle := UnityBundle{
+		FormatVersion: dataInput.ReadInt(),
+		UnityVersion:  dataInput.ReadNullTerminatedString(),
+		UnityRevision: dataInput.ReadNullTerminatedString(),
+	}
+	_ = dataInput.ReadLong() // file size
+	compressedBlocksInfoSize := dataInput.ReadInt()
+	blocksInfoSize := dataInput.ReadInt()
+	flags := dataInput.ReadInt()
+	if bundle.FormatVersion >= 7 {
+		alignPointer(dataInput)
+	}
+	if err := dataInput.Err(); err != nil {
+		return UnityBundle{}, fmt.Errorf("error reading header: %w", err)
+	}
+	logger.Debug("unity bundle header", "format version", bundle.FormatVersion, "unity version", bundle.UnityVersion, "flags", flags)
+
+	headerEnd := dataInput.IndexPointer
+	blocksInfoOffset := headerEnd
+	if flags&unityBlocksInfoAtEnd != 0 {
+		blocksInfoOffset = dataInput.Length - compressedBlocksInfoSize
+	}
+	dataInput.SetPointer(blocksInfoOffset)
+	compressedBlocksInfo := dataInput.Read(compressedBlocksInfoSize)
+	if err := dataInput.Err(); err != nil {
+		return UnityBundle{}, fmt.Errorf("error reading blocks info: %w", err)
+	}
+	blocksInfoBytes, err := decompressUnityBlock(compressedBlocksInfo, blocksInfoSize, flags)
+	if err != nil {
+		return UnityBundle{}, fmt.Errorf("error decompressing blocks info: %w", err)
+	}
+
+	blocksOffset := blocksInfoOffset + compressedBlocksInfoSize
+	if flags&unityBlocksInfoAtEnd != 0 {
+		blocksOffset = headerEnd
+	}
+	if flags&unityBlocksInfoPadding != 0 {
+		blocksOffset = (blocksOffset + unityBundleAlignment - 1) / unityBundleAlignment * unityBundleAlignment
+	}
+
+	blocksInfo := newDataInput(blocksInfoBytes, logger)
+	blocksInfo.Read(unityBlocksInfoHashBytes)
+	blockCount := blocksInfo.ReadInt()
+	dataInput.SetPointer(blocksOffset)
+	blocks := make([]byte, 0)
+	for i := 0; i < blockCount && blocksInfo.Err() == nil; i++ {
+		size := int(blocksInfo.ReadUint())
+		compressedSize := int(blocksInfo.ReadUint())
+		blockFlags := int(blocksInfo.ReadUnsignedShort())
+		block := dataInput.Read(compressedSize)
+		if err := dataInput.Err(); err != nil {
+			return UnityBundle{}, fmt.Errorf("error reading block %d: %w", i, err)
+		}
+		decompressed, err := decompressUnityBlock(block, size, blockFlags)
+		if err != nil {
+			return UnityBundle{}, fmt.Errorf("error decompressing block %d: %w", i, err)
+		}
+		blocks = append(blocks, decompressed...)
+	}
+
+	entryCount := blocksInfo.ReadInt()
+	for i := 0; i < entryCount && blocksInfo.Err() == nil; i++ {
+		offset, length := blocksInfo.ReadLong(), blocksInfo.ReadLong()
+		entry := UnityBundleEntry{
+			Flags: blocksInfo.ReadInt(),
+			Name:  blocksInfo.ReadNullTerminatedString(),
+		}
+		// checked separately, as their sum may overflow
+		if offset < 0 || offset > int64(len(blocks)) || length < 0 || length > int64(len(blocks))-offset {
+			return UnityBundle{}, fmt.Errorf("%w: entry %s out of bounds", ErrTruncatedData, entry.Name)
+		}
+		entry.Offset, entry.Length = int(offset), int(length)
+		bundle.Entries = append(bundle.Entries, entry)
+	}
+	if err := blocksInfo.Err(); err != nil {
+		return UnityBundle{}, fmt.Errorf("error reading blocks info: %w", err)
+	}
+
+	bundle.data = blocks
+	return bundle, nil
+}
+
+func decompressUnityBlock(data []byte, size, flags int) ([]byte, error) {
+	switch compression := flags & unityCompressionMask; compression {
+	case unityCompressionNone:
+		return data, nil
+	case unityCompressionLZ4, unityCompressionLZ4HC:
+		return decodeLZ4Block(data, size)
+	case unityCompressionLZMA:
+		return nil, fmt.Errorf("%w: lzma", ErrUnsupportedCompression)
+	default:
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedCompression, compression)
+	}
+}
+
+func alignPointer(dataInput *DataInput) {
+	dataInput.SetPointer((dataInput.IndexPointer + unityBundleAlignment - 1) / unityBundleAlignment * unityBundleAlignment)
+}
+
+// Read returns the content of an entry, usually a serialized file of assets.
+func (b UnityBundle) Read(entry UnityBundleEntry) []byte {
+	return b.data[entry.Offset : entry.Offset+entry.Length]
+}
+
+// ExtractAll writes every entry under the output folder, keeping their paths.
+func (b UnityBundle) ExtractAll(outputFolderPath string) error {
+	for _, entry := range b.Entries {
+		if !filepath.IsLocal(filepath.FromSlash(entry.Name)) {
+			return fmt.Errorf("invalid entry path: %s", entry.Name)
+		}
+		entryPath := filepath.Join(outputFolderPath, filepath.FromSlash(entry.Name))
+
+		err := os.MkdirAll(filepath.Dir(entryPath), 0755)
+		if err != nil {
+			return fmt.Errorf("error creating folder: %w", err)
+		}
+
+		err = os.WriteFile(entryPath, b.Read(entry), 0644)
+		if err != nil {
+			return fmt.Errorf("error writing file: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package parser_test
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/brequet/dofus-data-file-parser/pkg/parser"
+)
+
+func TestParseUnityBundleEntryOutOfBounds(t *testing.T) {
+	content := []byte("serialized file")
+	tests := []struct {
+		name           string
+		offset, length int64
+	}{
+		{name: "length past the end", offset: 1, length: int64(len(content))},
+		{name: "offset past the end", offset: int64(len(content)) + 1, length: -1},
+		{name: "negative length", offset: 1, length: -1},
+		{name: "overflowing sum", offset: 1, length: math.MaxInt64},
+		{name: "overflowing offset", offset: math.MaxInt64, length: 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data := buildUnityBundle(unityBundleEntry{name: "CAB-items", content: content, offset: test.offset, length: test.length})
+			_, err := parser.ParseUnityBundle(data)
+			if !errors.Is(err, parser.ErrTruncatedData) {
+				t.Errorf("ParseUnityBundle: error %v, want %v", err, parser.ErrTruncatedData)
+			}
+		})
+	}
+}
+
+func TestParseUnitySerializedFile(t *testing.T) {
+	file, err := parser.ParseUnitySerializedFile(unityItemsFile(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if file.Version != 22 || file.UnityVersion != "2022.3.15f1" || len(file.Objects) != 2 {
+		t.Fatalf("ParseUnitySerializedFile: %+v", file)
+	}
+	if name := file.Types[0].TypeTree[8].Name; name != "m_Name" {
+		t.Errorf("common string name %q, want m_Name", name)
+	}
+
+	fields, err := file.ReadObject(file.Objects[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{
+		"m_GameObject": map[string]any{"m_FileID": 0, "m_PathID": int64(0)},
+		"m_Enabled":    1,
+		"m_Script":     map[string]any{"m_FileID": 1, "m_PathID": int64(5)},
+		"m_Name":       "item 2",
+		"id":           2,
+		"ids":          []any{20, 21},
+		"visible":      false,
+		"order":        2,
+	}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("ReadObject: %#v, want %#v", fields, want)
+	}
+}
+
+func TestParseUnitySerializedFileUnsupportedVersion(t *testing.T) {
+	data := unityItemsFile(1)
+	data[11] = 9 // version
+	_, err := parser.ParseUnitySerializedFile(data)
+	if !errors.Is(err, parser.ErrUnsupportedVersion) {
+		t.Errorf("ParseUnitySerializedFile: error %v, want %v", err, parser.ErrUnsupportedVersion)
+	}
+}
+
+func TestUnityModules(t *testing.T) {
+	files := map[string]parser.UnitySerializedFile{}
+	for name, data := range map[string][]byte{"CAB-scripts": unityScriptsFile(), "CAB-items": unityItemsFile(3)} {
+		file, err := parser.ParseUnitySerializedFile(data)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		files[name] = file
+	}
+
+	modules, err := parser.UnityModules(files, parser.ParseOptions{Strict: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(modules) != 1 || len(modules["Items"]) != 3 {
+		t.Fatalf("UnityModules: %v", modules)
+	}
+	want := map[string]any{"ClassType_": "Items", "m_Name": "item 3", "id": 3, "ids": []any{30, 31}, "visible": true, "order": 3}
+	if !reflect.DeepEqual(modules["Items"][2], parser.Object(want)) {
+		t.Errorf("UnityModules: %#v, want %#v", modules["Items"][2], want)
+	}
+
+	// without the file of their MonoScript, the MonoBehaviours cannot be
+	// assigned a module
+	delete(files, "CAB-scripts")
+	_, err = parser.UnityModules(files, parser.ParseOptions{Strict: true})
+	if err == nil {
+		t.Error("UnityModules without the MonoScript succeeded in strict mode")
+	}
+	modules, err = parser.UnityModules(files)
+	if err != nil || len(modules) != 0 {
+		t.Errorf("UnityModules without the MonoScript: %v, error %v", modules, err)
+	}
+}
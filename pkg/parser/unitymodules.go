@@ -0,0 +1,107 @@
+package parser
+
+import (
+	"fmt"
+	"path"
+	"slices"
+)
+
+// unityScriptKey identifies a MonoScript by the name of its serialized file
+// and its path id.
+type unityScriptKey struct {
+	file   string
+	pathID int64
+}
+
+// unityEngineFields are the fields every MonoBehaviour holds for the engine,
+// which are not part of the game data.
+var unityEngineFields = []string{"m_GameObject", "m_Enabled", "m_Script", "m_EditorHideFlags", "m_EditorClassIdentifier"}
+
+// UnityModules decodes the MonoBehaviours of serialized files, keyed by their
+// name in their bundle, into the objects of the modules of Dofus 3. Each
+// object is stored in the module of the class of its MonoScript, whose name
+// is set as its "ClassType_" entry, the engine fields being removed.
+//
+// The MonoScripts may be in any of the files. A MonoBehaviour which cannot be
+// decoded is an error in strict mode, and is skipped with a warning otherwise.
+func UnityModules(files map[string]UnitySerializedFile, opts ...ParseOptions) (map[string][]Object, error) {
+	options := getParseOptions(opts)
+	logger := options.logger()
+
+	fileNames := make([]string, 0, len(files))
+	for fileName := range files {
+		fileNames = append(fileNames, fileName)
+	}
+	slices.Sort(fileNames)
+
+	scripts := map[unityScriptKey]string{}
+	for _, fileName := range fileNames {
+		file := files[fileName]
+		for _, object := range file.Objects {
+			if object.ClassID != unityMonoScriptClassID {
+				continue
+			}
+			fields, err := file.ReadObject(object)
+			if err != nil {
+				logger.Debug("skipping MonoScript", "file", fileName, "error", err)
+				continue
+			}
+			className, _ := fields["m_ClassName"].(string)
+			scripts[unityScriptKey{file: fileName, pathID: object.PathID}] = className
+		}
+	}
+
+	modules := map[string][]Object{}
+	for _, fileName := range fileNames {
+		file := files[fileName]
+		for _, object := range file.Objects {
+			if object.ClassID != unityMonoBehaviourClassID {
+				continue
+			}
+			fields, className, err := readUnityMonoBehaviour(file, fileName, object, scripts)
+			if err != nil {
+				err = fmt.Errorf("error decoding MonoBehaviour of %s: %w", fileName, err)
+				if options.Strict {
+					return nil, err
+				}
+				logger.Warn("skipping MonoBehaviour", "error", err)
+				continue
+			}
+
+			for _, field := range unityEngineFields {
+				delete(fields, field)
+			}
+			fields["ClassType_"] = className
+			modules[className] = append(modules[className], Object(fields))
+		}
+	}
+
+	return modules, nil
+}
+
+// readUnityMonoBehaviour decodes a MonoBehaviour, returning its fields and the
+// class name of its MonoScript.
+func readUnityMonoBehaviour(file UnitySerializedFile, fileName string, object UnityObjectInfo, scripts map[unityScriptKey]string) (map[string]any, string, error) {
+	fields, err := file.ReadObject(object)
+	if err != nil {
+		return nil, "", err
+	}
+
+	script, _ := fields["m_Script"].(map[string]any)
+	fileID, _ := script["m_FileID"].(int)
+	pathID, _ := script["m_PathID"].(int64)
+	scriptFileName := fileName
+	if fileID != 0 {
+		if fileID < 0 || fileID > len(file.Externals) {
+			return nil, "", fmt.Errorf("object %d refers to the unknown file %d", object.PathID, fileID)
+		}
+		// e.g. archive:/CAB-<hash>/CAB-<hash>
+		scriptFileName = path.Base(file.Externals[fileID-1])
+	}
+
+	className := scripts[unityScriptKey{file: scriptFileName, pathID: pathID}]
+	if className == "" {
+		return nil, "", fmt.Errorf("object %d refers to the unknown MonoScript %d of %s", object.PathID, pathID, scriptFileName)
+	}
+	return fields, className, nil
+}
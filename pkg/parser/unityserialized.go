@@ -0,0 +1,444 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+var (
+	// ErrUnsupportedVersion is returned for the Unity serialized files of a
+	// format version which is not supported.
+	ErrUnsupportedVersion = errors.New("unsupported version")
+	// ErrNoTypeTree is returned when decoding an object whose type has no type
+	// tree, the serialized file being built without them.
+	ErrNoTypeTree = errors.New("no type tree")
+)
+
+const (
+	unityMonoBehaviourClassID = 114
+	unityMonoScriptClassID    = 115
+
+	// versions of the serialized file format, from Unity 5 to Unity 6
+	minUnitySerializedFileVersion = 14
+	maxUnitySerializedFileVersion = 22
+
+	// unityAlignFlag is the meta flag of the type tree nodes whose value is
+	// followed by padding up to the next multiple of 4 bytes.
+	unityAlignFlag = 0x4000
+	// unityCommonStringFlag marks the string offsets of type tree nodes which
+	// are offsets in unityCommonStrings.
+	unityCommonStringFlag = 0x80000000
+)
+
+// UnitySerializedFile is a serialized file of Unity assets, the main entry of
+// the asset bundles of Dofus 3. Its objects are decoded with the type trees of
+// their type, which describe their fields.
+type UnitySerializedFile struct {
+	Version        int                   `json:"version"`
+	UnityVersion   string                `json:"unityVersion"`
+	TargetPlatform int                   `json:"targetPlatform"`
+	Types          []UnitySerializedType `json:"types"`
+	Objects        []UnityObjectInfo     `json:"objects"`
+	// Externals are the paths of the files the objects refer to, e.g.
+	// "archive:/CAB-<hash>/CAB-<hash>", file id n being Externals[n-1].
+	Externals []string `json:"externals"`
+
+	data  []byte
+	order binary.ByteOrder
+}
+
+// UnitySerializedType is a type of the objects of a serialized file.
+type UnitySerializedType struct {
+	ClassID int `json:"classId"`
+	// TypeTree lists the fields of the type depth first, the fields of a node
+	// being the nodes of the next level which follow it. It is empty when the
+	// file is built without type trees.
+	TypeTree []UnityTypeTreeNode `json:"typeTree,omitempty"`
+
+	ends []int // index of the node following the fields of each node
+}
+
+type UnityTypeTreeNode struct {
+	Type     string `json:"type"`
+	Name     string `json:"name"`
+	Level    int    `json:"level"`
+	ByteSize int    `json:"byteSize"`
+	MetaFlag int    `json:"metaFlag"`
+}
+
+// UnityObjectInfo locates an object of a serialized file.
+type UnityObjectInfo struct {
+	PathID    int64 `json:"pathId"`
+	Offset    int64 `json:"offset"`
+	Size      int64 `json:"size"`
+	TypeIndex int   `json:"typeIndex"` // in Types, -1 when unknown
+	ClassID   int   `json:"classId"`
+}
+
+// unityInput reads the values of a serialized file in its byte order, over a
+// DataInput whose errors it shares.
+type unityInput struct {
+	*DataInput
+	order binary.ByteOrder
+}
+
+func (ui unityInput) readUint16() uint16 {
+	data := ui.Read(2)
+	if data == nil {
+		return 0
+	}
+	return ui.order.Uint16(data)
+}
+
+func (ui unityInput) readUint32() uint32 {
+	data := ui.Read(4)
+	if data == nil {
+		return 0
+	}
+	return ui.order.Uint32(data)
+}
+
+func (ui unityInput) readUint64() uint64 {
+	data := ui.Read(8)
+	if data == nil {
+		return 0
+	}
+	return ui.order.Uint64(data)
+}
+
+func (ui unityInput) readInt32() int {
+	return int(int32(ui.readUint32()))
+}
+
+// readLength reads the length of a string, vector or map, whose every
+// element takes at least a byte.
+func (ui unityInput) readLength() int {
+	length := ui.readInt32()
+	if ui.Err() == nil && (length < 0 || length > ui.Remaining()) {
+		ui.err = fmt.Errorf("%w: length %d with %d bytes left at %s", ErrTruncatedData, length, ui.Remaining(), ui.OffsetStr())
+		return 0
+	}
+	return length
+}
+
+func (ui unityInput) align() {
+	ui.SetPointer((ui.IndexPointer + 3) / 4 * 4)
+}
+
+// ParseUnitySerializedFile reads the types, objects and externals of a Unity
+// serialized file, whose objects are then decoded with ReadObject.
+func ParseUnitySerializedFile(data []byte, opts ...ParseOptions) (UnitySerializedFile, error) {
+	logger := getParseOptions(opts).logger()
+
+	// See SerializedFile of AssetStudio and UnityPy
+	dataInput := newDataInput(data, logger)
+	_ = dataInput.ReadUint() // metadata size
+	_ = dataInput.ReadUint() // file size
+	version := int(dataInput.ReadUint())
+	dataOffset := int64(dataInput.ReadUint())
+	if err := dataInput.Err(); err != nil {
+		return UnitySerializedFile{}, fmt.Errorf("error reading header: %w", err)
+	}
+	if version < minUnitySerializedFileVersion || version > maxUnitySerializedFileVersion {
+		return UnitySerializedFile{}, fmt.Errorf("%w: serialized file version %d", ErrUnsupportedVersion, version)
+	}
+	bigEndian := dataInput.ReadBoolean()
+	dataInput.Read(3)
+	if version >= 22 {
+		_ = dataInput.ReadUint() // metadata size
+		_ = dataInput.ReadLong() // file size
+		dataOffset = dataInput.ReadLong()
+		_ = dataInput.ReadLong()
+	}
+	if err := dataInput.Err(); err != nil {
+		return UnitySerializedFile{}, fmt.Errorf("error reading header: %w", err)
+	}
+
+	file := UnitySerializedFile{Version: version, data: data, order: binary.LittleEndian}
+	if bigEndian {
+		file.order = binary.BigEndian
+	}
+	input := unityInput{DataInput: dataInput, order: file.order}
+	file.UnityVersion = input.ReadNullTerminatedString()
+	file.TargetPlatform = input.readInt32()
+	typeTrees := input.ReadBoolean()
+
+	typeCount := input.readInt32()
+	for i := 0; i < typeCount && input.Err() == nil; i++ {
+		serializedType, err := readUnitySerializedType(input, version, typeTrees)
+		if err != nil {
+			return UnitySerializedFile{}, fmt.Errorf("error reading type %d: %w", i, err)
+		}
+		file.Types = append(file.Types, serializedType)
+	}
+	if err := input.Err(); err != nil {
+		return UnitySerializedFile{}, fmt.Errorf("error reading types: %w", err)
+	}
+
+	objectCount := input.readInt32()
+	for i := 0; i < objectCount && input.Err() == nil; i++ {
+		input.align()
+		object := UnityObjectInfo{PathID: int64(input.readUint64())}
+		if version >= 22 {
+			object.Offset = int64(input.readUint64())
+		} else {
+			object.Offset = int64(input.readUint32())
+		}
+		object.Offset += dataOffset
+		object.Size = int64(input.readUint32())
+		typeID := input.readInt32()
+		object.TypeIndex = -1
+		if version < 16 {
+			// the type id is the class id of the type
+			object.ClassID = int(input.readUint16())
+			for typeIndex, serializedType := range file.Types {
+				if serializedType.ClassID == typeID {
+					object.TypeIndex = typeIndex
+					break
+				}
+			}
+		} else if typeID >= 0 && typeID < len(file.Types) {
+			object.TypeIndex = typeID
+			object.ClassID = file.Types[typeID].ClassID
+		}
+		if version < 17 {
+			_ = input.readUint16() // script type index
+		}
+		if version == 15 || version == 16 {
+			_ = input.ReadUnsignedByte() // stripped
+		}
+		file.Objects = append(file.Objects, object)
+	}
+
+	scriptCount := input.readInt32()
+	for i := 0; i < scriptCount && input.Err() == nil; i++ {
+		_ = input.readInt32() // local serialized file index
+		input.align()
+		_ = input.readUint64() // local identifier in file
+	}
+
+	externalCount := input.readInt32()
+	for i := 0; i < externalCount && input.Err() == nil; i++ {
+		_ = input.ReadNullTerminatedString()
+		input.Read(16) // guid
+		_ = input.readInt32()
+		file.Externals = append(file.Externals, input.ReadNullTerminatedString())
+	}
+	if err := input.Err(); err != nil {
+		return UnitySerializedFile{}, fmt.Errorf("error reading objects: %w", err)
+	}
+
+	return file, nil
+}
+
+func readUnitySerializedType(input unityInput, version int, typeTrees bool) (UnitySerializedType, error) {
+	serializedType := UnitySerializedType{ClassID: input.readInt32()}
+	if version >= 16 {
+		_ = input.ReadBoolean() // stripped
+	}
+	if version >= 17 {
+		_ = input.readUint16() // script type index
+	}
+	if (version < 16 && serializedType.ClassID < 0) || (version >= 16 && serializedType.ClassID == unityMonoBehaviourClassID) {
+		input.Read(16) // script id
+	}
+	input.Read(16) // old type hash
+	if !typeTrees {
+		return serializedType, input.Err()
+	}
+
+	nodes, err := readUnityTypeTree(input, version)
+	if err != nil {
+		return UnitySerializedType{}, err
+	}
+	serializedType.TypeTree = nodes
+	serializedType.ends = typeTreeEnds(nodes)
+	if version >= 21 {
+		dependencyCount := input.readLength()
+		input.Read(4 * dependencyCount)
+	}
+	return serializedType, input.Err()
+}
+
+func readUnityTypeTree(input unityInput, version int) ([]UnityTypeTreeNode, error) {
+	nodeSize := 24
+	if version >= 19 {
+		nodeSize = 32 // with the hash of the referenced type
+	}
+	nodeCount := input.readInt32()
+	stringsSize := input.readInt32()
+	if input.Err() == nil && (nodeCount <= 0 || nodeCount > input.Remaining()/nodeSize) {
+		return nil, fmt.Errorf("%w: type tree of %d nodes", ErrTruncatedData, nodeCount)
+	}
+	records := input.Read(nodeCount * nodeSize)
+	stringBuffer := input.Read(stringsSize)
+	if err := input.Err(); err != nil {
+		return nil, fmt.Errorf("error reading type tree: %w", err)
+	}
+
+	nodes := make([]UnityTypeTreeNode, nodeCount)
+	for i := range nodes {
+		record := records[i*nodeSize : (i+1)*nodeSize]
+		nodes[i] = UnityTypeTreeNode{
+			Level:    int(record[2]),
+			Type:     typeTreeString(stringBuffer, input.order.Uint32(record[4:])),
+			Name:     typeTreeString(stringBuffer, input.order.Uint32(record[8:])),
+			ByteSize: int(int32(input.order.Uint32(record[12:]))),
+			MetaFlag: int(int32(input.order.Uint32(record[20:]))),
+		}
+		// a single root, the other nodes being fields of the previous ones
+		if (i == 0) != (nodes[i].Level == 0) || (i > 0 && nodes[i].Level > nodes[i-1].Level+1) {
+			return nil, fmt.Errorf("invalid level %d of type tree node %d", nodes[i].Level, i)
+		}
+	}
+	return nodes, nil
+}
+
+func typeTreeString(stringBuffer []byte, offset uint32) string {
+	if offset&unityCommonStringFlag != 0 {
+		if str, ok := unityCommonStrings[offset&^unityCommonStringFlag]; ok {
+			return str
+		}
+		return fmt.Sprintf("unknown common string %d", offset&^unityCommonStringFlag)
+	}
+	if int64(offset) >= int64(len(stringBuffer)) {
+		return ""
+	}
+	str, _, _ := bytes.Cut(stringBuffer[offset:], []byte{0})
+	return string(str)
+}
+
+// typeTreeEnds returns, for each node, the index of the node which follows
+// its fields.
+func typeTreeEnds(nodes []UnityTypeTreeNode) []int {
+	ends := make([]int, len(nodes))
+	for i, node := range nodes {
+		end := i + 1
+		for end < len(nodes) && nodes[end].Level > node.Level {
+			end++
+		}
+		ends[i] = end
+	}
+	return ends
+}
+
+// ReadObject decodes an object with the type tree of its type, as the map of
+// its fields. Vectors are decoded as []any, maps as []any of pairs holding a
+// "first" and a "second" entry, and TypelessData as []byte.
+func (f UnitySerializedFile) ReadObject(object UnityObjectInfo) (map[string]any, error) {
+	if object.TypeIndex < 0 || object.TypeIndex >= len(f.Types) || len(f.Types[object.TypeIndex].TypeTree) == 0 {
+		return nil, fmt.Errorf("%w: object %d of class %d", ErrNoTypeTree, object.PathID, object.ClassID)
+	}
+	// checked separately, as their sum may overflow
+	if object.Offset < 0 || object.Offset > int64(len(f.data)) || object.Size < 0 || object.Size > int64(len(f.data))-object.Offset {
+		return nil, fmt.Errorf("%w: object %d out of bounds", ErrTruncatedData, object.PathID)
+	}
+
+	input := unityInput{
+		DataInput: newDataInput(f.data[object.Offset:object.Offset+object.Size], discardLogger),
+		order:     f.order,
+	}
+	value, err := input.readTypeTreeValue(f.Types[object.TypeIndex], 0)
+	if err != nil {
+		return nil, fmt.Errorf("error reading object %d: %w", object.PathID, err)
+	}
+	fields, ok := value.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("object %d of class %d is not a structure", object.PathID, object.ClassID)
+	}
+	return fields, nil
+}
+
+// readTypeTreeValue reads the value of a node of a type tree.
+func (ui unityInput) readTypeTreeValue(serializedType UnitySerializedType, index int) (any, error) {
+	nodes, ends := serializedType.TypeTree, serializedType.ends
+	node := nodes[index]
+	align := node.MetaFlag&unityAlignFlag != 0
+
+	var value any
+	switch node.Type {
+	case "bool":
+		value = ui.ReadBoolean()
+	case "SInt8":
+		value = int(int8(ui.ReadUnsignedByte()))
+	case "UInt8", "char":
+		value = int(ui.ReadUnsignedByte())
+	case "SInt16", "short":
+		value = int(int16(ui.readUint16()))
+	case "UInt16", "unsigned short":
+		value = int(ui.readUint16())
+	case "SInt32", "int":
+		value = ui.readInt32()
+	case "UInt32", "unsigned int", "Type*":
+		value = uint(ui.readUint32())
+	case "SInt64", "long long":
+		value = int64(ui.readUint64())
+	case "UInt64", "unsigned long long", "FileSize":
+		value = ui.readUint64()
+	case "float":
+		value = math.Float32frombits(ui.readUint32())
+	case "double":
+		value = math.Float64frombits(ui.readUint64())
+	case "string":
+		value = string(ui.Read(ui.readLength()))
+		align = true
+	case "TypelessData":
+		value = ui.Read(ui.readLength())
+	case "ManagedReferencesRegistry", "ReferencedObject":
+		return nil, fmt.Errorf("unsupported type tree node %s %s", node.Type, node.Name)
+	default:
+		switch {
+		case node.Type == "Array":
+			// size, then data
+			values, err := ui.readTypeTreeArray(serializedType, index+2)
+			if err != nil {
+				return nil, err
+			}
+			value = values
+		case index+1 < ends[index] && nodes[index+1].Type == "Array":
+			// vector and map: Array, then size and data
+			values, err := ui.readTypeTreeArray(serializedType, index+3)
+			if err != nil {
+				return nil, err
+			}
+			value = values
+			align = align || nodes[index+1].MetaFlag&unityAlignFlag != 0
+		default:
+			fields := map[string]any{}
+			for field := index + 1; field < ends[index] && ui.Err() == nil; field = ends[field] {
+				fieldValue, err := ui.readTypeTreeValue(serializedType, field)
+				if err != nil {
+					return nil, err
+				}
+				fields[nodes[field].Name] = fieldValue
+			}
+			value = fields
+		}
+	}
+
+	if align {
+		ui.align()
+	}
+	return value, ui.Err()
+}
+
+// readTypeTreeArray reads the elements of an array, whose type is the node of
+// the given index.
+func (ui unityInput) readTypeTreeArray(serializedType UnitySerializedType, elementIndex int) ([]any, error) {
+	length := ui.readLength()
+	if elementIndex >= len(serializedType.TypeTree) || serializedType.TypeTree[elementIndex].Level != serializedType.TypeTree[elementIndex-1].Level {
+		return nil, fmt.Errorf("invalid array type tree at node %d", elementIndex)
+	}
+	values := make([]any, 0, length)
+	for i := 0; i < length && ui.Err() == nil; i++ {
+		value, err := ui.readTypeTreeValue(serializedType, elementIndex)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, ui.Err()
+}
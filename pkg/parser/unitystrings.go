@@ -0,0 +1,37 @@
+package parser
+
+// unityCommonStrings are the strings built in Unity that type trees refer to
+// by their offset in the concatenation of the strings, each followed by a NUL
+// byte, rather than storing them.
+var unityCommonStrings = func() map[uint32]string {
+	strings := []string{
+		"AABB", "AnimationClip", "AnimationCurve", "AnimationState", "Array", "Base",
+		"BitField", "bitset", "bool", "char", "ColorRGBA", "Component", "data", "deque",
+		"double", "dynamic_array", "FastPropertyName", "first", "float", "Font",
+		"GameObject", "Generic Mono", "GradientNEW", "GUID", "GUIStyle", "int", "list",
+		"long long", "map", "Matrix4x4f", "MdFour", "MonoBehaviour", "MonoScript",
+		"m_ByteSize", "m_Curve", "m_EditorClassIdentifier", "m_EditorHideFlags",
+		"m_Enabled", "m_ExtensionPtr", "m_GameObject", "m_Index", "m_IsArray",
+		"m_IsStatic", "m_MetaFlag", "m_Name", "m_ObjectHideFlags", "m_PrefabInternal",
+		"m_PrefabParentObject", "m_Script", "m_StaticEditorFlags", "m_Type", "m_Version",
+		"Object", "pair", "PPtr<Component>", "PPtr<GameObject>", "PPtr<Material>",
+		"PPtr<MonoBehaviour>", "PPtr<MonoScript>", "PPtr<Object>", "PPtr<Prefab>",
+		"PPtr<Sprite>", "PPtr<TextAsset>", "PPtr<Texture>", "PPtr<Texture2D>",
+		"PPtr<Transform>", "Prefab", "Quaternionf", "Rectf", "RectInt", "RectOffset",
+		"second", "set", "short", "size", "SInt16", "SInt32", "SInt64", "SInt8",
+		"staticvector", "string", "TextAsset", "TextMesh", "Texture", "Texture2D",
+		"Transform", "TypelessData", "UInt16", "UInt32", "UInt64", "UInt8",
+		"unsigned int", "unsigned long long", "unsigned short", "vector", "Vector2f",
+		"Vector3f", "Vector4f", "m_ScriptingClassIdentifier", "Gradient", "Type*",
+		"int2_storage", "int3_storage", "BoundsInt", "m_CorrespondingSourceObject",
+		"m_PrefabInstance", "m_PrefabAsset", "FileSize", "Hash128", "RenderingLayerMask",
+	}
+
+	commonStrings := make(map[uint32]string, len(strings))
+	offset := uint32(0)
+	for _, str := range strings {
+		commonStrings[offset] = str
+		offset += uint32(len(str)) + 1
+	}
+	return commonStrings
+}()
@@ -0,0 +1,57 @@
+package parser
+
+import (
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// decodeModifiedUTF8 decodes the strings of AS3 readUTF, in modified UTF-8:
+// NUL is encoded on the 2 bytes C0 80, and characters outside of the Basic
+// Multilingual Plane as the 2 UTF-16 surrogates of their pair, on 3 bytes
+// each (CESU-8). Valid UTF-8, as written by other tools, is returned as is,
+// and the bytes invalid in both encodings are replaced by U+FFFD.
+func decodeModifiedUTF8(data []byte) string {
+	if utf8.Valid(data) {
+		return string(data)
+	}
+
+	var decoded strings.Builder
+	decoded.Grow(len(data))
+	for i := 0; i < len(data); {
+		if data[i] == 0xC0 && i+1 < len(data) && data[i+1] == 0x80 {
+			decoded.WriteByte(0)
+			i += 2
+			continue
+		}
+
+		high, ok := decodeSurrogate(data[i:])
+		if !ok {
+			r, size := utf8.DecodeRune(data[i:])
+			decoded.WriteRune(r) // utf8.RuneError for invalid bytes
+			i += size
+			continue
+		}
+
+		low, ok := decodeSurrogate(data[i+3:])
+		r := utf16.DecodeRune(high, low)
+		if !ok || r == utf8.RuneError {
+			// unpaired surrogate
+			decoded.WriteRune(utf8.RuneError)
+			i += 3
+			continue
+		}
+		decoded.WriteRune(r)
+		i += 6
+	}
+	return decoded.String()
+}
+
+// decodeSurrogate decodes the UTF-16 surrogate encoded on the first 3 bytes of
+// data, if any.
+func decodeSurrogate(data []byte) (rune, bool) {
+	if len(data) < 3 || data[0] != 0xED || data[1]&0xE0 != 0xA0 || data[2]&0xC0 != 0x80 {
+		return 0, false
+	}
+	return 0xD000 | rune(data[1]&0x3F)<<6 | rune(data[2]&0x3F), true
+}
@@ -0,0 +1,36 @@
+package parser
+
+import "testing"
+
+func TestDecodeModifiedUTF8(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{name: "ascii", data: []byte("ui.common.ok"), want: "ui.common.ok"},
+		{name: "empty", data: []byte{}, want: ""},
+		{name: "utf-8", data: []byte("Épée"), want: "Épée"},
+		{name: "utf-8 outside of the bmp", data: []byte("a😀b"), want: "a😀b"},
+		{name: "nul", data: []byte{'a', 0xC0, 0x80, 'b'}, want: "a\x00b"},
+		{name: "surrogate pair", data: []byte{'a', 0xED, 0xA0, 0xBD, 0xED, 0xB8, 0x80, 'b'}, want: "a😀b"},
+		{name: "surrogate pair and nul", data: []byte{0xC0, 0x80, 0xED, 0xA0, 0xBD, 0xED, 0xB8, 0x80}, want: "\x00😀"},
+		{name: "surrogate pair and utf-8", data: []byte{0xC3, 0xA9, 0xED, 0xA0, 0xBD, 0xED, 0xB8, 0x80}, want: "é😀"},
+		{name: "unpaired high surrogate", data: []byte{0xED, 0xA0, 0xBD, 'b'}, want: "�b"},
+		{name: "unpaired low surrogate", data: []byte{'a', 0xED, 0xB8, 0x80}, want: "a�"},
+		{name: "reversed surrogates", data: []byte{0xED, 0xB8, 0x80, 0xED, 0xA0, 0xBD}, want: "��"},
+		{name: "invalid byte", data: []byte{'a', 0xFF, 'b'}, want: "a�b"},
+		{name: "truncated sequence", data: []byte{'a', 0xC3}, want: "a�"},
+		{name: "truncated nul", data: []byte{'a', 0xC0}, want: "a�"},
+		{name: "truncated surrogate", data: []byte{0xED, 0xA0}, want: "��"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := decodeModifiedUTF8(test.data)
+			if got != test.want {
+				t.Errorf("decodeModifiedUTF8(% X) = %q, want %q", test.data, got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package parser
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+type GameVersion int
+
+const (
+	UnknownGameVersion GameVersion = iota
+	DofusRetro
+	Dofus2
+	DofusTouch
+	Dofus3
+)
+
+func (v GameVersion) String() string {
+	switch v {
+	case DofusRetro:
+		return "DofusRetro"
+	case Dofus2:
+		return "Dofus2"
+	case DofusTouch:
+		return "DofusTouch"
+	case Dofus3:
+		return "Dofus3"
+	default:
+		return "Unknown"
+	}
+}
+
+func (v GameVersion) MarshalJSON() ([]byte, error) {
+	return []byte(fmt.Sprintf("%q", v.String())), nil
+}
+
+// DetectGameVersion guesses which game a data folder belongs to from the files
+// it contains:
+//   - Dofus 2 ships D2O modules (starting with the "D2O" header) in common/,
+//   - Dofus Touch ships the same modules as JSON in common/,
+//   - Dofus Retro ships SWF lang files in lang/swf/,
+//   - Dofus 3 ships Unity asset bundles (starting with the "UnityFS"
+//     signature), whose folder is StreamingAssets/Content/Data/.
+func DetectGameVersion(dataFolderPath string) (GameVersion, error) {
+	folderInfo, err := os.Stat(dataFolderPath)
+	if err != nil {
+		return UnknownGameVersion, fmt.Errorf("error reading data folder: %w", err)
+	}
+	if !folderInfo.IsDir() {
+		return UnknownGameVersion, fmt.Errorf("data folder is not a directory: %s", dataFolderPath)
+	}
+
+	commonFolderPath := filepath.Join(dataFolderPath, "common")
+	if hasHeader(filepath.Join(commonFolderPath, "Items.d2o"), "D2O") {
+		return Dofus2, nil
+	}
+
+	if fileExists(filepath.Join(commonFolderPath, "Items.json")) {
+		return DofusTouch, nil
+	}
+
+	swfFiles, err := filepath.Glob(filepath.Join(dataFolderPath, "lang", "swf", "lang_*.swf"))
+	if err != nil {
+		return UnknownGameVersion, fmt.Errorf("error listing lang files: %w", err)
+	}
+	if len(swfFiles) > 0 {
+		return DofusRetro, nil
+	}
+
+	bundleFiles, err := filepath.Glob(filepath.Join(dataFolderPath, "*.bundle"))
+	if err != nil {
+		return UnknownGameVersion, fmt.Errorf("error listing bundle files: %w", err)
+	}
+	if len(bundleFiles) > 0 && hasHeader(bundleFiles[0], unityBundleSignature) {
+		return Dofus3, nil
+	}
+
+	return UnknownGameVersion, nil
+}
+
+func hasHeader(filePath, header string) bool {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	fileHeader := make([]byte, len(header))
+	_, err = io.ReadFull(file, fileHeader)
+	return err == nil && string(fileHeader) == header
+}
+
+func fileExists(filePath string) bool {
+	fileInfo, err := os.Stat(filePath)
+	return err == nil && !fileInfo.IsDir()
+}
@@ -0,0 +1,70 @@
+package pathfinding
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+const binaryVersion = 1
+
+// WriteBinary writes maps and transitions in a compact binary form, big
+// endian like the game files:
+//
+//	"DPG" version:u8
+//	mapCount:u32, then per map
+//	    mapId:u32 walkable:[70]u8 walkableInFight:[70]u8 los:[70]u8
+//	transitionCount:u32, then per transition
+//	    fromMapId:u32 toMapId:u32 side:u8 cellCount:u16 cellIds:[cellCount]u16
+//
+// The cell flags are bit sets, bit i%8 of byte i/8 being the flag of cell i.
+// Neighbours are not written: they follow from the walkable flags and
+// NeighbourCell. Sides are numbered right, bottom, left, top from 0.
+func WriteBinary(w io.Writer, maps []MapGraph, transitions []Transition) error {
+	writer := bufio.NewWriter(w)
+	write := func(value any) {
+		// errors are sticky in bufio.Writer, returned by Flush
+		_ = binary.Write(writer, binary.BigEndian, value)
+	}
+
+	writer.WriteString("DPG")
+	write(uint8(binaryVersion))
+
+	write(uint32(len(maps)))
+	for _, graph := range maps {
+		if len(graph.Cells) != cellsCount {
+			return fmt.Errorf("map %d has %d cells instead of %d", graph.MapId, len(graph.Cells), cellsCount)
+		}
+		var walkable, walkableInFight, los [cellsCount / 8]uint8
+		for i, cell := range graph.Cells {
+			setBit(walkable[:], i, cell.Walkable)
+			setBit(walkableInFight[:], i, cell.WalkableInFight)
+			setBit(los[:], i, cell.LineOfSight)
+		}
+		write(uint32(graph.MapId))
+		write(walkable)
+		write(walkableInFight)
+		write(los)
+	}
+
+	sides := map[string]uint8{SideRight: 0, SideBottom: 1, SideLeft: 2, SideTop: 3}
+	write(uint32(len(transitions)))
+	for _, transition := range transitions {
+		write(uint32(transition.FromMapId))
+		write(uint32(transition.ToMapId))
+		write(sides[transition.Side])
+		write(uint16(len(transition.Cells)))
+		for _, cellId := range transition.Cells {
+			write(uint16(cellId))
+		}
+	}
+
+	return writer.Flush()
+}
+
+func setBit(bits []uint8, i int, set bool) {
+	if set {
+		bits[i/8] |= 1 << (i % 8)
+	}
+}
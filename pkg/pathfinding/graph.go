@@ -0,0 +1,200 @@
+// Package pathfinding builds the movement graphs of Dofus 2 maps from their
+// DLM cells: the cells reachable from each cell of a map, and the transitions
+// between neighbouring maps.
+package pathfinding
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/brequet/dofus-data-file-parser/pkg/parser"
+)
+
+const (
+	mapWidth   = 14
+	cellsCount = 560
+)
+
+// Directions, see DirectionsEnum.as. Odd directions cross an edge of the cell
+// diamond and are the only ones allowed in fights; even directions cross a
+// corner.
+const (
+	DirectionRight = iota
+	DirectionDownRight
+	DirectionDown
+	DirectionDownLeft
+	DirectionLeft
+	DirectionUpLeft
+	DirectionUp
+	DirectionUpRight
+)
+
+// MapGraph is the movement graph of a map.
+type MapGraph struct {
+	MapId uint        `json:"mapId"`
+	Cells []GraphCell `json:"cells"` // indexed by cell id
+}
+
+type GraphCell struct {
+	CellId          int  `json:"cellId"`
+	Walkable        bool `json:"walkable"` // out of fights
+	WalkableInFight bool `json:"walkableInFight"`
+	LineOfSight     bool `json:"los"` // does not block the line of sight
+	Floor           int  `json:"floor"`
+	Speed           int  `json:"speed"`
+	// Neighbours are the walkable cells reachable in one step out of fights,
+	// an empty list for the cells which are not walkable.
+	Neighbours []Neighbour `json:"neighbours"`
+}
+
+type Neighbour struct {
+	CellId    int `json:"cellId"`
+	Direction int `json:"direction"`
+}
+
+// BuildMapGraph returns the movement graph of a map.
+func BuildMapGraph(dlmMap parser.DlmMap) MapGraph {
+	graph := MapGraph{MapId: dlmMap.Id, Cells: make([]GraphCell, 0, len(dlmMap.Cells))}
+	for cellId, cell := range dlmMap.Cells {
+		graph.Cells = append(graph.Cells, GraphCell{
+			CellId:          cellId,
+			Walkable:        isWalkable(cell),
+			WalkableInFight: cell.Mov && !cell.NonWalkableDuringFight,
+			LineOfSight:     cell.Los,
+			Floor:           cell.Floor,
+			Speed:           cell.Speed,
+			Neighbours:      make([]Neighbour, 0),
+		})
+	}
+
+	for cellId := range graph.Cells {
+		if !graph.Cells[cellId].Walkable {
+			continue
+		}
+		for direction := DirectionRight; direction <= DirectionUpRight; direction++ {
+			neighbourId, ok := NeighbourCell(cellId, direction)
+			if ok && neighbourId < len(graph.Cells) && graph.Cells[neighbourId].Walkable {
+				graph.Cells[cellId].Neighbours = append(graph.Cells[cellId].Neighbours, Neighbour{CellId: neighbourId, Direction: direction})
+			}
+		}
+	}
+	return graph
+}
+
+func isWalkable(cell parser.Cell) bool {
+	return cell.Mov && !cell.NonWalkableDuringRP
+}
+
+// NeighbourCell returns the cell next to a cell in a direction, false when it
+// is out of the map. Cells are laid out on 40 rows of 14, odd rows being
+// shifted right by half a cell.
+func NeighbourCell(cellId, direction int) (int, bool) {
+	row, column := cellId/mapWidth, cellId%mapWidth
+	// column of the cells of the rows above and below on the left, the one on
+	// the right being the next
+	leftColumn := column - 1
+	if row%2 == 1 {
+		leftColumn = column
+	}
+
+	switch direction {
+	case DirectionRight:
+		column++
+	case DirectionLeft:
+		column--
+	case DirectionDown:
+		row += 2
+	case DirectionUp:
+		row -= 2
+	case DirectionDownRight:
+		row, column = row+1, leftColumn+1
+	case DirectionDownLeft:
+		row, column = row+1, leftColumn
+	case DirectionUpRight:
+		row, column = row-1, leftColumn+1
+	case DirectionUpLeft:
+		row, column = row-1, leftColumn
+	default:
+		return 0, false
+	}
+
+	if row < 0 || row >= cellsCount/mapWidth || column < 0 || column >= mapWidth {
+		return 0, false
+	}
+	return row*mapWidth + column, true
+}
+
+// Map sides, in the direction the character leaves the map to.
+const (
+	SideRight  = "right"
+	SideBottom = "bottom"
+	SideLeft   = "left"
+	SideTop    = "top"
+)
+
+// Transition is an edge of the world graph, from a map to its neighbour on a
+// side, through the cells from which the character can change map.
+type Transition struct {
+	FromMapId uint   `json:"fromMapId"`
+	ToMapId   uint   `json:"toMapId"`
+	Side      string `json:"side"`
+	Cells     []int  `json:"cells"`
+}
+
+// Transitions returns the edges of the world graph leaving a map, read from
+// the map change flags of its walkable border cells.
+func Transitions(dlmMap parser.DlmMap) []Transition {
+	neighbours := map[string]int{
+		SideRight:  dlmMap.RightNeighbourId,
+		SideBottom: dlmMap.BottomNeighbourId,
+		SideLeft:   dlmMap.LeftNeighbourId,
+		SideTop:    dlmMap.TopNeighbourId,
+	}
+
+	cellsBySide := map[string][]int{}
+	for cellId, cell := range dlmMap.Cells {
+		if !isWalkable(cell) || cell.MapChangeData == 0 {
+			continue
+		}
+		for _, side := range mapChangeSides(cellId, cell.MapChangeData) {
+			cellsBySide[side] = append(cellsBySide[side], cellId)
+		}
+	}
+
+	transitions := make([]Transition, 0, len(cellsBySide))
+	for side, cells := range cellsBySide {
+		neighbourId := neighbours[side]
+		if neighbourId <= 0 {
+			continue
+		}
+		transitions = append(transitions, Transition{FromMapId: dlmMap.Id, ToMapId: uint(neighbourId), Side: side, Cells: cells})
+	}
+	slices.SortFunc(transitions, func(a, b Transition) int { return cmp.Compare(a.Side, b.Side) })
+	return transitions
+}
+
+// mapChangeSides returns the sides a cell leads to, the bits of its map
+// change data being the directions the character can leave the map in: a
+// diagonal direction only leads out of the map on the border it points to.
+func mapChangeSides(cellId, mapChangeData int) []string {
+	has := func(direction int) bool { return mapChangeData&(1<<direction) != 0 }
+	onRightBorder := (cellId+1)%(2*mapWidth) == 0
+	onLeftBorder := cellId%(2*mapWidth) == 0
+	onTopBorder := cellId < 2*mapWidth
+	onBottomBorder := cellId >= cellsCount-2*mapWidth
+
+	sides := make([]string, 0, 1)
+	if has(DirectionRight) || onRightBorder && (has(DirectionDownRight) || has(DirectionUpRight)) {
+		sides = append(sides, SideRight)
+	}
+	if has(DirectionDown) || onBottomBorder && (has(DirectionDownRight) || has(DirectionDownLeft)) {
+		sides = append(sides, SideBottom)
+	}
+	if has(DirectionLeft) || onLeftBorder && (has(DirectionDownLeft) || has(DirectionUpLeft)) {
+		sides = append(sides, SideLeft)
+	}
+	if has(DirectionUp) || onTopBorder && (has(DirectionUpLeft) || has(DirectionUpRight)) {
+		sides = append(sides, SideTop)
+	}
+	return sides
+}
@@ -0,0 +1,394 @@
+package retro
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// Object is an ActionScript object, whose members are the data of lang files.
+type Object = map[string]any
+
+// ActionScript 1/2 bytecode actions used by lang files.
+const (
+	actionEnd           = 0x00
+	actionPop           = 0x17
+	actionGetVariable   = 0x1C
+	actionSetVariable   = 0x1D
+	actionDefineLocal   = 0x3C
+	actionNewObject     = 0x40
+	actionInitArray     = 0x42
+	actionInitObject    = 0x43
+	actionGetMember     = 0x4E
+	actionSetMember     = 0x4F
+	actionStoreRegister = 0x87
+	actionConstantPool  = 0x88
+	actionPush          = 0x96
+)
+
+// machine runs the bytecode of lang files, which only builds objects and
+// arrays and assigns them to variables, without any control flow.
+type machine struct {
+	variables Object
+	stack     []any
+	constants []string
+	registers [256]any
+}
+
+func newMachine() *machine {
+	return &machine{variables: Object{}}
+}
+
+func (m *machine) push(value any) {
+	m.stack = append(m.stack, value)
+}
+
+func (m *machine) pop() (any, error) {
+	if len(m.stack) == 0 {
+		return nil, fmt.Errorf("%w: empty stack", ErrInvalidSwf)
+	}
+	value := m.stack[len(m.stack)-1]
+	m.stack = m.stack[:len(m.stack)-1]
+	return value, nil
+}
+
+func (m *machine) popCount() (int, error) {
+	value, err := m.pop()
+	if err != nil {
+		return 0, err
+	}
+	count, ok := toNumber(value)
+	if !ok || count < 0 || int(count) > len(m.stack) {
+		return 0, fmt.Errorf("%w: invalid count %v", ErrInvalidSwf, value)
+	}
+	return int(count), nil
+}
+
+// run executes the actions of a DoAction tag.
+func (m *machine) run(actions []byte) error {
+	for position := 0; position < len(actions); {
+		code := actions[position]
+		position++
+		var payload []byte
+		if code >= 0x80 {
+			if position+2 > len(actions) {
+				return fmt.Errorf("%w: truncated action %#x", ErrInvalidSwf, code)
+			}
+			length := int(binary.LittleEndian.Uint16(actions[position:]))
+			position += 2
+			if position+length > len(actions) {
+				return fmt.Errorf("%w: action %#x of %d bytes at %#x", ErrInvalidSwf, code, length, position)
+			}
+			payload = actions[position : position+length]
+			position += length
+		}
+
+		err := m.execute(code, payload)
+		if err != nil {
+			return fmt.Errorf("error executing action %#x at %#x: %w", code, position, err)
+		}
+		if code == actionEnd {
+			return nil
+		}
+	}
+	return nil
+}
+
+func (m *machine) execute(code byte, payload []byte) error {
+	switch code {
+	case actionEnd:
+		return nil
+	case actionConstantPool:
+		return m.readConstantPool(payload)
+	case actionPush:
+		return m.readPush(payload)
+	case actionPop:
+		_, err := m.pop()
+		return err
+	case actionStoreRegister:
+		if len(payload) < 1 || len(m.stack) == 0 {
+			return fmt.Errorf("%w: invalid register store", ErrInvalidSwf)
+		}
+		m.registers[payload[0]] = m.stack[len(m.stack)-1]
+		return nil
+	case actionGetVariable:
+		name, err := m.pop()
+		if err != nil {
+			return err
+		}
+		m.push(m.variables[toKey(name)])
+		return nil
+	case actionSetVariable, actionDefineLocal:
+		value, err := m.pop()
+		if err != nil {
+			return err
+		}
+		name, err := m.pop()
+		if err != nil {
+			return err
+		}
+		m.variables[toKey(name)] = value
+		return nil
+	case actionGetMember:
+		name, err := m.pop()
+		if err != nil {
+			return err
+		}
+		object, err := m.pop()
+		if err != nil {
+			return err
+		}
+		m.push(getMember(object, toKey(name)))
+		return nil
+	case actionSetMember:
+		value, err := m.pop()
+		if err != nil {
+			return err
+		}
+		name, err := m.pop()
+		if err != nil {
+			return err
+		}
+		object, err := m.pop()
+		if err != nil {
+			return err
+		}
+		return setMember(object, toKey(name), value)
+	case actionInitObject:
+		count, err := m.popCount()
+		if err != nil {
+			return err
+		}
+		object := make(Object, count)
+		for i := 0; i < count; i++ {
+			value, err := m.pop()
+			if err != nil {
+				return err
+			}
+			name, err := m.pop()
+			if err != nil {
+				return err
+			}
+			object[toKey(name)] = value
+		}
+		m.push(object)
+		return nil
+	case actionInitArray:
+		count, err := m.popCount()
+		if err != nil {
+			return err
+		}
+		array := make([]any, count)
+		for i := range array {
+			array[i], err = m.pop()
+			if err != nil {
+				return err
+			}
+		}
+		m.push(&array)
+		return nil
+	case actionNewObject:
+		name, err := m.pop()
+		if err != nil {
+			return err
+		}
+		count, err := m.popCount()
+		if err != nil {
+			return err
+		}
+		arguments := make([]any, count)
+		for i := range arguments {
+			arguments[i], err = m.pop()
+			if err != nil {
+				return err
+			}
+		}
+		if toKey(name) == "Array" {
+			m.push(&arguments)
+		} else {
+			m.push(Object{})
+		}
+		return nil
+	default:
+		return fmt.Errorf("%w: unsupported action", ErrInvalidSwf)
+	}
+}
+
+func (m *machine) readConstantPool(payload []byte) error {
+	if len(payload) < 2 {
+		return fmt.Errorf("%w: truncated constant pool", ErrInvalidSwf)
+	}
+	count := int(binary.LittleEndian.Uint16(payload))
+	m.constants = make([]string, 0, count)
+	rest := payload[2:]
+	for i := 0; i < count; i++ {
+		end := bytes.IndexByte(rest, 0)
+		if end < 0 {
+			return fmt.Errorf("%w: truncated constant %d", ErrInvalidSwf, i)
+		}
+		m.constants = append(m.constants, string(rest[:end]))
+		rest = rest[end+1:]
+	}
+	return nil
+}
+
+func (m *machine) readPush(payload []byte) error {
+	for len(payload) > 0 {
+		valueType := payload[0]
+		payload = payload[1:]
+		size := 0
+		switch valueType {
+		case 0: // string
+			end := bytes.IndexByte(payload, 0)
+			if end < 0 {
+				return fmt.Errorf("%w: truncated string", ErrInvalidSwf)
+			}
+			m.push(string(payload[:end]))
+			size = end + 1
+		case 1: // float
+			size = 4
+			if len(payload) >= size {
+				m.push(float64(math.Float32frombits(binary.LittleEndian.Uint32(payload))))
+			}
+		case 2, 3: // null, undefined
+			m.push(nil)
+		case 4: // register
+			size = 1
+			if len(payload) >= size {
+				m.push(m.registers[payload[0]])
+			}
+		case 5: // boolean
+			size = 1
+			if len(payload) >= size {
+				m.push(payload[0] != 0)
+			}
+		case 6: // double, whose 32 bits halves are swapped
+			size = 8
+			if len(payload) >= size {
+				bits := uint64(binary.LittleEndian.Uint32(payload))<<32 | uint64(binary.LittleEndian.Uint32(payload[4:]))
+				m.push(math.Float64frombits(bits))
+			}
+		case 7: // integer
+			size = 4
+			if len(payload) >= size {
+				m.push(int(int32(binary.LittleEndian.Uint32(payload))))
+			}
+		case 8, 9: // constant of the pool, by 8 or 16 bits index
+			size = int(valueType) - 7
+			if len(payload) >= size {
+				index := int(payload[0])
+				if valueType == 9 {
+					index = int(binary.LittleEndian.Uint16(payload))
+				}
+				if index >= len(m.constants) {
+					return fmt.Errorf("%w: constant %d out of the %d of the pool", ErrInvalidSwf, index, len(m.constants))
+				}
+				m.push(m.constants[index])
+			}
+		default:
+			return fmt.Errorf("%w: unsupported push type %d", ErrInvalidSwf, valueType)
+		}
+		if len(payload) < size {
+			return fmt.Errorf("%w: truncated push of type %d", ErrInvalidSwf, valueType)
+		}
+		payload = payload[size:]
+	}
+	return nil
+}
+
+// getMember returns a member of an object or an element of an array, nil when
+// there is none.
+func getMember(object any, name string) any {
+	switch object := object.(type) {
+	case Object:
+		return object[name]
+	case *[]any:
+		index, err := strconv.Atoi(name)
+		if err == nil && index >= 0 && index < len(*object) {
+			return (*object)[index]
+		}
+	}
+	return nil
+}
+
+// setMember sets a member of an object or an element of an array, which grows
+// as needed.
+func setMember(object any, name string, value any) error {
+	switch object := object.(type) {
+	case Object:
+		object[name] = value
+		return nil
+	case *[]any:
+		index, err := strconv.Atoi(name)
+		if err != nil || index < 0 || index > 1<<20 {
+			return fmt.Errorf("%w: invalid array index %q", ErrInvalidSwf, name)
+		}
+		for len(*object) <= index {
+			*object = append(*object, nil)
+		}
+		(*object)[index] = value
+		return nil
+	default:
+		return fmt.Errorf("%w: setting member %q of %T", ErrInvalidSwf, name, object)
+	}
+}
+
+// toKey converts a value to the string ActionScript uses as member name.
+func toKey(value any) string {
+	switch value := value.(type) {
+	case string:
+		return value
+	case int:
+		return strconv.Itoa(value)
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(value)
+	case nil:
+		return "null"
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+func toNumber(value any) (float64, bool) {
+	switch value := value.(type) {
+	case int:
+		return float64(value), true
+	case float64:
+		return value, true
+	case string:
+		number, err := strconv.ParseFloat(value, 64)
+		return number, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// exportValue replaces the arrays, shared by pointer to be filled by
+// SetMember, by their content, for the values to be marshalled to JSON.
+func exportValue(value any) any {
+	switch value := value.(type) {
+	case Object:
+		exported := make(Object, len(value))
+		for name, member := range value {
+			exported[name] = exportValue(member)
+		}
+		return exported
+	case *[]any:
+		exported := make([]any, len(*value))
+		for i, element := range *value {
+			exported[i] = exportValue(element)
+		}
+		return exported
+	case float64:
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			return nil
+		}
+		return value
+	default:
+		return value
+	}
+}
@@ -0,0 +1,90 @@
+package retro
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+)
+
+// LangFile is a lang file of the lang/swf folder, named
+// <name>_<locale>_<version>.swf, e.g. items_fr_412.swf.
+type LangFile struct {
+	Path    string
+	Name    string
+	Locale  string
+	Version int
+}
+
+var langFileNamePattern = regexp.MustCompile(`^([a-z0-9]+)_([a-z]{2})_(\d+)\.swf$`)
+
+// LatestLangFiles lists the lang files of a Dofus Retro data folder, keeping
+// the highest version of each name and locale, as the game does when several
+// updates were downloaded. Files are sorted by locale, then name.
+func LatestLangFiles(dataFolderPath string) ([]LangFile, error) {
+	langFolderPath := filepath.Join(dataFolderPath, "lang", "swf")
+	entries, err := os.ReadDir(langFolderPath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading lang folder: %w", err)
+	}
+
+	latest := map[string]LangFile{}
+	for _, entry := range entries {
+		matches := langFileNamePattern.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || matches == nil {
+			continue
+		}
+		version, err := strconv.Atoi(matches[3])
+		if err != nil {
+			continue
+		}
+
+		file := LangFile{Path: filepath.Join(langFolderPath, entry.Name()), Name: matches[1], Locale: matches[2], Version: version}
+		key := file.Locale + "/" + file.Name
+		if previous, ok := latest[key]; !ok || previous.Version < file.Version {
+			latest[key] = file
+		}
+	}
+
+	files := make([]LangFile, 0, len(latest))
+	for _, file := range latest {
+		files = append(files, file)
+	}
+	slices.SortFunc(files, func(a, b LangFile) int {
+		if a.Locale != b.Locale {
+			return cmp.Compare(a.Locale, b.Locale)
+		}
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return files, nil
+}
+
+// ProcessLangFile parses a lang file, returning the variables its code
+// assigns, e.g. "I" for the items, as JSON compatible values.
+func ProcessLangFile(filePath string) (Object, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading file: %w", err)
+	}
+	return ParseLang(data)
+}
+
+// ParseLang parses the content of a lang file, see ProcessLangFile.
+func ParseLang(data []byte) (Object, error) {
+	doActions, err := readDoActions(data)
+	if err != nil {
+		return nil, err
+	}
+
+	m := newMachine()
+	for _, actions := range doActions {
+		err = m.run(actions)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return exportValue(m.variables).(Object), nil
+}
@@ -0,0 +1,58 @@
+// Package retro parses the data of Dofus Retro (1.x), whose lang files are
+// SWF movies holding ActionScript code which assigns the data to variables.
+package retro
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/brequet/dofus-data-file-parser/pkg/parser"
+)
+
+// ErrInvalidSwf is returned for data which is not an SWF movie or is
+// truncated.
+var ErrInvalidSwf = errors.New("invalid swf")
+
+const doActionTag = 12
+
+// readDoActions returns the ActionScript bytecode of the DoAction tags of an
+// SWF movie, in order.
+func readDoActions(data []byte) ([][]byte, error) {
+	_, reader, err := parser.ReadSwfHeader(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSwf, err)
+	}
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("%w: error decompressing swf: %w", ErrInvalidSwf, err)
+	}
+
+	position := 0
+	doActions := make([][]byte, 0)
+	for position+2 <= len(body) {
+		codeAndLength := binary.LittleEndian.Uint16(body[position:])
+		position += 2
+		code, length := int(codeAndLength>>6), int(codeAndLength&0x3F)
+		if length == 0x3F {
+			if position+4 > len(body) {
+				return nil, fmt.Errorf("%w: truncated tag header at %#x", ErrInvalidSwf, position)
+			}
+			length = int(binary.LittleEndian.Uint32(body[position:]))
+			position += 4
+		}
+		if length < 0 || position+length > len(body) {
+			return nil, fmt.Errorf("%w: tag %d of %d bytes at %#x", ErrInvalidSwf, code, length, position)
+		}
+
+		if code == doActionTag {
+			doActions = append(doActions, body[position:position+length])
+		}
+		if code == 0 { // End
+			break
+		}
+		position += length
+	}
+	return doActions, nil
+}
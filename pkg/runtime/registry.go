@@ -0,0 +1,73 @@
+package runtime
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/brequet/dofus-data-file-parser/pkg/parser"
+)
+
+// ClassRegistry maps D2O class names to the generated Go struct types.
+type ClassRegistry struct {
+	types map[string]reflect.Type
+}
+
+func NewClassRegistry() *ClassRegistry {
+	return &ClassRegistry{
+		types: map[string]reflect.Type{},
+	}
+}
+
+// Register associates a class name with the type of the given struct value.
+func (r *ClassRegistry) Register(className string, prototype any) {
+	t := reflect.TypeOf(prototype)
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	r.types[className] = t
+}
+
+// New returns a pointer to a zero-valued instance of the struct registered for
+// the class name.
+func (r *ClassRegistry) New(className string) (any, bool) {
+	t, ok := r.types[className]
+	if !ok {
+		return nil, false
+	}
+	return reflect.New(t).Interface(), true
+}
+
+// Populate returns a pointer to an instance of the struct registered for the
+// class name, filled from a parsed object.
+func (r *ClassRegistry) Populate(className string, obj parser.Object) (any, error) {
+	instance, ok := r.New(className)
+	if !ok {
+		return nil, fmt.Errorf("class not registered: %s", className)
+	}
+
+	if objectMap, ok := obj.(map[string]any); ok {
+		if classType, ok := objectMap["ClassType_"]; ok && classType != className {
+			return nil, fmt.Errorf("object class %v does not match %s", classType, className)
+		}
+	}
+
+	// the objects of fields typed as the interface of an extended class are
+	// decoded as the struct of their own class
+	err := parser.DecodeInto(obj, instance, parser.DecodeOptions{Classes: r})
+	if err != nil {
+		return nil, fmt.Errorf("error populating %s: %w", className, err)
+	}
+
+	return instance, nil
+}
+
+// Decode returns a pointer to an instance of the struct registered for the
+// class of a parsed object, named by its "ClassType_" entry, filled from it.
+func (r *ClassRegistry) Decode(obj parser.Object) (any, error) {
+	objectMap, ok := obj.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("expected object, got %T", obj)
+	}
+	className, _ := objectMap["ClassType_"].(string)
+	return r.Populate(className, obj)
+}
@@ -0,0 +1,96 @@
+package runtime_test
+
+import (
+	"testing"
+
+	"github.com/brequet/dofus-data-file-parser/pkg/parser"
+	"github.com/brequet/dofus-data-file-parser/pkg/runtime"
+)
+
+// The types are those generated for the classes, hierarchy and registry of an
+// item whose possible effects are polymorphic.
+
+type Item struct {
+	Id              int                  `json:"id"`
+	PossibleEffects []EffectInstanceType `json:"possibleEffects"`
+	BestEffect      EffectInstanceType   `json:"bestEffect"`
+}
+
+type EffectInstance struct {
+	EffectId int `json:"effectId"`
+}
+
+type EffectInstanceDice struct {
+	EffectInstance
+	DiceNum  uint `json:"diceNum"`
+	DiceSide uint `json:"diceSide"`
+}
+
+type EffectInstanceType interface {
+	GetEffectInstance() *EffectInstance
+}
+
+func (c *EffectInstance) GetEffectInstance() *EffectInstance {
+	return c
+}
+
+func newRegistry() *runtime.ClassRegistry {
+	registry := runtime.NewClassRegistry()
+	registry.Register("EffectInstance", EffectInstance{})
+	registry.Register("EffectInstanceDice", EffectInstanceDice{})
+	registry.Register("Item", Item{})
+	return registry
+}
+
+func TestDecodePolymorphicVector(t *testing.T) {
+	object := parser.Object(map[string]any{
+		"ClassType_": "Item",
+		"id":         1,
+		"possibleEffects": []any{
+			map[string]any{"ClassType_": "EffectInstance", "effectId": 10},
+			map[string]any{"ClassType_": "EffectInstanceDice", "effectId": 20, "diceNum": uint(2), "diceSide": uint(6)},
+			nil,
+		},
+		"bestEffect": map[string]any{"ClassType_": "EffectInstanceDice", "effectId": 30, "diceNum": uint(1), "diceSide": uint(4)},
+	})
+
+	decoded, err := newRegistry().Decode(object)
+	if err != nil {
+		t.Fatal(err)
+	}
+	item := decoded.(*Item)
+
+	if len(item.PossibleEffects) != 3 {
+		t.Fatalf("%d possible effects, want 3", len(item.PossibleEffects))
+	}
+	if effect, ok := item.PossibleEffects[0].(*EffectInstance); !ok || effect.EffectId != 10 {
+		t.Errorf("possibleEffects[0] = %#v, want an EffectInstance of effect 10", item.PossibleEffects[0])
+	}
+	want := EffectInstanceDice{EffectInstance: EffectInstance{EffectId: 20}, DiceNum: 2, DiceSide: 6}
+	if dice, ok := item.PossibleEffects[1].(*EffectInstanceDice); !ok || *dice != want {
+		t.Errorf("possibleEffects[1] = %#v, want %#v", item.PossibleEffects[1], want)
+	}
+	if item.PossibleEffects[2] != nil {
+		t.Errorf("possibleEffects[2] = %#v, want nil", item.PossibleEffects[2])
+	}
+	if item.PossibleEffects[1].GetEffectInstance().EffectId != 20 {
+		t.Errorf("possibleEffects[1] is not handled as an EffectInstance")
+	}
+
+	want = EffectInstanceDice{EffectInstance: EffectInstance{EffectId: 30}, DiceNum: 1, DiceSide: 4}
+	if dice, ok := item.BestEffect.(*EffectInstanceDice); !ok || *dice != want {
+		t.Errorf("bestEffect = %#v, want %#v", item.BestEffect, want)
+	}
+}
+
+func TestDecodeUnregisteredClass(t *testing.T) {
+	object := parser.Object(map[string]any{
+		"ClassType_":      "Item",
+		"possibleEffects": []any{map[string]any{"ClassType_": "EffectInstanceDate", "effectId": 10}},
+	})
+
+	_, err := newRegistry().Decode(object)
+	if err == nil {
+		t.Fatal("decoding an object of an unregistered class succeeded")
+	}
+}
@@ -0,0 +1,139 @@
+package writer
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/brequet/dofus-data-file-parser/pkg/parser"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// WriteD2iFile writes translations to a D2I file, without named texts, the
+// undiacritical variant of the texts being generated.
+func WriteD2iFile(translations parser.Translations, d2iFilePath string) error {
+	data := parser.D2iData{Texts: make(map[int]parser.Text, len(translations))}
+	for id, text := range translations {
+		undiacriticalText := removeDiacritics(text)
+		if undiacriticalText == text {
+			undiacriticalText = ""
+		}
+		data.Texts[id] = parser.Text{Text: text, UndiacriticalText: undiacriticalText}
+	}
+	return WriteD2iData(data, d2iFilePath)
+}
+
+// WriteD2iData writes texts and named texts to a D2I file, keeping the
+// undiacritical variants of the texts as they are, e.g. as parsed.
+func WriteD2iData(data parser.D2iData, d2iFilePath string) error {
+	fileContent, err := buildD2i(data)
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(d2iFilePath, fileContent, 0644)
+	if err != nil {
+		return fmt.Errorf("error writing file: %w", err)
+	}
+
+	return nil
+}
+
+func buildD2i(d2iData parser.D2iData) ([]byte, error) {
+	// See I18nFileAccessor.as
+	ids := make([]int, 0, len(d2iData.Texts))
+	for id := range d2iData.Texts {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	data := NewDataOutput()
+	indexes := NewDataOutput()
+	const headerLength = 4
+
+	undiacriticalTexts := make(map[int]string, len(ids))
+	for _, id := range ids {
+		text := d2iData.Texts[id]
+		diacriticExists := text.UndiacriticalText != ""
+		undiacriticalTexts[id] = text.Text
+		if diacriticExists {
+			undiacriticalTexts[id] = text.UndiacriticalText
+		}
+
+		pointer := headerLength + data.Len()
+		data.WriteUTF(text.Text)
+		if err := data.Err(); err != nil {
+			return nil, fmt.Errorf("error writing text %d: %w", id, err)
+		}
+
+		indexes.WriteInt(id)
+		indexes.WriteBoolean(diacriticExists)
+		indexes.WriteInt(pointer)
+
+		if diacriticExists {
+			undiacriticalPointer := headerLength + data.Len()
+			data.WriteUTF(text.UndiacriticalText)
+			if err := data.Err(); err != nil {
+				return nil, fmt.Errorf("error writing undiacritical text %d: %w", id, err)
+			}
+			indexes.WriteInt(undiacriticalPointer)
+		}
+	}
+
+	keys := make([]string, 0, len(d2iData.NamedTexts))
+	for key := range d2iData.NamedTexts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	namedIndexes := NewDataOutput()
+	for _, key := range keys {
+		pointer := headerLength + data.Len()
+		data.WriteUTF(d2iData.NamedTexts[key])
+		if err := data.Err(); err != nil {
+			return nil, fmt.Errorf("error writing named text %s: %w", key, err)
+		}
+
+		namedIndexes.WriteUTF(key)
+		namedIndexes.WriteInt(pointer)
+	}
+
+	sortedIds := append([]int(nil), ids...)
+	sort.SliceStable(sortedIds, func(i, j int) bool {
+		return strings.ToLower(undiacriticalTexts[sortedIds[i]]) < strings.ToLower(undiacriticalTexts[sortedIds[j]])
+	})
+
+	fileContent := NewDataOutput()
+	fileContent.WriteInt(headerLength + data.Len())
+	fileContent.Write(data.Bytes())
+
+	fileContent.WriteInt(indexes.Len())
+	fileContent.Write(indexes.Bytes())
+
+	fileContent.WriteInt(namedIndexes.Len())
+	fileContent.Write(namedIndexes.Bytes())
+
+	fileContent.WriteInt(len(sortedIds) * 4)
+	for _, id := range sortedIds {
+		fileContent.WriteInt(id)
+	}
+
+	if err := errors.Join(indexes.Err(), namedIndexes.Err(), fileContent.Err()); err != nil {
+		return nil, fmt.Errorf("error writing indexes: %w", err)
+	}
+	return fileContent.Bytes(), nil
+}
+
+func removeDiacritics(text string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	result, _, err := transform.String(t, text)
+	if err != nil {
+		return text
+	}
+	return result
+}
@@ -0,0 +1,147 @@
+package writer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// DataOutput writes big endian values to a buffer, as parser.DataInput reads
+// them. Writing a value which cannot be encoded does not panic: it records an
+// error, returned by Err, and the following writes are ignored.
+type DataOutput struct {
+	buffer bytes.Buffer
+	err    error
+}
+
+func NewDataOutput() *DataOutput {
+	return &DataOutput{}
+}
+
+// Err returns the first error encountered while writing.
+func (do *DataOutput) Err() error {
+	return do.err
+}
+
+// Bytes returns the written bytes.
+func (do *DataOutput) Bytes() []byte {
+	return do.buffer.Bytes()
+}
+
+// Len returns the number of written bytes.
+func (do *DataOutput) Len() int {
+	return do.buffer.Len()
+}
+
+func (do *DataOutput) Write(data []byte) {
+	if do.err == nil {
+		do.buffer.Write(data)
+	}
+}
+
+func (do *DataOutput) WriteInt(value int) {
+	if value < math.MinInt32 || value > math.MaxInt32 {
+		do.setErr(fmt.Errorf("int out of range: %d", value))
+		return
+	}
+	do.Write(binary.BigEndian.AppendUint32(nil, uint32(value)))
+}
+
+func (do *DataOutput) WriteUint(value uint) {
+	if value > math.MaxUint32 {
+		do.setErr(fmt.Errorf("uint out of range: %d", value))
+		return
+	}
+	do.Write(binary.BigEndian.AppendUint32(nil, uint32(value)))
+}
+
+func (do *DataOutput) WriteLong(value int64) {
+	do.Write(binary.BigEndian.AppendUint64(nil, uint64(value)))
+}
+
+func (do *DataOutput) WriteShort(value int16) {
+	do.WriteUnsignedShort(uint16(value))
+}
+
+func (do *DataOutput) WriteUnsignedShort(value uint16) {
+	do.Write(binary.BigEndian.AppendUint16(nil, value))
+}
+
+// WriteSignedByte writes a signed byte, WriteByte in AS3.
+func (do *DataOutput) WriteSignedByte(value int8) {
+	do.WriteUnsignedByte(uint8(value))
+}
+
+func (do *DataOutput) WriteUnsignedByte(value uint8) {
+	do.Write([]byte{value})
+}
+
+func (do *DataOutput) WriteBoolean(value bool) {
+	if value {
+		do.WriteUnsignedByte(1)
+	} else {
+		do.WriteUnsignedByte(0)
+	}
+}
+
+func (do *DataOutput) WriteFloat(value float32) {
+	do.Write(binary.BigEndian.AppendUint32(nil, math.Float32bits(value)))
+}
+
+func (do *DataOutput) WriteDouble(value float64) {
+	do.Write(binary.BigEndian.AppendUint64(nil, math.Float64bits(value)))
+}
+
+// WriteUTF writes a string prefixed by its unsigned short length.
+func (do *DataOutput) WriteUTF(value string) {
+	if len(value) > math.MaxUint16 {
+		do.setErr(fmt.Errorf("string too long: %d bytes", len(value)))
+		return
+	}
+	do.WriteUnsignedShort(uint16(len(value)))
+	do.Write([]byte(value))
+}
+
+// WriteVarInt writes a 32 bits integer on 1 to 5 bytes of 7 bits.
+func (do *DataOutput) WriteVarInt(value int) {
+	if value < math.MinInt32 || value > math.MaxUint32 {
+		do.setErr(fmt.Errorf("var int out of range: %d", value))
+		return
+	}
+	do.writeVar(uint64(uint32(value)))
+}
+
+func (do *DataOutput) WriteVarUhInt(value int) {
+	do.WriteVarInt(value)
+}
+
+// WriteVarShort writes a 16 bits integer on 1 to 3 bytes of 7 bits.
+func (do *DataOutput) WriteVarShort(value int16) {
+	do.writeVar(uint64(uint16(value)))
+}
+
+func (do *DataOutput) WriteVarUhShort(value uint16) {
+	do.writeVar(uint64(value))
+}
+
+// WriteVarLong writes a 64 bits integer on 1 to 10 bytes of 7 bits.
+func (do *DataOutput) WriteVarLong(value int64) {
+	do.writeVar(uint64(value))
+}
+
+func (do *DataOutput) WriteVarUhLong(value uint64) {
+	do.writeVar(value)
+}
+
+// writeVar writes an integer on bytes of 7 bits, the least significant first,
+// whose most significant bit tells whether another byte follows.
+func (do *DataOutput) writeVar(value uint64) {
+	do.Write(binary.AppendUvarint(nil, value))
+}
+
+func (do *DataOutput) setErr(err error) {
+	if do.err == nil {
+		do.err = err
+	}
+}